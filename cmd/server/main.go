@@ -81,6 +81,12 @@ func run() error {
 	r := router.New(cfg, db)
 	r.StartDispatcher()
 	defer r.StopDispatcher()
+	r.StartSendQueue()
+	defer r.StopSendQueue()
+	r.StartScheduler()
+	defer r.StopScheduler()
+	r.StartCampaigns()
+	defer r.StopCampaigns()
 
 	go scheduleReconnect(ctx, r)
 
@@ -263,6 +263,69 @@ const docTemplate = `{
                 }
             }
         },
+        "/chatwoot/{sessionId}/webhook": {
+            "post": {
+                "description": "Receives the webhook of the Chatwoot inbox, authenticated by the token of webhookPath. Public outgoing messages of agents are sent to the chat of their conversation; other events are acknowledged and ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chatwoot"
+                ],
+                "summary": "Chatwoot webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Chatwoot event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chatwoot.WebhookEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "produces": [
@@ -518,51 +581,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/call/reject": {
-            "post": {
+        "/sessions/{sessionId}/assignments": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Assignments"
                 ],
-                "summary": "Reject call",
+                "summary": "List chat assignments",
                 "parameters": [
-                    {
-                        "description": "Call rejection data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RejectCallRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (open, pending, closed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by agent",
+                        "name": "agent_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ChatAssignment"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -571,24 +636,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/chat/archive": {
-            "post": {
+        "/sessions/{sessionId}/assignments/{chat}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "archive=true/false",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Assignments"
                 ],
-                "summary": "Archive chat",
+                "summary": "Get chat assignment",
                 "parameters": [
                     {
                         "type": "string",
@@ -598,25 +659,22 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Archive data",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.ArchiveChatMessage"
-                        }
+                        "type": "string",
+                        "description": "Chat JID or phone",
+                        "name": "chat",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ChatAssignment"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -625,14 +683,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/chat/downloadaudio": {
+        "/sessions/{sessionId}/assignments/{chat}/assign": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns base64",
+                "description": "Assigns an unassigned, pending or closed chat to an agent and emits ChatAssigned. Chats another agent is handling must be transferred",
                 "consumes": [
                     "application/json"
                 ],
@@ -640,9 +698,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Assignments"
                 ],
-                "summary": "Download audio",
+                "summary": "Assign chat",
                 "parameters": [
                     {
                         "type": "string",
@@ -652,12 +710,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Download data",
-                        "name": "message",
+                        "type": "string",
+                        "description": "Chat JID or phone",
+                        "name": "chat",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assignment",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DownloadMediaMessage"
+                            "$ref": "#/definitions/model.ChatAssignRequest"
                         }
                     }
                 ],
@@ -665,8 +730,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ChatAssignment"
                         }
                     },
                     "400": {
@@ -675,18 +739,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/chat/downloaddocument": {
+        "/sessions/{sessionId}/assignments/{chat}/close": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns base64",
+                "description": "Closes a chat and emits ChatClosed. The chat is opened again when the contact writes",
                 "consumes": [
                     "application/json"
                 ],
@@ -694,9 +765,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Assignments"
                 ],
-                "summary": "Download document",
+                "summary": "Close chat",
                 "parameters": [
                     {
                         "type": "string",
@@ -706,25 +777,37 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Download data",
-                        "name": "message",
+                        "type": "string",
+                        "description": "Chat JID or phone",
+                        "name": "chat",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Close options",
+                        "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DownloadMediaMessage"
+                            "$ref": "#/definitions/model.ChatCloseRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChatAssignment"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -733,14 +816,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/chat/downloadimage": {
+        "/sessions/{sessionId}/assignments/{chat}/transfer": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns base64",
+                "description": "Moves an open or pending chat to another agent and emits ChatAssigned",
                 "consumes": [
                     "application/json"
                 ],
@@ -748,9 +831,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Assignments"
                 ],
-                "summary": "Download image",
+                "summary": "Transfer chat",
                 "parameters": [
                     {
                         "type": "string",
@@ -760,12 +843,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Download data",
-                        "name": "message",
+                        "type": "string",
+                        "description": "Chat JID or phone",
+                        "name": "chat",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assignment",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DownloadMediaMessage"
+                            "$ref": "#/definitions/model.ChatAssignRequest"
                         }
                     }
                 ],
@@ -773,8 +863,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ChatAssignment"
                         }
                     },
                     "400": {
@@ -783,28 +872,38 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/chat/downloadsticker": {
-            "post": {
+        "/sessions/{sessionId}/autoreplies": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns base64",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "AutoReplies"
                 ],
-                "summary": "Download sticker",
+                "summary": "List auto-replies",
                 "parameters": [
                     {
                         "type": "string",
@@ -812,43 +911,34 @@ const docTemplate = `{
                         "name": "sessionId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Download data",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.DownloadMediaMessage"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AutoReply"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/chat/downloadvideo": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns base64",
+                "description": "Creates a rule that answers incoming messages matching its text pattern, chat type, sender lists and business hours. Rules are tried by descending priority; the first match answers unless it answered the same chat within its cooldown",
                 "consumes": [
                     "application/json"
                 ],
@@ -856,9 +946,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "AutoReplies"
                 ],
-                "summary": "Download video",
+                "summary": "Create auto-reply",
                 "parameters": [
                     {
                         "type": "string",
@@ -868,12 +958,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Download data",
-                        "name": "message",
+                        "description": "Auto-reply rule",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DownloadMediaMessage"
+                            "$ref": "#/definitions/model.AutoReplyRequest"
                         }
                     }
                 ],
@@ -881,8 +971,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.AutoReply"
                         }
                     },
                     "400": {
@@ -895,23 +984,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/chat/markread": {
-            "post": {
+        "/sessions/{sessionId}/autoreplies/{ruleId}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "AutoReplies"
                 ],
-                "summary": "Mark as read",
+                "summary": "Get auto-reply",
                 "parameters": [
                     {
                         "type": "string",
@@ -921,41 +1007,35 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Mark read data",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.MarkReadMessage"
-                        }
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.AutoReply"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/chat/presence": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "state: composing/paused",
                 "consumes": [
                     "application/json"
                 ],
@@ -963,44 +1043,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "AutoReplies"
                 ],
-                "summary": "Chat presence",
+                "summary": "Update auto-reply",
                 "parameters": [
-                    {
-                        "description": "Chat presence data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "media": {
-                                    "type": "string"
-                                },
-                                "phone": {
-                                    "type": "string"
-                                },
-                                "state": {
-                                    "type": "string"
-                                }
-                            }
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Auto-reply rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AutoReplyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.AutoReply"
                         }
                     },
                     "400": {
@@ -1009,28 +1084,29 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/connect": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "immediate=false waits 10s for verification",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "AutoReplies"
                 ],
-                "summary": "Connect session",
+                "summary": "Delete auto-reply",
                 "parameters": [
                     {
                         "type": "string",
@@ -1040,12 +1116,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Connection options",
-                        "name": "request",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/model.SessionConnectRequest"
-                        }
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1056,22 +1131,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1080,20 +1141,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/disconnect": {
-            "post": {
+        "/sessions/{sessionId}/call/log": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Returns the latest 100 calls rejected by the call policy",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Calls"
                 ],
-                "summary": "Disconnect session",
+                "summary": "List rejected calls",
                 "parameters": [
                     {
                         "type": "string",
@@ -1107,8 +1169,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CallLog"
+                            }
                         }
                     },
                     "401": {
@@ -1121,34 +1185,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/announce": {
-            "post": {
+        "/sessions/{sessionId}/call/policy": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Only admins send when true",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Calls"
                 ],
-                "summary": "Set announce mode",
+                "summary": "Get call policy",
                 "parameters": [
-                    {
-                        "description": "Group announce data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.GroupAnnounceRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -1161,27 +1212,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.CallPolicy"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/group/create": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Rejects incoming calls automatically. Mode \"always\" rejects every call, \"hours\" rejects calls while window matches and \"non_contacts\" rejects callers that are not in the address book. When message is set it is sent to the caller after the call is rejected. Rejected calls are logged and emitted as CallRejected",
                 "consumes": [
                     "application/json"
                 ],
@@ -1189,30 +1238,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Calls"
                 ],
-                "summary": "Create group",
+                "summary": "Set call policy",
                 "parameters": [
                     {
-                        "description": "Group data",
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Call policy",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "name": {
-                                    "type": "string"
-                                },
-                                "participants": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
+                            "$ref": "#/definitions/model.CallPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CallPolicy"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops rejecting calls automatically",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calls"
+                ],
+                "summary": "Delete call policy",
+                "parameters": [
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -1229,8 +1306,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1239,14 +1316,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/ephemeral": {
+        "/sessions/{sessionId}/call/reject": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "duration: 24h/7d/90d/off",
                 "consumes": [
                     "application/json"
                 ],
@@ -1254,17 +1330,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "User"
                 ],
-                "summary": "Set ephemeral",
+                "summary": "Reject call",
                 "parameters": [
                     {
-                        "description": "Group ephemeral data",
+                        "description": "Call rejection data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.GroupEphemeralRequest"
+                            "$ref": "#/definitions/model.RejectCallRequest"
                         }
                     },
                     {
@@ -1293,7 +1369,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/info": {
+        "/sessions/{sessionId}/campaigns": {
             "get": {
                 "security": [
                     {
@@ -1304,17 +1380,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Get group info",
+                "summary": "List campaigns",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Group JID",
-                        "name": "jid",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -1327,27 +1396,28 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Campaign"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/group/inviteinfo": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Creates a draft campaign. Payload follows the scheduled message format for the given kind, or kind is template with template_id; strings may use {{name}} placeholders filled from each recipient's variables, and the phone is set per recipient. Malformed phones are skipped and listed in rejected",
                 "consumes": [
                     "application/json"
                 ],
@@ -1355,33 +1425,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Get invite info",
+                "summary": "Create campaign",
                 "parameters": [
-                    {
-                        "description": "Group invite info request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.GroupInviteInfoRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Campaign data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CampaignCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Campaign"
                         }
                     },
                     "400": {
@@ -1394,32 +1463,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/invitelink": {
+        "/sessions/{sessionId}/campaigns/{campaignId}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Returns the campaign with its progress counters",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Get invite link",
+                "summary": "Get campaign",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group JID",
-                        "name": "jid",
-                        "in": "query",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Session ID",
-                        "name": "sessionId",
+                        "description": "Campaign ID",
+                        "name": "campaignId",
                         "in": "path",
                         "required": true
                     }
@@ -1428,12 +1498,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Campaign"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1442,47 +1511,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/join": {
+        "/sessions/{sessionId}/campaigns/{campaignId}/cancel": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Recipients not sent yet are left pending",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Join group",
+                "summary": "Cancel campaign",
                 "parameters": [
-                    {
-                        "description": "Group join data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.GroupJoinRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaignId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Campaign"
                         }
                     },
                     "400": {
@@ -1495,52 +1559,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/leave": {
+        "/sessions/{sessionId}/campaigns/{campaignId}/pause": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Leave group",
+                "summary": "Pause campaign",
                 "parameters": [
-                    {
-                        "description": "Group JID",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "jid": {
-                                    "type": "string"
-                                }
-                            }
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaignId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Campaign"
                         }
                     },
                     "400": {
@@ -1553,20 +1606,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/list": {
+        "/sessions/{sessionId}/campaigns/{campaignId}/recipients": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Returns up to 500 recipients in list order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "List groups",
+                "summary": "List campaign recipients",
                 "parameters": [
                     {
                         "type": "string",
@@ -1574,60 +1628,87 @@ const docTemplate = `{
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaignId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, sending, sent, failed, invalid)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of recipients to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CampaignRecipient"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/group/locked": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Only admins edit when true",
+                "description": "Accepts a JSON body or a text/csv body with a header row. The CSV phone column is required and every other column becomes a variable. Duplicate and malformed numbers are skipped",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Set locked mode",
+                "summary": "Add campaign recipients",
                 "parameters": [
-                    {
-                        "description": "Group locked data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.GroupLockedRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaignId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recipients",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CampaignRecipientsRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1648,55 +1729,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/name": {
+        "/sessions/{sessionId}/campaigns/{campaignId}/resume": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Set name",
+                "summary": "Resume campaign",
                 "parameters": [
-                    {
-                        "description": "Group name data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "jid": {
-                                    "type": "string"
-                                },
-                                "name": {
-                                    "type": "string"
-                                }
-                            }
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaignId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Campaign"
                         }
                     },
                     "400": {
@@ -1709,48 +1776,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/photo": {
+        "/sessions/{sessionId}/campaigns/{campaignId}/start": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Base64 image required",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Starts sending a draft campaign, respecting its rate and time window",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Campaigns"
                 ],
-                "summary": "Set photo",
+                "summary": "Start campaign",
                 "parameters": [
-                    {
-                        "description": "Group photo data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.GroupPhotoRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaignId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Campaign"
                         }
                     },
                     "400": {
@@ -1763,13 +1824,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/photo/remove": {
+        "/sessions/{sessionId}/chat/archive": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "archive=true/false",
                 "consumes": [
                     "application/json"
                 ],
@@ -1777,30 +1839,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Chat"
                 ],
-                "summary": "Remove photo",
+                "summary": "Archive chat",
                 "parameters": [
-                    {
-                        "description": "Group JID",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "jid": {
-                                    "type": "string"
-                                }
-                            }
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Archive data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ArchiveChatMessage"
+                        }
                     }
                 ],
                 "responses": {
@@ -1821,13 +1878,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/group/topic": {
+        "/sessions/{sessionId}/chat/clear": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Clears the messages of the chat but keeps the chat. Starred messages are kept when keep_starred is set",
                 "consumes": [
                     "application/json"
                 ],
@@ -1835,33 +1893,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Chat"
                 ],
-                "summary": "Set topic",
+                "summary": "Clear chat",
                 "parameters": [
-                    {
-                        "description": "Group topic data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "jid": {
-                                    "type": "string"
-                                },
-                                "topic": {
-                                    "type": "string"
-                                }
-                            }
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Chat",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ClearChatMessage"
+                        }
                     }
                 ],
                 "responses": {
@@ -1878,18 +1928,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/group/updateparticipants": {
+        "/sessions/{sessionId}/chat/delete": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "action: add/remove/promote/demote",
+                "description": "Deletes the chat and its messages from the linked devices",
                 "consumes": [
                     "application/json"
                 ],
@@ -1897,39 +1954,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Chat"
                 ],
-                "summary": "Update participants",
+                "summary": "Delete chat",
                 "parameters": [
-                    {
-                        "description": "Participants data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "action": {
-                                    "type": "string"
-                                },
-                                "jid": {
-                                    "type": "string"
-                                },
-                                "participants": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Chat",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteChatMessage"
+                        }
                     }
                 ],
                 "responses": {
@@ -1946,25 +1989,35 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/logout": {
+        "/sessions/{sessionId}/chat/downloadaudio": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Clears session credentials",
+                "description": "Returns base64. Use GET /chat/media to stream large files.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Chat"
                 ],
-                "summary": "Logout session",
+                "summary": "Download audio",
                 "parameters": [
                     {
                         "type": "string",
@@ -1972,6 +2025,15 @@ const docTemplate = `{
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Download data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DownloadMediaMessage"
+                        }
                     }
                 ],
                 "responses": {
@@ -1982,9 +2044,9 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
@@ -1992,14 +2054,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/audio": {
+        "/sessions/{sessionId}/chat/downloaddocument": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Base64 data:audio/* required",
+                "description": "Returns base64. Use GET /chat/media to stream large files.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2007,9 +2069,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send audio",
+                "summary": "Download document",
                 "parameters": [
                     {
                         "type": "string",
@@ -2019,12 +2081,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Audio data",
+                        "description": "Download data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.AudioMessage"
+                            "$ref": "#/definitions/model.DownloadMediaMessage"
                         }
                     }
                 ],
@@ -2046,14 +2108,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/buttons": {
+        "/sessions/{sessionId}/chat/downloadimage": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Experimental, 1-3 buttons",
+                "description": "Returns base64. Use GET /chat/media to stream large files.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2061,9 +2123,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send buttons",
+                "summary": "Download image",
                 "parameters": [
                     {
                         "type": "string",
@@ -2073,12 +2135,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Buttons data",
+                        "description": "Download data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.ButtonsMessage"
+                            "$ref": "#/definitions/model.DownloadMediaMessage"
                         }
                     }
                 ],
@@ -2100,14 +2162,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/contact": {
+        "/sessions/{sessionId}/chat/downloadsticker": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Requires vCard format",
+                "description": "Returns base64. Use GET /chat/media to stream large files.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2115,9 +2177,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send contact",
+                "summary": "Download sticker",
                 "parameters": [
                     {
                         "type": "string",
@@ -2127,12 +2189,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Contact data",
+                        "description": "Download data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.ContactMessage"
+                            "$ref": "#/definitions/model.DownloadMediaMessage"
                         }
                     }
                 ],
@@ -2154,14 +2216,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/delete": {
+        "/sessions/{sessionId}/chat/downloadvideo": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Revokes own messages",
+                "description": "Returns base64. Use GET /chat/media to stream large files.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2169,9 +2231,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Delete message",
+                "summary": "Download video",
                 "parameters": [
                     {
                         "type": "string",
@@ -2181,12 +2243,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Delete data",
+                        "description": "Download data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DeleteMessage"
+                            "$ref": "#/definitions/model.DownloadMediaMessage"
                         }
                     }
                 ],
@@ -2208,14 +2270,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/document": {
+        "/sessions/{sessionId}/chat/markread": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Base64 with filename required",
                 "consumes": [
                     "application/json"
                 ],
@@ -2223,9 +2284,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send document",
+                "summary": "Mark as read",
                 "parameters": [
                     {
                         "type": "string",
@@ -2235,12 +2296,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Document data",
+                        "description": "Mark read data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DocumentMessage"
+                            "$ref": "#/definitions/model.MarkReadMessage"
                         }
                     }
                 ],
@@ -2262,23 +2323,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/edit": {
+        "/sessions/{sessionId}/chat/media": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Streams decrypted media as a binary response, addressed by the media descriptor fields. Byte fields are base64 encoded.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Edit message",
+                "summary": "Stream media",
                 "parameters": [
                     {
                         "type": "string",
@@ -2288,12 +2350,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Edit data",
+                        "description": "Media descriptor",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.EditMessage"
+                            "$ref": "#/definitions/model.DownloadMediaMessage"
                         }
                     }
                 ],
@@ -2301,8 +2363,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -2315,24 +2376,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/image": {
-            "post": {
+        "/sessions/{sessionId}/chat/media/{messageId}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Base64 data:image/* required",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Streams the decrypted media of a message kept in the message store as a binary response.",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send image",
+                "summary": "Stream stored message media",
                 "parameters": [
                     {
                         "type": "string",
@@ -2342,21 +2400,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Image data",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.ImageMessage"
-                        }
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -2365,17 +2420,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/messages/list": {
+        "/sessions/{sessionId}/chat/mute": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "mute=true/false. duration_seconds limits the mute, 0 mutes forever",
                 "consumes": [
                     "application/json"
                 ],
@@ -2383,9 +2446,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send list",
+                "summary": "Mute chat",
                 "parameters": [
                     {
                         "type": "string",
@@ -2395,12 +2458,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "List data",
+                        "description": "Mute data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.ListMessage"
+                            "$ref": "#/definitions/model.MuteChatMessage"
                         }
                     }
                 ],
@@ -2418,17 +2481,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/messages/location": {
+        "/sessions/{sessionId}/chat/pin": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "pin=true/false",
                 "consumes": [
                     "application/json"
                 ],
@@ -2436,9 +2507,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send location",
+                "summary": "Pin chat",
                 "parameters": [
                     {
                         "type": "string",
@@ -2448,12 +2519,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Location data",
+                        "description": "Pin data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.LocationMessage"
+                            "$ref": "#/definitions/model.PinChatMessage"
                         }
                     }
                 ],
@@ -2471,18 +2542,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/messages/poll": {
+        "/sessions/{sessionId}/chat/presence": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Min 2 options required",
+                "description": "state: composing/paused",
                 "consumes": [
                     "application/json"
                 ],
@@ -2490,25 +2568,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "User"
                 ],
-                "summary": "Send poll",
+                "summary": "Chat presence",
                 "parameters": [
+                    {
+                        "description": "Chat presence data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "media": {
+                                    "type": "string"
+                                },
+                                "phone": {
+                                    "type": "string"
+                                },
+                                "state": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Poll data",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.PollMessage"
-                        }
                     }
                 ],
                 "responses": {
@@ -2529,13 +2618,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/messages/reaction": {
+        "/sessions/{sessionId}/chat/read": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "read=true/false. Only changes the unread mark on the linked devices; use markread to send read receipts",
                 "consumes": [
                     "application/json"
                 ],
@@ -2543,9 +2633,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "React to message",
+                "summary": "Mark chat as read or unread",
                 "parameters": [
                     {
                         "type": "string",
@@ -2555,12 +2645,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Reaction data",
+                        "description": "Read data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.ReactionMessage"
+                            "$ref": "#/definitions/model.ReadChatMessage"
                         }
                     }
                 ],
@@ -2578,18 +2668,25 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/messages/sticker": {
+        "/sessions/{sessionId}/chat/star": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "WebP format required",
+                "description": "star=true/false. Messages received in groups need sender_phone",
                 "consumes": [
                     "application/json"
                 ],
@@ -2597,9 +2694,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chat"
                 ],
-                "summary": "Send sticker",
+                "summary": "Star message",
                 "parameters": [
                     {
                         "type": "string",
@@ -2609,12 +2706,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Sticker data",
+                        "description": "Star data",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.StickerMessage"
+                            "$ref": "#/definitions/model.StarMessage"
                         }
                     }
                 ],
@@ -2632,27 +2729,31 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/messages/text": {
-            "post": {
+        "/sessions/{sessionId}/chatwoot": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chatwoot"
                 ],
-                "summary": "Send text",
+                "summary": "Get Chatwoot integration",
                 "parameters": [
                     {
                         "type": "string",
@@ -2660,43 +2761,31 @@ const docTemplate = `{
                         "name": "sessionId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Message data",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.TextMessage"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ChatwootConfig"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/messages/video": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Base64 data:video/* required",
+                "description": "Connects the session to an API channel inbox of Chatwoot. Incoming messages and their attachments are posted to a conversation of the contact, created on the first message of a chat. Set the webhook of the inbox to the public URL of this API followed by webhookPath so agent replies are sent to the chat. Group chats are skipped unless ignore_groups is false",
                 "consumes": [
                     "application/json"
                 ],
@@ -2704,9 +2793,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Messages"
+                    "Chatwoot"
                 ],
-                "summary": "Send video",
+                "summary": "Set Chatwoot integration",
                 "parameters": [
                     {
                         "type": "string",
@@ -2716,12 +2805,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Video data",
-                        "name": "message",
+                        "description": "Chatwoot integration",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.VideoMessage"
+                            "$ref": "#/definitions/model.ChatwootConfigRequest"
                         }
                     }
                 ],
@@ -2729,8 +2818,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ChatwootConfig"
                         }
                     },
                     "400": {
@@ -2741,35 +2829,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/newsletter/create": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Stops bridging the session and forgets its Chatwoot conversations",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Chatwoot"
                 ],
-                "summary": "Create newsletter",
+                "summary": "Delete Chatwoot integration",
                 "parameters": [
-                    {
-                        "description": "Newsletter details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.NewsletterCreateRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -2786,8 +2861,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2796,13 +2871,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/newsletter/follow": {
+        "/sessions/{sessionId}/connect": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "immediate=false waits 10s for verification",
                 "consumes": [
                     "application/json"
                 ],
@@ -2810,28 +2886,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Sessions"
                 ],
-                "summary": "Follow",
+                "summary": "Connect session",
                 "parameters": [
-                    {
-                        "description": "Newsletter JID",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.NewsletterFollowRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
+                    },
+                    {
+                        "description": "Connection options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.SessionConnectRequest"
+                        }
+                    }
+                ],
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
@@ -2845,11 +2920,32 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/sessions/{sessionId}/newsletter/info": {
+        "/sessions/{sessionId}/connector": {
             "get": {
                 "security": [
                     {
@@ -2860,17 +2956,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Connector"
                 ],
-                "summary": "Get info",
+                "summary": "Get connector",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Newsletter JID",
-                        "name": "jid",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -2883,56 +2972,58 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Connector"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/newsletter/info/invite": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Calls url for the incoming messages of the session and runs the actions it answers with: text, media, react, read and typing. Messages of a chat arriving within debounce_ms of each other are sent in one call. When the call fails or times out, fallback_message is sent to the chat. Group chats are skipped unless ignore_groups is false",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Connector"
                 ],
-                "summary": "Get info by invite",
+                "summary": "Set connector",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Invite key/code",
-                        "name": "key",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Connector",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ConnectorRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Connector"
                         }
                     },
                     "400": {
@@ -2943,22 +3034,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/newsletter/list": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Stops calling the connector for incoming messages",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Connector"
                 ],
-                "summary": "List newsletters",
+                "summary": "Delete connector",
                 "parameters": [
                     {
                         "type": "string",
@@ -2976,8 +3066,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2986,34 +3076,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/newsletter/liveupdates": {
+        "/sessions/{sessionId}/disconnect": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns subscription duration",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Sessions"
                 ],
-                "summary": "Live updates",
+                "summary": "Disconnect session",
                 "parameters": [
-                    {
-                        "description": "Newsletter JID",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.NewsletterFollowRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -3030,8 +3107,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3040,33 +3117,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/newsletter/markviewed": {
-            "post": {
+        "/sessions/{sessionId}/flow": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Flows"
                 ],
-                "summary": "Mark viewed",
+                "summary": "Get flow",
                 "parameters": [
-                    {
-                        "description": "Mark viewed request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.NewsletterMarkViewedRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -3079,70 +3144,59 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Flow"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/newsletter/messages": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "count/before for pagination",
+                "description": "Replaces the conversational flow of the session, a state machine run on incoming messages and list or button replies. The definition is sent as JSON, or as YAML with a YAML content type. Running conversations continue in the new definition",
+                "consumes": [
+                    "application/json",
+                    "application/x-yaml"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Flows"
                 ],
-                "summary": "Get messages",
+                "summary": "Set flow",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Newsletter JID",
-                        "name": "jid",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Number of messages to fetch",
-                        "name": "count",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Fetch messages before this server ID",
-                        "name": "before",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Flow definition",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FlowDefinition"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Flow"
                         }
                     },
                     "400": {
@@ -3153,36 +3207,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/newsletter/mute": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "mute=true/false",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Deletes the flow of the session and all its conversations",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Flows"
                 ],
-                "summary": "Mute newsletter",
+                "summary": "Delete flow",
                 "parameters": [
-                    {
-                        "description": "Mute request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.NewsletterMuteRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -3199,8 +3239,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3209,51 +3249,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/newsletter/reaction": {
-            "post": {
+        "/sessions/{sessionId}/flow/states": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Flows"
                 ],
-                "summary": "Send reaction",
+                "summary": "List flow conversations",
                 "parameters": [
-                    {
-                        "description": "Reaction request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.NewsletterReactionRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (active, handoff)",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.FlowState"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3262,77 +3298,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/newsletter/unfollow": {
-            "post": {
+        "/sessions/{sessionId}/flow/states/{chat}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Flows"
                 ],
-                "summary": "Unfollow",
+                "summary": "Get flow conversation",
                 "parameters": [
-                    {
-                        "description": "Newsletter JID",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.NewsletterFollowRequest"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chat JID or phone",
+                        "name": "chat",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.FlowState"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{sessionId}/pairphone": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns 8-digit linking code",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Ends the conversation of a chat and hands it back from a human to the flow; the next message of the contact starts over",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Flows"
                 ],
-                "summary": "Pair phone",
+                "summary": "Reset flow conversation",
                 "parameters": [
                     {
                         "type": "string",
@@ -3342,13 +3367,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Phone number",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.PairPhoneRequest"
-                        }
+                        "type": "string",
+                        "description": "Chat JID or phone",
+                        "name": "chat",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3359,8 +3382,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3369,21 +3392,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/qr": {
-            "get": {
+        "/sessions/{sessionId}/group/announce": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Only admins send when true",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Group"
                 ],
-                "summary": "Get QR code",
+                "summary": "Set announce mode",
                 "parameters": [
+                    {
+                        "description": "Group announce data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupAnnounceRequest"
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -3400,8 +3436,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3410,21 +3446,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/status": {
-            "get": {
+        "/sessions/{sessionId}/group/create": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Group"
                 ],
-                "summary": "Get status",
+                "summary": "Create group",
                 "parameters": [
+                    {
+                        "description": "Group data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "name": {
+                                    "type": "string"
+                                },
+                                "participants": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -3441,8 +3500,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3451,13 +3510,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/status/text": {
+        "/sessions/{sessionId}/group/ephemeral": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "duration: 24h/7d/90d/off",
                 "consumes": [
                     "application/json"
                 ],
@@ -3465,25 +3525,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Status"
+                    "Group"
                 ],
-                "summary": "Set status",
+                "summary": "Set ephemeral",
                 "parameters": [
+                    {
+                        "description": "Group ephemeral data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupEphemeralRequest"
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Status text data",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.StatusTextMessage"
-                        }
                     }
                 ],
                 "responses": {
@@ -3504,41 +3564,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/user/avatar": {
-            "post": {
+        "/sessions/{sessionId}/group/info": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "preview=true for thumbnail",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Group"
                 ],
-                "summary": "Get avatar",
+                "summary": "Get group info",
                 "parameters": [
                     {
-                        "description": "Phone and preview option",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "phone": {
-                                    "type": "string"
-                                },
-                                "preview": {
-                                    "type": "boolean"
-                                }
-                            }
-                        }
+                        "type": "string",
+                        "description": "Group JID",
+                        "name": "jid",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -3566,14 +3612,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/user/check": {
+        "/sessions/{sessionId}/group/inviteinfo": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Verifies WhatsApp registration",
                 "consumes": [
                     "application/json"
                 ],
@@ -3581,25 +3626,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Group"
                 ],
-                "summary": "Check users",
+                "summary": "Get invite info",
                 "parameters": [
                     {
-                        "description": "Phone numbers",
+                        "description": "Group invite info request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "phone": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
+                            "$ref": "#/definitions/model.GroupInviteInfoRequest"
                         }
                     },
                     {
@@ -3628,7 +3665,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/user/contacts": {
+        "/sessions/{sessionId}/group/invitelink": {
             "get": {
                 "security": [
                     {
@@ -3639,10 +3676,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Group"
                 ],
-                "summary": "Get contacts",
+                "summary": "Get invite link",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group JID",
+                        "name": "jid",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Session ID",
@@ -3659,8 +3703,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -3669,27 +3713,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/user/getlid": {
-            "get": {
+        "/sessions/{sessionId}/group/join": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Group"
                 ],
-                "summary": "Get LID",
+                "summary": "Join group",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Phone number",
-                        "name": "phone",
-                        "in": "query",
-                        "required": true
+                        "description": "Group join data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupJoinRequest"
+                        }
                     },
                     {
                         "type": "string",
@@ -3717,7 +3766,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/user/info": {
+        "/sessions/{sessionId}/group/leave": {
             "post": {
                 "security": [
                     {
@@ -3731,23 +3780,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Group"
                 ],
-                "summary": "Get user info",
+                "summary": "Leave group",
                 "parameters": [
                     {
-                        "description": "Phone numbers",
+                        "description": "Group JID",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "phone": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "string"
-                                    }
+                                "jid": {
+                                    "type": "string"
                                 }
                             }
                         }
@@ -3778,7 +3824,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/user/newsletters": {
+        "/sessions/{sessionId}/group/list": {
             "get": {
                 "security": [
                     {
@@ -3789,9 +3835,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Group"
                 ],
-                "summary": "Get newsletters",
+                "summary": "List groups",
                 "parameters": [
                     {
                         "type": "string",
@@ -3819,14 +3865,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/user/presence": {
+        "/sessions/{sessionId}/group/locked": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "available/unavailable",
+                "description": "Only admins edit when true",
                 "consumes": [
                     "application/json"
                 ],
@@ -3834,22 +3880,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Group"
                 ],
-                "summary": "Send presence",
+                "summary": "Set locked mode",
                 "parameters": [
                     {
-                        "description": "Presence (available/unavailable)",
+                        "description": "Group locked data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "presence": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/model.GroupLockedRequest"
                         }
                     },
                     {
@@ -3878,53 +3919,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{sessionId}/webhook": {
-            "get": {
+        "/sessions/{sessionId}/group/name": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Webhook"
+                    "Group"
                 ],
-                "summary": "Get webhook",
+                "summary": "Set name",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Session name",
-                        "name": "sessionId",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
+                        "description": "Group name data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "jid": {
+                                    "type": "string"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/sessions/{sessionId}/group/photo": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "active=false to disable",
+                "description": "Base64 image required",
                 "consumes": [
                     "application/json"
                 ],
@@ -3932,39 +3995,83 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Webhook"
+                    "Group"
                 ],
-                "summary": "Update webhook",
+                "summary": "Set photo",
                 "parameters": [
+                    {
+                        "description": "Group photo data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupPhotoRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Session name",
+                        "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/group/photo/remove": {
+            "post": {
+                "security": [
                     {
-                        "description": "Webhook update data",
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Remove photo",
+                "parameters": [
+                    {
+                        "description": "Group JID",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "active": {
-                                    "type": "boolean"
-                                },
-                                "events": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "string"
-                                    }
-                                },
-                                "webhook": {
+                                "jid": {
                                     "type": "string"
                                 }
                             }
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3983,7 +4090,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/sessions/{sessionId}/group/topic": {
             "post": {
                 "security": [
                     {
@@ -3997,25 +4106,101 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Webhook"
+                    "Group"
                 ],
-                "summary": "Set webhook",
+                "summary": "Set topic",
                 "parameters": [
+                    {
+                        "description": "Group topic data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "jid": {
+                                    "type": "string"
+                                },
+                                "topic": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Session name",
+                        "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/group/updateparticipants": {
+            "post": {
+                "security": [
                     {
-                        "description": "Webhook configuration",
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "action: add/remove/promote/demote",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Update participants",
+                "parameters": [
+                    {
+                        "description": "Participants data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.WebhookRequest"
+                            "type": "object",
+                            "properties": {
+                                "action": {
+                                    "type": "string"
+                                },
+                                "jid": {
+                                    "type": "string"
+                                },
+                                "participants": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -4034,24 +4219,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/sessions/{sessionId}/logout": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Clears session credentials",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Webhook"
+                    "Sessions"
                 ],
-                "summary": "Delete webhook",
+                "summary": "Logout session",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session name",
+                        "description": "Session ID",
                         "name": "sessionId",
                         "in": "path",
                         "required": true
@@ -4074,474 +4262,5034 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.ArchiveChatMessage": {
-            "type": "object",
-            "properties": {
-                "archive": {
-                    "type": "boolean"
-                },
-                "phone": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.AudioMessage": {
-            "type": "object",
-            "properties": {
-                "audio": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "mimetype": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "ptt": {
-                    "type": "boolean"
-                }
-            }
         },
-        "model.ButtonItem": {
-            "type": "object",
-            "properties": {
-                "button_id": {
-                    "type": "string"
-                },
-                "button_text": {
-                    "type": "string"
-                }
-            }
+        "/sessions/{sessionId}/messages/audio": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Base64 data:audio/*, https URL or multipart upload",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send audio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Audio data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AudioMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/buttons": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Experimental, 1-3 buttons",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send buttons",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Buttons data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ButtonsMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/contact": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Requires vCard format",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send contact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Contact data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ContactMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/delete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes own messages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Delete message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delete data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/document": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Base64 (filename required), https URL or multipart upload",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Document data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DocumentMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/edit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Edit message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Edit data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.EditMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/image": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Base64 data:image/*, https URL or multipart upload",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ImageMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/list": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "List data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ListMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/location": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Location data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LocationMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/poll": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Min 2 options required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send poll",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Poll data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PollMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/reaction": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "React to message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reaction data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReactionMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/sticker": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "WebP as base64, https URL or multipart upload",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send sticker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sticker data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StickerMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/template": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Renders a template, chosen by template_id or template_name, with the given variables and sends it through the send endpoint of its kind. Requests missing a variable without a default are rejected. reply_to, mentions and queue work as on the other send endpoints.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TemplateSendRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/text": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "reply_to quotes a message, mentions @-mentions users (include @number in the text). link_preview fetches a preview card for the first https URL; preview supplies one explicitly. queue=true stores the message in the send queue and returns a queue_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send text",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TextMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/messages/video": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Base64 data:video/*, https URL or multipart upload",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Send video",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Video data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VideoMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/create": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Create newsletter",
+                "parameters": [
+                    {
+                        "description": "Newsletter details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NewsletterCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/follow": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Follow",
+                "parameters": [
+                    {
+                        "description": "Newsletter JID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NewsletterFollowRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/info": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Get info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Newsletter JID",
+                        "name": "jid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/info/invite": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Get info by invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite key/code",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/list": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "List newsletters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/liveupdates": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns subscription duration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Live updates",
+                "parameters": [
+                    {
+                        "description": "Newsletter JID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NewsletterFollowRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/markviewed": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Mark viewed",
+                "parameters": [
+                    {
+                        "description": "Mark viewed request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NewsletterMarkViewedRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/messages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "count/before for pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Get messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Newsletter JID",
+                        "name": "jid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of messages to fetch",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Fetch messages before this server ID",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/mute": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "mute=true/false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Mute newsletter",
+                "parameters": [
+                    {
+                        "description": "Mute request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NewsletterMuteRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/reaction": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Send reaction",
+                "parameters": [
+                    {
+                        "description": "Reaction request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NewsletterReactionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/newsletter/unfollow": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Unfollow",
+                "parameters": [
+                    {
+                        "description": "Newsletter JID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.NewsletterFollowRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/pairphone": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns 8-digit linking code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Pair phone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Phone number",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PairPhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/polls/{messageId}/results": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the vote count and voters of each option of a poll sent or received by this session. Each voter's latest vote counts; withdrawn votes are not counted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Polls"
+                ],
+                "summary": "Get poll results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Poll message ID",
+                        "name": "messageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PollResults"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/privacy": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the current privacy settings of the session account from WhatsApp",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Privacy"
+                ],
+                "summary": "Get privacy settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PrivacySettings"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the given privacy settings and returns all of them. Changes made on another device emit PrivacySettingsChanged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Privacy"
+                ],
+                "summary": "Set privacy settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Privacy settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PrivacySettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PrivacySettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/profile": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the JID, push name, about text and profile picture of the session account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get own profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Profile"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/profile/about": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the about text of the profile (max 139 characters)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Set own about text",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "About text",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProfileAboutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/profile/name": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the push name shown to contacts that have not saved the number (max 25 characters)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Set own name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProfileNameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/profile/photo": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Image as base64 data URL or https URL. It is converted to a JPEG of at most 640 pixels per side",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Set own profile photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Photo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProfilePhotoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Remove own profile photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/qr": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Get QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/queue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Latest 100 items of the send queue",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Queue"
+                ],
+                "summary": "List queued messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (pending, sending, sent, failed, canceled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.QueueItem"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/queue/{queueId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Queue"
+                ],
+                "summary": "Get queued message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Queue item ID",
+                        "name": "queueId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.QueueItem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Only pending items can be canceled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Queue"
+                ],
+                "summary": "Cancel queued message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Queue item ID",
+                        "name": "queueId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/scheduled": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled"
+                ],
+                "summary": "List scheduled messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (scheduled, sending, sent, failed, canceled)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ScheduledMessage"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stores any send payload (kind: text, image, audio, video, document, location, contact, sticker, poll, list, buttons) to be sent at send_at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled"
+                ],
+                "summary": "Schedule message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/scheduled/{scheduleId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled"
+                ],
+                "summary": "Get scheduled message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduled message ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Only messages that were not sent or canceled yet can be rescheduled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled"
+                ],
+                "summary": "Reschedule message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduled message ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New send time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RescheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduledMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduled"
+                ],
+                "summary": "Cancel scheduled message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduled message ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Get status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/status/text": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Set status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status text data",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StatusTextMessage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/avatar": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "preview=true for thumbnail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get avatar",
+                "parameters": [
+                    {
+                        "description": "Phone and preview option",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "phone": {
+                                    "type": "string"
+                                },
+                                "preview": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/block": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Blocks phone numbers or JIDs and returns the resulting blocklist. Changes emit Blocklist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Block contacts",
+                "parameters": [
+                    {
+                        "description": "Contacts",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BlocklistRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/blocklist": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the JIDs blocked by the session account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get blocklist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/check": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Verifies WhatsApp registration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Check users",
+                "parameters": [
+                    {
+                        "description": "Phone numbers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "phone": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/contacts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get contacts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/getlid": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get LID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/info": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user info",
+                "parameters": [
+                    {
+                        "description": "Phone numbers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "phone": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/newsletters": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get newsletters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/presence": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "available/unavailable",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Send presence",
+                "parameters": [
+                    {
+                        "description": "Presence (available/unavailable)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "presence": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/user/unblock": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Unblocks phone numbers or JIDs and returns the resulting blocklist. Changes emit Blocklist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Unblock contacts",
+                "parameters": [
+                    {
+                        "description": "Contacts",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BlocklistRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{sessionId}/webhook": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session name",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "active=false to disable",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session name",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook update data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "active": {
+                                    "type": "boolean"
+                                },
+                                "events": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                },
+                                "webhook": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Set webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session name",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session name",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/templates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "List templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Template"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stores a message template. Kind and payload follow the scheduled message format without the phone; payload strings may use {{name}} placeholders, and defaults gives values for optional ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create template",
+                "parameters": [
+                    {
+                        "description": "Template data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{templateId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Template"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Update template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Delete template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "templateId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "chatwoot.WebhookAttachment": {
+            "type": "object",
+            "properties": {
+                "data_url": {
+                    "type": "string"
+                },
+                "file_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "chatwoot.WebhookConversation": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chatwoot.WebhookEvent": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chatwoot.WebhookAttachment"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "conversation": {
+                    "$ref": "#/definitions/chatwoot.WebhookConversation"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message_type": {
+                    "type": "string"
+                },
+                "private": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ArchiveChatMessage": {
+            "type": "object",
+            "properties": {
+                "archive": {
+                    "type": "boolean"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AudioMessage": {
+            "type": "object",
+            "properties": {
+                "audio": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mimetype": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "ptt": {
+                    "type": "boolean"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.AutoReply": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "caseSensitive": {
+                    "type": "boolean"
+                },
+                "chatType": {
+                    "type": "string"
+                },
+                "cooldownSeconds": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deny": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "matchType": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "quote": {
+                    "type": "boolean"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "templateId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "window": {
+                    "$ref": "#/definitions/model.TimeWindow"
+                }
+            }
+        },
+        "model.AutoReplyRequest": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "case_sensitive": {
+                    "type": "boolean"
+                },
+                "chat_type": {
+                    "type": "string",
+                    "example": "direct"
+                },
+                "cooldown_seconds": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "deny": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "text"
+                },
+                "match_type": {
+                    "type": "string",
+                    "example": "contains"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "opening-hours"
+                },
+                "pattern": {
+                    "type": "string",
+                    "example": "hours"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "quote": {
+                    "type": "boolean"
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "window": {
+                    "$ref": "#/definitions/model.TimeWindow"
+                }
+            }
+        },
+        "model.BlocklistRequest": {
+            "type": "object",
+            "properties": {
+                "phone": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "5511999999999"
+                    ]
+                }
+            }
+        },
+        "model.ButtonItem": {
+            "type": "object",
+            "properties": {
+                "button_id": {
+                    "type": "string"
+                },
+                "button_text": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ButtonsMessage": {
+            "type": "object",
+            "properties": {
+                "buttons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ButtonItem"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.CallLog": {
+            "type": "object",
+            "properties": {
+                "callId": {
+                    "type": "string"
+                },
+                "caller": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "messageSent": {
+                    "type": "boolean"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "sessionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CallPolicy": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "window": {
+                    "$ref": "#/definitions/model.TimeWindow"
+                }
+            }
+        },
+        "model.CallPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Sorry, we can't take calls. Please send us a message."
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "always"
+                },
+                "window": {
+                    "$ref": "#/definitions/model.TimeWindow"
+                }
+            }
+        },
+        "model.Campaign": {
+            "type": "object",
+            "properties": {
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "jitterSeconds": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "progress": {
+                    "$ref": "#/definitions/model.CampaignProgress"
+                },
+                "ratePerMinute": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "description": "Rejected lists the malformed phones skipped when the campaign was\ncreated.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "templateId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "window": {
+                    "$ref": "#/definitions/model.TimeWindow"
+                }
+            }
+        },
+        "model.CampaignCreateRequest": {
+            "type": "object",
+            "properties": {
+                "jitter_seconds": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "text"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "black-friday"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "rate_per_minute": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CampaignRecipientInput"
+                    }
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "window": {
+                    "$ref": "#/definitions/model.TimeWindow"
+                }
+            }
+        },
+        "model.CampaignProgress": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "sending": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CampaignRecipient": {
+            "type": "object",
+            "properties": {
+                "campaignId": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "messageId": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.CampaignRecipientInput": {
+            "type": "object",
+            "properties": {
+                "phone": {
+                    "type": "string",
+                    "example": "5511999999999"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.CampaignRecipientsRequest": {
+            "type": "object",
+            "properties": {
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CampaignRecipientInput"
+                    }
+                }
+            }
+        },
+        "model.ChatAssignRequest": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "example": "agent-42"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "open"
+                }
+            }
+        },
+        "model.ChatAssignment": {
+            "type": "object",
+            "properties": {
+                "agentId": {
+                    "type": "string"
+                },
+                "assignedAt": {
+                    "type": "string"
+                },
+                "chat": {
+                    "type": "string"
+                },
+                "closedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChatCloseRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChatwootConfig": {
+            "type": "object",
+            "properties": {
+                "accountId": {
+                    "type": "integer"
+                },
+                "baseUrl": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "ignoreGroups": {
+                    "type": "boolean"
+                },
+                "inboxId": {
+                    "type": "integer"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "webhookPath": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChatwootConfigRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "api_token": {
+                    "type": "string"
+                },
+                "base_url": {
+                    "type": "string",
+                    "example": "https://app.chatwoot.com"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "ignore_groups": {
+                    "type": "boolean"
+                },
+                "inbox_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.ClearChatMessage": {
+            "type": "object",
+            "properties": {
+                "keep_starred": {
+                    "type": "boolean"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Connector": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "debounceMs": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "fallbackMessage": {
+                    "type": "string"
+                },
+                "ignoreGroups": {
+                    "type": "boolean"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "timeoutSeconds": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ConnectorRequest": {
+            "type": "object",
+            "properties": {
+                "auth_token": {
+                    "type": "string"
+                },
+                "debounce_ms": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "fallback_message": {
+                    "type": "string",
+                    "example": "Sorry, something went wrong. Please try again later."
+                },
+                "ignore_groups": {
+                    "type": "boolean"
+                },
+                "timeout_seconds": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://bot.example.com/whatsapp"
+                }
+            }
+        },
+        "model.ContactMessage": {
+            "type": "object",
+            "properties": {
+                "contact_name": {
+                    "type": "string"
+                },
+                "contact_vcard": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.DeleteChatMessage": {
+            "type": "object",
+            "properties": {
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeleteMessage": {
+            "type": "object",
+            "properties": {
+                "message_id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DocumentMessage": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "document": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mimetype": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.DownloadMediaMessage": {
+            "type": "object",
+            "properties": {
+                "direct_path": {
+                    "type": "string"
+                },
+                "file_enc_sha256": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "file_length": {
+                    "type": "integer"
+                },
+                "file_sha256": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "media_key": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "mimetype": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EditMessage": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Flow": {
+            "type": "object",
+            "properties": {
+                "definition": {
+                    "type": "object"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FlowDefinition": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "handoff_timeout_seconds": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "menu"
+                },
+                "states": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.FlowNode"
+                    }
+                },
+                "timeout_seconds": {
+                    "type": "integer",
+                    "example": 1800
+                },
+                "triggers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.FlowMessage": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "example": "text"
+                },
+                "payload": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.FlowNode": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "boolean"
+                },
+                "handoff": {
+                    "type": "boolean"
+                },
+                "invalid": {
+                    "$ref": "#/definitions/model.FlowMessage"
+                },
+                "message": {
+                    "$ref": "#/definitions/model.FlowMessage"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FlowTransition"
+                    }
+                }
+            }
+        },
+        "model.FlowState": {
+            "type": "object",
+            "properties": {
+                "chat": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.FlowTransition": {
+            "type": "object",
+            "properties": {
+                "match": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "1",
+                        "billing"
+                    ]
+                },
+                "next": {
+                    "type": "string",
+                    "example": "billing"
+                },
+                "regex": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GroupAnnounceRequest": {
+            "type": "object",
+            "properties": {
+                "announce": {
+                    "type": "boolean"
+                },
+                "jid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GroupEphemeralRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "\"24h\", \"7d\", \"90d\", \"off\"",
+                    "type": "string"
+                },
+                "jid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GroupInviteInfoRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GroupJoinRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GroupLockedRequest": {
+            "type": "object",
+            "properties": {
+                "jid": {
+                    "type": "string"
+                },
+                "locked": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.GroupPhotoRequest": {
+            "type": "object",
+            "properties": {
+                "image": {
+                    "type": "string"
+                },
+                "jid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ImageMessage": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mimetype": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.LinkPreview": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "thumbnail": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ListItem": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "row_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ListMessage": {
+            "type": "object",
+            "properties": {
+                "button_text": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "footer_text": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ListSection"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.ListSection": {
+            "type": "object",
+            "properties": {
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ListItem"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LocationMessage": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.MarkReadMessage": {
+            "type": "object",
+            "properties": {
+                "chat_phone": {
+                    "type": "string"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sender_phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MuteChatMessage": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer",
+                    "example": 28800
+                },
+                "mute": {
+                    "type": "boolean"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NewsletterCreateRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Newsletter description"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My Newsletter"
+                },
+                "picture": {
+                    "description": "base64 encoded image",
+                    "type": "string"
+                }
+            }
+        },
+        "model.NewsletterFollowRequest": {
+            "type": "object",
+            "properties": {
+                "jid": {
+                    "type": "string",
+                    "example": "123456789@newsletter"
+                }
+            }
         },
-        "model.ButtonsMessage": {
+        "model.NewsletterMarkViewedRequest": {
             "type": "object",
             "properties": {
-                "buttons": {
+                "jid": {
+                    "type": "string",
+                    "example": "123456789@newsletter"
+                },
+                "server_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.ButtonItem"
-                    }
-                },
-                "id": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
                 }
             }
         },
-        "model.ContactMessage": {
+        "model.NewsletterMuteRequest": {
             "type": "object",
             "properties": {
-                "contact_name": {
-                    "type": "string"
+                "jid": {
+                    "type": "string",
+                    "example": "123456789@newsletter"
                 },
-                "contact_vcard": {
-                    "type": "string"
+                "mute": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "model.NewsletterReactionRequest": {
+            "type": "object",
+            "properties": {
+                "jid": {
+                    "type": "string",
+                    "example": "123456789@newsletter"
                 },
-                "id": {
-                    "type": "string"
+                "reaction": {
+                    "type": "string",
+                    "example": "👍"
                 },
+                "server_id": {
+                    "type": "integer",
+                    "example": 123
+                }
+            }
+        },
+        "model.PairPhoneRequest": {
+            "type": "object",
+            "properties": {
                 "phone": {
                     "type": "string"
                 }
             }
         },
-        "model.DeleteMessage": {
+        "model.PinChatMessage": {
             "type": "object",
             "properties": {
-                "message_id": {
-                    "type": "string"
-                },
                 "phone": {
                     "type": "string"
+                },
+                "pin": {
+                    "type": "boolean"
                 }
             }
         },
-        "model.DocumentMessage": {
+        "model.Poll": {
             "type": "object",
             "properties": {
-                "caption": {
+                "chat": {
                     "type": "string"
                 },
-                "document": {
+                "createdAt": {
                     "type": "string"
                 },
-                "filename": {
+                "id": {
                     "type": "string"
                 },
-                "id": {
+                "name": {
                     "type": "string"
                 },
-                "mimetype": {
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "selectableCount": {
+                    "type": "integer"
+                },
+                "sender": {
                     "type": "string"
                 },
-                "phone": {
+                "sessionId": {
                     "type": "string"
                 }
             }
         },
-        "model.DownloadMediaMessage": {
+        "model.PollMessage": {
             "type": "object",
             "properties": {
-                "direct_path": {
+                "header": {
                     "type": "string"
                 },
-                "file_enc_sha256": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
-                "file_length": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 },
-                "file_sha256": {
+                "mentions": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 },
-                "media_key": {
+                "options": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 },
-                "mimetype": {
+                "phone": {
                     "type": "string"
                 },
-                "url": {
-                    "type": "string"
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
                 }
             }
         },
-        "model.EditMessage": {
+        "model.PollOptionResult": {
             "type": "object",
             "properties": {
-                "body": {
+                "name": {
                     "type": "string"
                 },
-                "message_id": {
-                    "type": "string"
+                "voters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "phone": {
-                    "type": "string"
+                "votes": {
+                    "type": "integer"
                 }
             }
         },
-        "model.GroupAnnounceRequest": {
+        "model.PollResults": {
             "type": "object",
             "properties": {
-                "announce": {
-                    "type": "boolean"
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PollOptionResult"
+                    }
                 },
-                "jid": {
-                    "type": "string"
+                "poll": {
+                    "$ref": "#/definitions/model.Poll"
+                },
+                "voters": {
+                    "type": "integer"
                 }
             }
         },
-        "model.GroupEphemeralRequest": {
+        "model.PrivacySettings": {
             "type": "object",
             "properties": {
-                "duration": {
-                    "description": "\"24h\", \"7d\", \"90d\", \"off\"",
+                "about": {
                     "type": "string"
                 },
-                "jid": {
+                "callAdd": {
                     "type": "string"
-                }
-            }
-        },
-        "model.GroupInviteInfoRequest": {
-            "type": "object",
-            "properties": {
-                "code": {
+                },
+                "groupAdd": {
                     "type": "string"
-                }
-            }
-        },
-        "model.GroupJoinRequest": {
-            "type": "object",
-            "properties": {
-                "code": {
+                },
+                "lastSeen": {
+                    "type": "string"
+                },
+                "online": {
+                    "type": "string"
+                },
+                "profile": {
+                    "type": "string"
+                },
+                "readReceipts": {
                     "type": "string"
                 }
             }
         },
-        "model.GroupLockedRequest": {
+        "model.PrivacySettingsRequest": {
             "type": "object",
             "properties": {
-                "jid": {
-                    "type": "string"
+                "about": {
+                    "type": "string",
+                    "example": "all"
                 },
-                "locked": {
-                    "type": "boolean"
+                "call_add": {
+                    "type": "string",
+                    "example": "known"
+                },
+                "group_add": {
+                    "type": "string",
+                    "example": "contact_blacklist"
+                },
+                "last_seen": {
+                    "type": "string",
+                    "example": "contacts"
+                },
+                "online": {
+                    "type": "string",
+                    "example": "match_last_seen"
+                },
+                "profile": {
+                    "type": "string",
+                    "example": "contacts"
+                },
+                "read_receipts": {
+                    "type": "string",
+                    "example": "all"
                 }
             }
         },
-        "model.GroupPhotoRequest": {
+        "model.Profile": {
             "type": "object",
             "properties": {
-                "image": {
+                "about": {
                     "type": "string"
                 },
                 "jid": {
                     "type": "string"
-                }
-            }
-        },
-        "model.ImageMessage": {
-            "type": "object",
-            "properties": {
-                "caption": {
-                    "type": "string"
                 },
-                "id": {
+                "lid": {
                     "type": "string"
                 },
-                "image": {
+                "pictureId": {
                     "type": "string"
                 },
-                "mimetype": {
+                "pictureUrl": {
                     "type": "string"
                 },
-                "phone": {
+                "pushName": {
                     "type": "string"
                 }
             }
         },
-        "model.ListItem": {
+        "model.ProfileAboutRequest": {
             "type": "object",
             "properties": {
-                "desc": {
-                    "type": "string"
-                },
-                "row_id": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+                "about": {
+                    "type": "string",
+                    "example": "Available 9am-6pm"
                 }
             }
         },
-        "model.ListMessage": {
+        "model.ProfileNameRequest": {
             "type": "object",
             "properties": {
-                "button_text": {
-                    "type": "string"
-                },
-                "desc": {
-                    "type": "string"
-                },
-                "footer_text": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "sections": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.ListSection"
-                    }
-                },
-                "title": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "FioZap Support"
                 }
             }
         },
-        "model.ListSection": {
+        "model.ProfilePhotoRequest": {
             "type": "object",
             "properties": {
-                "rows": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.ListItem"
-                    }
-                },
-                "title": {
-                    "type": "string"
+                "image": {
+                    "type": "string",
+                    "example": "data:image/jpeg;base64,/9j/4AAQ..."
                 }
             }
         },
-        "model.LocationMessage": {
+        "model.QueueItem": {
             "type": "object",
             "properties": {
-                "address": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "messageId": {
                     "type": "string"
                 },
-                "id": {
+                "notBefore": {
                     "type": "string"
                 },
-                "latitude": {
-                    "type": "number"
+                "payload": {
+                    "type": "object"
                 },
-                "longitude": {
-                    "type": "number"
+                "sessionId": {
+                    "type": "string"
                 },
-                "name": {
+                "status": {
                     "type": "string"
                 },
-                "phone": {
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "model.MarkReadMessage": {
+        "model.ReactionMessage": {
             "type": "object",
             "properties": {
-                "chat_phone": {
+                "emoji": {
                     "type": "string"
                 },
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "message_id": {
+                    "type": "string"
                 },
-                "sender_phone": {
+                "phone": {
                     "type": "string"
                 }
             }
         },
-        "model.NewsletterCreateRequest": {
+        "model.ReadChatMessage": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string",
-                    "example": "Newsletter description"
-                },
-                "name": {
-                    "type": "string",
-                    "example": "My Newsletter"
-                },
-                "picture": {
-                    "description": "base64 encoded image",
+                "phone": {
                     "type": "string"
+                },
+                "read": {
+                    "type": "boolean"
                 }
             }
         },
-        "model.NewsletterFollowRequest": {
+        "model.RejectCallRequest": {
             "type": "object",
             "properties": {
-                "jid": {
-                    "type": "string",
-                    "example": "123456789@newsletter"
+                "call_from": {
+                    "type": "string"
+                },
+                "call_id": {
+                    "type": "string"
                 }
             }
         },
-        "model.NewsletterMarkViewedRequest": {
+        "model.ReplyTo": {
             "type": "object",
             "properties": {
-                "jid": {
-                    "type": "string",
-                    "example": "123456789@newsletter"
+                "message_id": {
+                    "type": "string"
                 },
-                "server_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        2,
-                        3
-                    ]
+                "participant": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
                 }
             }
         },
-        "model.NewsletterMuteRequest": {
+        "model.RescheduleRequest": {
             "type": "object",
             "properties": {
-                "jid": {
+                "send_at": {
                     "type": "string",
-                    "example": "123456789@newsletter"
+                    "example": "2026-01-02 09:00"
                 },
-                "mute": {
-                    "type": "boolean",
-                    "example": true
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Sao_Paulo"
                 }
             }
         },
-        "model.NewsletterReactionRequest": {
+        "model.ScheduleRequest": {
             "type": "object",
             "properties": {
-                "jid": {
+                "kind": {
                     "type": "string",
-                    "example": "123456789@newsletter"
+                    "example": "text"
                 },
-                "reaction": {
+                "payload": {
+                    "type": "object"
+                },
+                "send_at": {
                     "type": "string",
-                    "example": "👍"
+                    "example": "2026-01-02 09:00"
                 },
-                "server_id": {
-                    "type": "integer",
-                    "example": 123
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Sao_Paulo"
                 }
             }
         },
-        "model.PairPhoneRequest": {
+        "model.ScheduledMessage": {
             "type": "object",
             "properties": {
-                "phone": {
+                "createdAt": {
                     "type": "string"
-                }
-            }
-        },
-        "model.PollMessage": {
-            "type": "object",
-            "properties": {
-                "header": {
+                },
+                "error": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "options": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "kind": {
+                    "type": "string"
                 },
-                "phone": {
+                "messageId": {
                     "type": "string"
-                }
-            }
-        },
-        "model.ReactionMessage": {
-            "type": "object",
-            "properties": {
-                "emoji": {
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "sendAt": {
                     "type": "string"
                 },
-                "message_id": {
+                "sessionId": {
                     "type": "string"
                 },
-                "phone": {
+                "status": {
                     "type": "string"
-                }
-            }
-        },
-        "model.RejectCallRequest": {
-            "type": "object",
-            "properties": {
-                "call_from": {
+                },
+                "timezone": {
                     "type": "string"
                 },
-                "call_id": {
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
@@ -4601,6 +9349,26 @@ const docTemplate = `{
                 }
             }
         },
+        "model.StarMessage": {
+            "type": "object",
+            "properties": {
+                "from_me": {
+                    "type": "boolean"
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "sender_phone": {
+                    "type": "string"
+                },
+                "star": {
+                    "type": "boolean"
+                }
+            }
+        },
         "model.StatusTextMessage": {
             "type": "object",
             "properties": {
@@ -4621,6 +9389,12 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "mimetype": {
                     "type": "string"
                 },
@@ -4642,8 +9416,111 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
                 "sticker": {
                     "type": "string"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.Template": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "defaults": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.TemplateRequest": {
+            "type": "object",
+            "properties": {
+                "defaults": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "text"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "welcome"
+                },
+                "payload": {
+                    "type": "object"
+                }
+            }
+        },
+        "model.TemplateSendRequest": {
+            "type": "object",
+            "properties": {
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "5511999999999"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "template_name": {
+                    "type": "string",
+                    "example": "welcome"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -4653,11 +9530,75 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "link_preview": {
+                    "type": "boolean"
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "message": {
                     "type": "string"
                 },
                 "phone": {
                     "type": "string"
+                },
+                "preview": {
+                    "$ref": "#/definitions/model.LinkPreview"
+                },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                }
+            }
+        },
+        "model.TimeWindow": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri"
+                    ]
+                },
+                "end": {
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "09:00"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Sao_Paulo"
+                }
+            }
+        },
+        "model.Typing": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 2000
+                },
+                "wpm": {
+                    "type": "integer",
+                    "example": 40
                 }
             }
         },
@@ -4701,12 +9642,27 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "mimetype": {
                     "type": "string"
                 },
                 "phone": {
                     "type": "string"
                 },
+                "queue": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "$ref": "#/definitions/model.ReplyTo"
+                },
+                "typing": {
+                    "$ref": "#/definitions/model.Typing"
+                },
                 "video": {
                     "type": "string"
                 }
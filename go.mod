@@ -15,6 +15,8 @@ require (
 	github.com/vincent-petithory/dataurl v1.0.0
 	go.mau.fi/util v0.9.4
 	go.mau.fi/whatsmeow v0.0.0-20260107124630-ccfa04f8e445
+	go.yaml.in/yaml/v3 v3.0.4
+	golang.org/x/net v0.48.0
 	google.golang.org/protobuf v1.36.11
 )
 
@@ -41,11 +43,9 @@ require (
 	github.com/swaggo/files v1.0.1 // indirect
 	github.com/vektah/gqlparser/v2 v2.5.27 // indirect
 	go.mau.fi/libsignal v0.2.1 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/crypto v0.46.0 // indirect
 	golang.org/x/exp v0.0.0-20251209150349-8475f28825e9 // indirect
 	golang.org/x/mod v0.32.0 // indirect
-	golang.org/x/net v0.48.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/term v0.38.0 // indirect
@@ -0,0 +1,366 @@
+// Package chatwoot is a minimal client of the Chatwoot application API and
+// the payloads of its webhooks, as far as the inbox bridge needs them.
+package chatwoot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	tokenHeader     = "api_access_token"
+	contentTypeJSON = "application/json"
+	userAgent       = "FioZap-Chatwoot/1.0"
+	errorBodyLimit  = 512
+	maxRedirects    = 10
+
+	MessageTypeIncoming = "incoming"
+	MessageTypeOutgoing = "outgoing"
+	EventMessageCreated = "message_created"
+)
+
+var errAttachmentTooLarge = errors.New("attachment exceeds maximum allowed size")
+
+// APIError is a response of the Chatwoot API with an error status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("chatwoot returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is a 404 response of the Chatwoot API, e.g.
+// for a conversation that was deleted.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+type Contact struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phone_number"`
+	Identifier  string `json:"identifier"`
+}
+
+type Conversation struct {
+	ID      int64  `json:"id"`
+	InboxID int64  `json:"inbox_id"`
+	Status  string `json:"status"`
+}
+
+type Message struct {
+	ID int64 `json:"id"`
+}
+
+// Attachment is a file posted with a message.
+type Attachment struct {
+	Reader   io.Reader
+	FileName string
+	MimeType string
+}
+
+// Client calls the API of one Chatwoot account.
+type Client struct {
+	http      *http.Client
+	baseURL   string
+	accountID int64
+	token     string
+}
+
+// NewClient returns a client of a Chatwoot account. The redirect policy of
+// httpClient is applied after the API token was removed from redirects that
+// leave the Chatwoot host.
+func NewClient(httpClient *http.Client, baseURL string, accountID int64, token string) *Client {
+	c := &Client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		accountID: accountID,
+		token:     token,
+	}
+	hc := *httpClient
+	hc.CheckRedirect = c.checkRedirect(httpClient.CheckRedirect)
+	c.http = &hc
+	return c
+}
+
+// SearchContacts returns the contacts whose name, phone number, email or
+// identifier contain query.
+func (c *Client) SearchContacts(ctx context.Context, query string) ([]Contact, error) {
+	var res struct {
+		Payload []Contact `json:"payload"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/contacts/search?q="+url.QueryEscape(query), nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to search contacts: %w", err)
+	}
+	return res.Payload, nil
+}
+
+// CreateContact creates a contact and adds it to an inbox.
+func (c *Client) CreateContact(ctx context.Context, inboxID int64, name, phone, identifier string) (*Contact, error) {
+	body := map[string]interface{}{
+		"inbox_id":   inboxID,
+		"name":       name,
+		"identifier": identifier,
+	}
+	if phone != "" {
+		body["phone_number"] = "+" + phone
+	}
+
+	// Chatwoot returns the contact either directly in the payload or next to
+	// its contact inbox, depending on the version.
+	var res struct {
+		Payload struct {
+			Contact
+			Nested *Contact `json:"contact"`
+		} `json:"payload"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/contacts", body, &res); err != nil {
+		return nil, fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	if res.Payload.Nested != nil {
+		return res.Payload.Nested, nil
+	}
+	contact := res.Payload.Contact
+	return &contact, nil
+}
+
+// ListConversations returns the conversations of a contact.
+func (c *Client) ListConversations(ctx context.Context, contactID int64) ([]Conversation, error) {
+	var res struct {
+		Payload []Conversation `json:"payload"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/contacts/%d/conversations", contactID), nil, &res); err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	return res.Payload, nil
+}
+
+// CreateConversation opens a conversation with a contact in an inbox.
+func (c *Client) CreateConversation(ctx context.Context, inboxID, contactID int64) (*Conversation, error) {
+	body := map[string]interface{}{
+		"inbox_id":   inboxID,
+		"contact_id": contactID,
+	}
+
+	var conv Conversation
+	if err := c.do(ctx, http.MethodPost, "/conversations", body, &conv); err != nil {
+		return nil, fmt.Errorf("failed to create conversation: %w", err)
+	}
+	return &conv, nil
+}
+
+// CreateIncomingMessage posts a message of the contact to a conversation,
+// with an optional attachment.
+func (c *Client) CreateIncomingMessage(ctx context.Context, conversationID int64, content string, att *Attachment) (*Message, error) {
+	endpoint := fmt.Sprintf("/conversations/%d/messages", conversationID)
+
+	var msg Message
+	var err error
+	if att == nil {
+		err = c.do(ctx, http.MethodPost, endpoint, map[string]interface{}{
+			"content":      content,
+			"message_type": MessageTypeIncoming,
+			"private":      false,
+		}, &msg)
+	} else {
+		err = c.doMultipart(ctx, endpoint, content, att, &msg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+	return &msg, nil
+}
+
+// Download fetches an attachment of an outgoing message. Only URLs on the
+// Chatwoot host are requested, so the webhook cannot be used to reach other
+// hosts, and redirects to a storage host are followed without the API token.
+// The caller must close the returned body.
+func (c *Client) Download(ctx context.Context, rawURL string, maxSize int64) (io.ReadCloser, string, string, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || !c.onHost(target) {
+		return nil, "", "", fmt.Errorf("attachment url %q is not on the chatwoot host", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(tokenHeader, c.token)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to download attachment: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, "", "", apiError(resp)
+	}
+	if resp.ContentLength > maxSize {
+		_ = resp.Body.Close()
+		return nil, "", "", errAttachmentTooLarge
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	fileName := path.Base(resp.Request.URL.Path)
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		fileName = params["filename"]
+	}
+
+	return &limitedBody{ReadCloser: resp.Body, remaining: maxSize}, mimeType, fileName, nil
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.accountURL(endpoint), reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentTypeJSON)
+	}
+
+	return c.send(req, out)
+}
+
+// doMultipart writes the form through a pipe while it is sent, so the
+// attachment is read once from its reader instead of being copied into a
+// buffer of the whole form.
+func (c *Client) doMultipart(ctx context.Context, endpoint, content string, att *Attachment, out interface{}) error {
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+
+	go func() {
+		err := writeMessageForm(form, content, att)
+		if err == nil {
+			err = form.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.accountURL(endpoint), pr)
+	if err != nil {
+		_ = pr.Close()
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return c.send(req, out)
+}
+
+func writeMessageForm(form *multipart.Writer, content string, att *Attachment) error {
+	if content != "" {
+		if err := form.WriteField("content", content); err != nil {
+			return err
+		}
+	}
+	if err := form.WriteField("message_type", MessageTypeIncoming); err != nil {
+		return err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     "attachments[]",
+		"filename": att.FileName,
+	}))
+	header.Set("Content-Type", att.MimeType)
+
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, att.Reader)
+	return err
+}
+
+func (c *Client) send(req *http.Request, out interface{}) error {
+	req.Header.Set(tokenHeader, c.token)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return apiError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// checkRedirect drops the API token from redirects to other hosts, e.g. the
+// storage of an attachment, before applying the policy of the HTTP client.
+func (c *Client) checkRedirect(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !c.onHost(req.URL) {
+			req.Header.Del(tokenHeader)
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+}
+
+// onHost reports whether u has the scheme and host of the Chatwoot instance.
+func (c *Client) onHost(u *url.URL) bool {
+	base, err := url.Parse(c.baseURL)
+	return err == nil && u.Scheme == base.Scheme && u.Host == base.Host
+}
+
+func (c *Client) accountURL(endpoint string) string {
+	return c.baseURL + "/api/v1/accounts/" + strconv.FormatInt(c.accountID, 10) + endpoint
+}
+
+func apiError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit))
+	return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+}
+
+// limitedBody fails reads past the size limit instead of truncating the file.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, errAttachmentTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,190 @@
+package chatwoot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testToken = "secret-token"
+
+func TestClientAPI(t *testing.T) {
+	type request struct {
+		method, path, query, token string
+		body                       map[string]interface{}
+	}
+	var got []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, token: r.Header.Get(tokenHeader)}
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&req.body)
+		}
+		got = append(got, req)
+
+		switch r.URL.Path {
+		case "/api/v1/accounts/7/contacts/search":
+			_, _ = io.WriteString(w, `{"payload":[{"id":1,"name":"Maria","phone_number":"+5511999999999","identifier":"5511999999999@s.whatsapp.net"}]}`)
+		case "/api/v1/accounts/7/contacts":
+			// Newer versions nest the contact next to its contact inbox.
+			_, _ = io.WriteString(w, `{"payload":{"contact":{"id":2,"name":"Joana"},"contact_inbox":{"source_id":"x"}}}`)
+		case "/api/v1/accounts/7/conversations":
+			_, _ = io.WriteString(w, `{"id":42,"inbox_id":3,"status":"open"}`)
+		case "/api/v1/accounts/7/conversations/404/messages":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":"conversation not found"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.Client(), server.URL+"/", 7, testToken)
+	ctx := context.Background()
+
+	contacts, err := c.SearchContacts(ctx, "+55 11")
+	if err != nil || len(contacts) != 1 || contacts[0].PhoneNumber != "+5511999999999" {
+		t.Errorf("SearchContacts() = %+v, %v", contacts, err)
+	}
+
+	contact, err := c.CreateContact(ctx, 3, "Joana", "5511888888888", "5511888888888@s.whatsapp.net")
+	if err != nil || contact.ID != 2 || contact.Name != "Joana" {
+		t.Errorf("CreateContact() = %+v, %v", contact, err)
+	}
+
+	conv, err := c.CreateConversation(ctx, 3, 2)
+	if err != nil || conv.ID != 42 {
+		t.Errorf("CreateConversation() = %+v, %v", conv, err)
+	}
+
+	_, err = c.CreateIncomingMessage(ctx, 404, "hi", nil)
+	if !IsNotFound(err) {
+		t.Errorf("CreateIncomingMessage() error = %v, want a not found error", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !strings.Contains(apiErr.Body, "conversation not found") {
+		t.Errorf("CreateIncomingMessage() error = %v, want the response body", err)
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("server received %d requests, want 4", len(got))
+	}
+	for _, req := range got {
+		if req.token != testToken {
+			t.Errorf("%s %s token = %q", req.method, req.path, req.token)
+		}
+	}
+	if q, _ := url.ParseQuery(got[0].query); q.Get("q") != "+55 11" {
+		t.Errorf("search query = %q", got[0].query)
+	}
+	if body := got[1].body; body["phone_number"] != "+5511888888888" || body["identifier"] != "5511888888888@s.whatsapp.net" || body["inbox_id"] != float64(3) {
+		t.Errorf("create contact body = %v", body)
+	}
+	if body := got[3].body; body["message_type"] != MessageTypeIncoming || body["private"] != false || body["content"] != "hi" {
+		t.Errorf("create message body = %v", body)
+	}
+}
+
+func TestClientCheckRedirect(t *testing.T) {
+	c := NewClient(&http.Client{}, "https://chatwoot.example", 1, testToken)
+
+	tests := []struct {
+		name      string
+		target    string
+		via       int
+		wantToken bool
+		wantErr   bool
+	}{
+		{name: "same host keeps token", target: "https://chatwoot.example/rails/file.jpg", wantToken: true},
+		{name: "other host drops token", target: "https://storage.example/file.jpg"},
+		{name: "other scheme drops token", target: "http://chatwoot.example/file.jpg"},
+		{name: "other port drops token", target: "https://chatwoot.example:8443/file.jpg"},
+		{name: "redirect limit", target: "https://chatwoot.example/file.jpg", via: maxRedirects, wantToken: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set(tokenHeader, testToken)
+
+			err := c.http.CheckRedirect(req, make([]*http.Request, tt.via))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRedirect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if has := req.Header.Get(tokenHeader) != ""; has != tt.wantToken {
+				t.Errorf("token kept = %v, want %v", has, tt.wantToken)
+			}
+		})
+	}
+
+	// The policy of the given client runs after the token was dropped.
+	policyErr := errors.New("policy")
+	var sawToken bool
+	wrapped := NewClient(&http.Client{CheckRedirect: func(req *http.Request, _ []*http.Request) error {
+		sawToken = req.Header.Get(tokenHeader) != ""
+		return policyErr
+	}}, "https://chatwoot.example", 1, testToken)
+	req := httptest.NewRequest(http.MethodGet, "https://storage.example/file.jpg", nil)
+	req.Header.Set(tokenHeader, testToken)
+	if err := wrapped.http.CheckRedirect(req, nil); !errors.Is(err, policyErr) || sawToken {
+		t.Errorf("CheckRedirect() error = %v, token seen by policy = %v", err, sawToken)
+	}
+}
+
+func TestClientDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="invoice 1.pdf"`)
+		_, _ = io.WriteString(w, strings.Repeat("x", 100))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.Client(), server.URL, 1, testToken)
+	ctx := context.Background()
+
+	body, mimeType, fileName, err := c.Download(ctx, server.URL+"/rails/blob/abc", 100)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	_ = body.Close()
+	if err != nil || len(data) != 100 || mimeType != "application/pdf" || fileName != "invoice 1.pdf" {
+		t.Errorf("Download() = %d bytes, %q, %q, %v", len(data), mimeType, fileName, err)
+	}
+
+	if _, _, _, err := c.Download(ctx, server.URL+"/rails/blob/abc", 99); !errors.Is(err, errAttachmentTooLarge) {
+		t.Errorf("Download() over the limit error = %v, want %v", err, errAttachmentTooLarge)
+	}
+
+	if _, _, _, err := c.Download(ctx, "https://elsewhere.example/file.pdf", 100); err == nil {
+		t.Error("Download() fetched a URL outside the chatwoot host")
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		limit   int64
+		wantErr error
+	}{
+		{name: "under the limit", size: 10, limit: 11},
+		{name: "at the limit", size: 10, limit: 10},
+		{name: "over the limit", size: 11, limit: 10, wantErr: errAttachmentTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &limitedBody{ReadCloser: io.NopCloser(strings.NewReader(strings.Repeat("x", tt.size))), remaining: tt.limit}
+			_, err := io.ReadAll(body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadAll() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
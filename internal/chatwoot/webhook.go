@@ -0,0 +1,36 @@
+package chatwoot
+
+// Attachment file types of Chatwoot messages.
+const (
+	FileTypeImage = "image"
+	FileTypeAudio = "audio"
+	FileTypeVideo = "video"
+	FileTypeFile  = "file"
+)
+
+// WebhookEvent is the payload of the account or inbox webhook of Chatwoot.
+// Only the fields of message events are decoded.
+type WebhookEvent struct {
+	Event        string              `json:"event"`
+	ID           int64               `json:"id"`
+	Content      string              `json:"content"`
+	MessageType  string              `json:"message_type"`
+	Private      bool                `json:"private"`
+	Conversation WebhookConversation `json:"conversation"`
+	Attachments  []WebhookAttachment `json:"attachments"`
+}
+
+type WebhookConversation struct {
+	ID int64 `json:"id"`
+}
+
+type WebhookAttachment struct {
+	FileType string `json:"file_type"`
+	DataURL  string `json:"data_url"`
+}
+
+// IsAgentReply reports whether the event is a public message written in
+// Chatwoot that should reach the contact.
+func (e *WebhookEvent) IsAgentReply() bool {
+	return e.Event == EventMessageCreated && e.MessageType == MessageTypeOutgoing && !e.Private && e.Conversation.ID != 0
+}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -22,6 +25,16 @@ const (
 	defaultLogLevel  = "info"
 	defaultLogType   = "console"
 	tokenLength      = 16
+
+	defaultMediaMaxSize      = 64 << 20
+	defaultMediaFetchTimeout = 30 * time.Second
+	defaultMessageRetention  = 30 * 24 * time.Hour
+
+	defaultQueueRate   = 20
+	defaultQueueBurst  = 5
+	defaultQueueJitter = 3 * time.Second
+
+	defaultIdempotencyTTL = 24 * time.Hour
 )
 
 type Config struct {
@@ -38,6 +51,21 @@ type Config struct {
 	LogType    string
 	WADebug    string
 	CORSOrigin string
+
+	MediaMaxSize      int64
+	MediaFetchTimeout time.Duration
+	MessageStore      bool
+	MessageRetention  time.Duration
+
+	QueueRate   int
+	QueueBurst  int
+	QueueJitter time.Duration
+
+	IdempotencyTTL time.Duration
+
+	AlertWebhookURL string
+
+	AllowedPrivateHosts []string
 }
 
 func Load() (*Config, error) {
@@ -57,6 +85,21 @@ func Load() (*Config, error) {
 		LogType:    getEnv("LOG_TYPE", defaultLogType),
 		WADebug:    getEnv("WA_DEBUG", ""),
 		CORSOrigin: getEnv("CORS_ORIGIN", "*"),
+
+		MediaMaxSize:      getEnvInt64("MEDIA_MAX_SIZE", defaultMediaMaxSize),
+		MediaFetchTimeout: getEnvDuration("MEDIA_FETCH_TIMEOUT", defaultMediaFetchTimeout),
+		MessageStore:      getEnvBool("MESSAGE_STORE", false),
+		MessageRetention:  getEnvDuration("MESSAGE_RETENTION", defaultMessageRetention),
+
+		QueueRate:   int(getEnvInt64("QUEUE_RATE", defaultQueueRate)),
+		QueueBurst:  int(getEnvInt64("QUEUE_BURST", defaultQueueBurst)),
+		QueueJitter: getEnvDuration("QUEUE_JITTER", defaultQueueJitter),
+
+		IdempotencyTTL: getEnvDuration("IDEMPOTENCY_TTL", defaultIdempotencyTTL),
+
+		AlertWebhookURL: getEnv("ALERT_WEBHOOK_URL", ""),
+
+		AllowedPrivateHosts: getEnvList("ALLOWED_PRIVATE_HOSTS"),
 	}
 
 	if cfg.AdminToken == "" {
@@ -80,6 +123,21 @@ func (c *Config) Validate() error {
 	if c.DBUser == "" {
 		return errors.New("DB_USER is required")
 	}
+	if c.MediaMaxSize <= 0 {
+		return errors.New("MEDIA_MAX_SIZE must be positive")
+	}
+	if c.MessageRetention < 0 {
+		return errors.New("MESSAGE_RETENTION must not be negative")
+	}
+	if c.QueueRate <= 0 {
+		return errors.New("QUEUE_RATE must be positive")
+	}
+	if c.QueueBurst <= 0 {
+		return errors.New("QUEUE_BURST must be positive")
+	}
+	if c.IdempotencyTTL <= 0 {
+		return errors.New("IDEMPOTENCY_TTL must be positive")
+	}
 	return nil
 }
 
@@ -112,6 +170,44 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated variable, dropping empty items.
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 func generateToken() string {
 	b := make([]byte, tokenLength)
 	_, _ = rand.Read(b)
@@ -0,0 +1,18 @@
+-- v3 -> v4: Create fzMessage table
+
+CREATE TABLE IF NOT EXISTS "fzMessage" (
+    "id" VARCHAR(128) NOT NULL,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "chat" VARCHAR(255) NOT NULL,
+    "sender" VARCHAR(255) NOT NULL,
+    "fromMe" BOOLEAN NOT NULL DEFAULT FALSE,
+    "type" VARCHAR(32) NOT NULL,
+    "text" TEXT DEFAULT '',
+    "raw" BYTEA,
+    "timestamp" TIMESTAMP NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "id")
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzMessageChat" 
+ON "fzMessage" ("sessionId", "chat", "timestamp" DESC);
@@ -0,0 +1,22 @@
+-- v4 -> v5: Create fzMessageQueue table
+
+CREATE TABLE IF NOT EXISTS "fzMessageQueue" (
+    "id" VARCHAR(64) PRIMARY KEY,
+    "userId" VARCHAR(64) NOT NULL,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "kind" VARCHAR(20) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "error" TEXT DEFAULT '',
+    "messageId" VARCHAR(128) DEFAULT '',
+    "notBefore" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzMessageQueuePending" 
+ON "fzMessageQueue" ("sessionId", "notBefore") WHERE "status" = 'pending';
+
+CREATE INDEX IF NOT EXISTS "idxFzMessageQueueSession" 
+ON "fzMessageQueue" ("sessionId", "createdAt" DESC);
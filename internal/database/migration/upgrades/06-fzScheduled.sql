@@ -0,0 +1,22 @@
+-- v5 -> v6: Create fzScheduled table
+
+CREATE TABLE IF NOT EXISTS "fzScheduled" (
+    "id" VARCHAR(64) PRIMARY KEY,
+    "userId" VARCHAR(64) NOT NULL,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "kind" VARCHAR(20) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "sendAt" TIMESTAMPTZ NOT NULL,
+    "timezone" VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    "status" VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    "error" TEXT DEFAULT '',
+    "messageId" VARCHAR(128) DEFAULT '',
+    "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzScheduledDue" 
+ON "fzScheduled" ("sendAt") WHERE "status" = 'scheduled';
+
+CREATE INDEX IF NOT EXISTS "idxFzScheduledSession" 
+ON "fzScheduled" ("sessionId", "sendAt");
@@ -0,0 +1,40 @@
+-- v6 -> v7: Create fzCampaign and fzCampaignRecipient tables
+
+CREATE TABLE IF NOT EXISTS "fzCampaign" (
+    "id" VARCHAR(64) PRIMARY KEY,
+    "userId" VARCHAR(64) NOT NULL,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "name" VARCHAR(255) NOT NULL,
+    "kind" VARCHAR(20) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "ratePerMinute" INTEGER NOT NULL,
+    "jitterSeconds" INTEGER NOT NULL DEFAULT 0,
+    "window" JSONB,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'draft',
+    "leaseOwner" VARCHAR(64) DEFAULT '',
+    "leaseUntil" TIMESTAMPTZ,
+    "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "completedAt" TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzCampaignSession" 
+ON "fzCampaign" ("sessionId", "createdAt" DESC);
+
+CREATE INDEX IF NOT EXISTS "idxFzCampaignRunning" 
+ON "fzCampaign" ("status") WHERE "status" = 'running';
+
+CREATE TABLE IF NOT EXISTS "fzCampaignRecipient" (
+    "campaignId" VARCHAR(64) NOT NULL REFERENCES "fzCampaign"("id") ON DELETE CASCADE,
+    "phone" VARCHAR(255) NOT NULL,
+    "variables" JSONB NOT NULL DEFAULT '{}',
+    "position" SERIAL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+    "error" TEXT DEFAULT '',
+    "messageId" VARCHAR(128) DEFAULT '',
+    "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("campaignId", "phone")
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzCampaignRecipientPending" 
+ON "fzCampaignRecipient" ("campaignId", "position") WHERE "status" = 'pending';
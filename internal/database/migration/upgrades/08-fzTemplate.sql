@@ -0,0 +1,13 @@
+-- v7 -> v8: Create fzTemplate table
+
+CREATE TABLE IF NOT EXISTS "fzTemplate" (
+    "id" VARCHAR(64) PRIMARY KEY,
+    "userId" VARCHAR(64) NOT NULL REFERENCES "fzUser"("id") ON DELETE CASCADE,
+    "name" VARCHAR(255) NOT NULL,
+    "kind" VARCHAR(20) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "defaults" JSONB NOT NULL DEFAULT '{}',
+    "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE ("userId", "name")
+);
@@ -0,0 +1,17 @@
+-- v8 -> v9: Create fzIdempotency table
+
+CREATE TABLE IF NOT EXISTS "fzIdempotency" (
+    "userId" VARCHAR(64) NOT NULL REFERENCES "fzUser"("id") ON DELETE CASCADE,
+    "key" VARCHAR(255) NOT NULL,
+    "requestHash" VARCHAR(64) NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'processing',
+    "statusCode" INTEGER NOT NULL DEFAULT 0,
+    "contentType" VARCHAR(255) NOT NULL DEFAULT '',
+    "responseBody" BYTEA,
+    "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "expiresAt" TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY ("userId", "key")
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzIdempotencyExpires" 
+ON "fzIdempotency" ("expiresAt");
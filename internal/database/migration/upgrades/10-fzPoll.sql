@@ -0,0 +1,22 @@
+-- v9 -> v10: Create fzPoll and fzPollVote tables
+
+CREATE TABLE IF NOT EXISTS "fzPoll" (
+    "id" VARCHAR(128) NOT NULL,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "chat" VARCHAR(255) NOT NULL,
+    "sender" VARCHAR(255) NOT NULL,
+    "name" TEXT NOT NULL,
+    "options" TEXT[] NOT NULL,
+    "selectableCount" INTEGER NOT NULL DEFAULT 0,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "id")
+);
+
+CREATE TABLE IF NOT EXISTS "fzPollVote" (
+    "pollId" VARCHAR(128) NOT NULL,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "voter" VARCHAR(255) NOT NULL,
+    "options" TEXT[] NOT NULL,
+    "timestamp" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("sessionId", "pollId", "voter")
+);
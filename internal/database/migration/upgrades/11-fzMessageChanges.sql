@@ -0,0 +1,5 @@
+-- v10 -> v11: Track edits, revokes and reactions in fzMessage
+
+ALTER TABLE "fzMessage" ADD COLUMN IF NOT EXISTS "editedAt" TIMESTAMP;
+ALTER TABLE "fzMessage" ADD COLUMN IF NOT EXISTS "revoked" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE "fzMessage" ADD COLUMN IF NOT EXISTS "reactions" JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,8 @@
+-- v11 -> v12: Track bans and replaced streams on fzSession and prioritize alert webhooks
+
+ALTER TABLE "fzSession" ADD COLUMN IF NOT EXISTS "alert" VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE "fzSession" ADD COLUMN IF NOT EXISTS "alertReason" TEXT NOT NULL DEFAULT '';
+ALTER TABLE "fzSession" ADD COLUMN IF NOT EXISTS "alertAt" TIMESTAMP;
+ALTER TABLE "fzSession" ADD COLUMN IF NOT EXISTS "bannedUntil" TIMESTAMP;
+
+ALTER TABLE "fzWebhook" ADD COLUMN IF NOT EXISTS "priority" INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- v12 -> v13: Create fzCallPolicy and fzCallLog tables
+
+CREATE TABLE IF NOT EXISTS "fzCallPolicy" (
+    "sessionId" VARCHAR(64) PRIMARY KEY REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "mode" VARCHAR(20) NOT NULL,
+    "window" JSONB,
+    "message" TEXT NOT NULL DEFAULT '',
+    "updatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "fzCallLog" (
+    "id" VARCHAR(64) PRIMARY KEY,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "callId" VARCHAR(128) NOT NULL,
+    "caller" VARCHAR(255) NOT NULL,
+    "mode" VARCHAR(20) NOT NULL,
+    "messageSent" BOOLEAN NOT NULL DEFAULT FALSE,
+    "error" TEXT NOT NULL DEFAULT '',
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzCallLogSession" ON "fzCallLog" ("sessionId", "createdAt" DESC);
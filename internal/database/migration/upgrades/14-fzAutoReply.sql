@@ -0,0 +1,32 @@
+-- v13 -> v14: Create fzAutoReply and fzAutoReplyCooldown tables
+
+CREATE TABLE IF NOT EXISTS "fzAutoReply" (
+    "id" VARCHAR(64) PRIMARY KEY,
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "name" VARCHAR(255) NOT NULL,
+    "enabled" BOOLEAN NOT NULL DEFAULT TRUE,
+    "priority" INTEGER NOT NULL DEFAULT 0,
+    "matchType" VARCHAR(20) NOT NULL,
+    "pattern" TEXT NOT NULL,
+    "caseSensitive" BOOLEAN NOT NULL DEFAULT FALSE,
+    "chatType" VARCHAR(20) NOT NULL DEFAULT 'any',
+    "allow" TEXT[] NOT NULL DEFAULT '{}',
+    "deny" TEXT[] NOT NULL DEFAULT '{}',
+    "window" JSONB,
+    "kind" VARCHAR(20) NOT NULL,
+    "payload" JSONB NOT NULL DEFAULT '{}',
+    "templateId" VARCHAR(64) NOT NULL DEFAULT '',
+    "quote" BOOLEAN NOT NULL DEFAULT FALSE,
+    "cooldownSeconds" INTEGER NOT NULL DEFAULT 60,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzAutoReplySession" ON "fzAutoReply" ("sessionId", "priority" DESC);
+
+CREATE TABLE IF NOT EXISTS "fzAutoReplyCooldown" (
+    "ruleId" VARCHAR(64) NOT NULL REFERENCES "fzAutoReply"("id") ON DELETE CASCADE,
+    "chat" VARCHAR(255) NOT NULL,
+    "sentAt" TIMESTAMP NOT NULL,
+    PRIMARY KEY ("ruleId", "chat")
+);
@@ -0,0 +1,21 @@
+-- v14 -> v15: Create fzFlow and fzFlowState tables
+
+CREATE TABLE IF NOT EXISTS "fzFlow" (
+    "sessionId" VARCHAR(64) PRIMARY KEY REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "definition" JSONB NOT NULL,
+    "enabled" BOOLEAN NOT NULL DEFAULT TRUE,
+    "updatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "fzFlowState" (
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "chat" VARCHAR(255) NOT NULL,
+    "state" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(20) NOT NULL,
+    "expiresAt" TIMESTAMP,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "chat")
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzFlowStateStatus" ON "fzFlowState" ("sessionId", "status");
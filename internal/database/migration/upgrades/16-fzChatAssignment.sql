@@ -0,0 +1,16 @@
+-- v15 -> v16: Create fzChatAssignment table
+
+CREATE TABLE IF NOT EXISTS "fzChatAssignment" (
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "chat" VARCHAR(255) NOT NULL,
+    "agentId" VARCHAR(255) NOT NULL DEFAULT '',
+    "status" VARCHAR(20) NOT NULL,
+    "notes" TEXT NOT NULL DEFAULT '',
+    "assignedAt" TIMESTAMP,
+    "closedAt" TIMESTAMP,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "chat")
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzChatAssignmentAgent" ON "fzChatAssignment" ("sessionId", "agentId", "status");
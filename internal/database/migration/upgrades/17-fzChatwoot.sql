@@ -0,0 +1,25 @@
+-- v16 -> v17: Create fzChatwoot and fzChatwootConversation tables
+
+CREATE TABLE IF NOT EXISTS "fzChatwoot" (
+    "sessionId" VARCHAR(64) PRIMARY KEY REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "enabled" BOOLEAN NOT NULL DEFAULT TRUE,
+    "baseUrl" TEXT NOT NULL,
+    "accountId" BIGINT NOT NULL,
+    "inboxId" BIGINT NOT NULL,
+    "apiToken" TEXT NOT NULL,
+    "webhookToken" VARCHAR(64) NOT NULL,
+    "ignoreGroups" BOOLEAN NOT NULL DEFAULT TRUE,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "fzChatwootConversation" (
+    "sessionId" VARCHAR(64) NOT NULL REFERENCES "fzChatwoot"("sessionId") ON DELETE CASCADE,
+    "chat" VARCHAR(255) NOT NULL,
+    "contactId" BIGINT NOT NULL,
+    "conversationId" BIGINT NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "chat")
+);
+
+CREATE INDEX IF NOT EXISTS "idxFzChatwootConversationId" ON "fzChatwootConversation" ("sessionId", "conversationId");
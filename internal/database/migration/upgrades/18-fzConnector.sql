@@ -0,0 +1,14 @@
+-- v17 -> v18: Create fzConnector table
+
+CREATE TABLE IF NOT EXISTS "fzConnector" (
+    "sessionId" VARCHAR(64) PRIMARY KEY REFERENCES "fzSession"("id") ON DELETE CASCADE,
+    "enabled" BOOLEAN NOT NULL DEFAULT TRUE,
+    "url" TEXT NOT NULL,
+    "authToken" TEXT NOT NULL DEFAULT '',
+    "timeoutSeconds" INTEGER NOT NULL DEFAULT 30,
+    "debounceMs" INTEGER NOT NULL DEFAULT 1500,
+    "fallbackMessage" TEXT NOT NULL DEFAULT '',
+    "ignoreGroups" BOOLEAN NOT NULL DEFAULT TRUE,
+    "createdAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "updatedAt" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
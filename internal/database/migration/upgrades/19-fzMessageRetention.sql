@@ -0,0 +1,4 @@
+-- v18 -> v19: Index fzMessage by timestamp for retention pruning
+
+CREATE INDEX IF NOT EXISTS "idxFzMessageTimestamp"
+ON "fzMessage" ("timestamp");
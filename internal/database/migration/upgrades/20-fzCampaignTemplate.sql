@@ -0,0 +1,3 @@
+-- v19 -> v20: Let campaigns send a stored template
+
+ALTER TABLE "fzCampaign" ADD COLUMN IF NOT EXISTS "templateId" VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- v20 -> v21: Store session alert times with their time zone
+
+ALTER TABLE "fzSession" ALTER COLUMN "alertAt" TYPE TIMESTAMPTZ;
+ALTER TABLE "fzSession" ALTER COLUMN "bannedUntil" TYPE TIMESTAMPTZ;
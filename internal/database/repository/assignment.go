@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const assignmentColumns = `"sessionId", "chat", "agentId", "status", "notes", "assignedAt", "closedAt", "createdAt", "updatedAt"`
+
+type AssignmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewAssignmentRepository(db *sqlx.DB) *AssignmentRepository {
+	return &AssignmentRepository{db: db}
+}
+
+// Get returns the assignment of a chat, or nil if it has none.
+func (r *AssignmentRepository) Get(sessionID, chat string) (*model.ChatAssignment, error) {
+	var a model.ChatAssignment
+	query := `SELECT ` + assignmentColumns + ` FROM "fzChatAssignment" WHERE "sessionId" = $1 AND "chat" = $2`
+
+	err := r.db.Get(&a, query, sessionID, chat)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (r *AssignmentRepository) List(sessionID, status, agentID string) ([]model.ChatAssignment, error) {
+	assignments := []model.ChatAssignment{}
+	query := `
+		SELECT ` + assignmentColumns + ` FROM "fzChatAssignment"
+		WHERE "sessionId" = $1 AND ($2::text = '' OR "status" = $2) AND ($3::text = '' OR "agentId" = $3)
+		ORDER BY "updatedAt" DESC
+	`
+
+	if err := r.db.Select(&assignments, query, sessionID, status, agentID); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// Insert stores the assignment of a chat that has none. It returns nil when
+// the chat got an assignment meanwhile.
+func (r *AssignmentRepository) Insert(a *model.ChatAssignment) (*model.ChatAssignment, error) {
+	var saved model.ChatAssignment
+	query := `
+		INSERT INTO "fzChatAssignment" ("sessionId", "chat", "agentId", "status", "notes", "assignedAt", "closedAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT ("sessionId", "chat") DO NOTHING
+		RETURNING ` + assignmentColumns
+
+	err := r.db.Get(&saved, query, a.SessionID, a.Chat, a.AgentID, a.Status, a.Notes, a.AssignedAt, a.ClosedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// Update replaces the assignment of a chat if its agent and status are still
+// agentID and status. It returns nil when the assignment changed meanwhile,
+// so decisions taken on the read assignment cannot overwrite a concurrent
+// change.
+func (r *AssignmentRepository) Update(a *model.ChatAssignment, agentID, status string) (*model.ChatAssignment, error) {
+	var saved model.ChatAssignment
+	query := `
+		UPDATE "fzChatAssignment"
+		SET "agentId" = $3, "status" = $4, "notes" = $5, "assignedAt" = $6, "closedAt" = $7, "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "chat" = $2 AND "agentId" = $8 AND "status" = $9
+		RETURNING ` + assignmentColumns
+
+	err := r.db.Get(&saved, query, a.SessionID, a.Chat, a.AgentID, a.Status, a.Notes, a.AssignedAt, a.ClosedAt, agentID, status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// Reopen opens a closed chat again. It returns nil when the chat is not
+// closed.
+func (r *AssignmentRepository) Reopen(sessionID, chat string) (*model.ChatAssignment, error) {
+	var a model.ChatAssignment
+	query := `
+		UPDATE "fzChatAssignment"
+		SET "status" = 'open', "closedAt" = NULL, "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "chat" = $2 AND "status" = 'closed'
+		RETURNING ` + assignmentColumns
+
+	err := r.db.Get(&a, query, sessionID, chat)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// MarkPending queues a chat for an agent unless it is already open or
+// pending.
+func (r *AssignmentRepository) MarkPending(sessionID, chat string) error {
+	query := `
+		INSERT INTO "fzChatAssignment" ("sessionId", "chat", "status")
+		VALUES ($1, $2, 'pending')
+		ON CONFLICT ("sessionId", "chat") DO UPDATE
+		SET "status" = 'pending', "closedAt" = NULL, "updatedAt" = NOW()
+		WHERE "fzChatAssignment"."status" = 'closed'
+	`
+	_, err := r.db.Exec(query, sessionID, chat)
+	return err
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"fiozap/internal/model"
+)
+
+const autoReplyColumns = `"id", "sessionId", "name", "enabled", "priority", "matchType", "pattern", "caseSensitive", "chatType",
+	"allow", "deny", "window", "kind", "payload", "templateId", "quote", "cooldownSeconds", "createdAt", "updatedAt"`
+
+type AutoReplyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAutoReplyRepository(db *sqlx.DB) *AutoReplyRepository {
+	return &AutoReplyRepository{db: db}
+}
+
+func (r *AutoReplyRepository) Create(sessionID string, req *model.AutoReplyRequest) (*model.AutoReply, error) {
+	var rule model.AutoReply
+	query := `
+		INSERT INTO "fzAutoReply" ("id", "sessionId", "name", "enabled", "priority", "matchType", "pattern", "caseSensitive",
+			"chatType", "allow", "deny", "window", "kind", "payload", "templateId", "quote", "cooldownSeconds")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING ` + autoReplyColumns
+
+	err := r.db.Get(&rule, query, generateID(), sessionID, req.Name, *req.Enabled, req.Priority, req.MatchType, req.Pattern,
+		req.CaseSensitive, req.ChatType, pq.StringArray(req.Allow), pq.StringArray(req.Deny), req.Window, req.Kind,
+		[]byte(req.Payload), req.TemplateID, req.Quote, req.CooldownSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *AutoReplyRepository) GetByID(sessionID, id string) (*model.AutoReply, error) {
+	var rule model.AutoReply
+	query := `SELECT ` + autoReplyColumns + ` FROM "fzAutoReply" WHERE "sessionId" = $1 AND "id" = $2`
+
+	if err := r.db.Get(&rule, query, sessionID, id); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *AutoReplyRepository) ListBySession(sessionID string) ([]model.AutoReply, error) {
+	rules := []model.AutoReply{}
+	query := `SELECT ` + autoReplyColumns + ` FROM "fzAutoReply" WHERE "sessionId" = $1 ORDER BY "priority" DESC, "createdAt"`
+
+	if err := r.db.Select(&rules, query, sessionID); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ListEnabled returns the enabled rules of a session in the order they are
+// tried.
+func (r *AutoReplyRepository) ListEnabled(sessionID string) ([]model.AutoReply, error) {
+	rules := []model.AutoReply{}
+	query := `
+		SELECT ` + autoReplyColumns + ` FROM "fzAutoReply"
+		WHERE "sessionId" = $1 AND "enabled"
+		ORDER BY "priority" DESC, "createdAt"
+	`
+
+	if err := r.db.Select(&rules, query, sessionID); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Update replaces a rule. It returns nil when the rule does not exist.
+func (r *AutoReplyRepository) Update(sessionID, id string, req *model.AutoReplyRequest) (*model.AutoReply, error) {
+	var rule model.AutoReply
+	query := `
+		UPDATE "fzAutoReply"
+		SET "name" = $3, "enabled" = $4, "priority" = $5, "matchType" = $6, "pattern" = $7, "caseSensitive" = $8,
+			"chatType" = $9, "allow" = $10, "deny" = $11, "window" = $12, "kind" = $13, "payload" = $14,
+			"templateId" = $15, "quote" = $16, "cooldownSeconds" = $17, "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "id" = $2
+		RETURNING ` + autoReplyColumns
+
+	err := r.db.Get(&rule, query, sessionID, id, req.Name, *req.Enabled, req.Priority, req.MatchType, req.Pattern,
+		req.CaseSensitive, req.ChatType, pq.StringArray(req.Allow), pq.StringArray(req.Deny), req.Window, req.Kind,
+		[]byte(req.Payload), req.TemplateID, req.Quote, req.CooldownSeconds)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *AutoReplyRepository) Delete(sessionID, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM "fzAutoReply" WHERE "sessionId" = $1 AND "id" = $2`, sessionID, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ClaimCooldown starts the cooldown of a rule in a chat. It returns false
+// when the rule answered the chat less than cooldownSeconds ago.
+func (r *AutoReplyRepository) ClaimCooldown(ruleID, chat string, cooldownSeconds int) (bool, error) {
+	query := `
+		INSERT INTO "fzAutoReplyCooldown" ("ruleId", "chat", "sentAt")
+		VALUES ($1, $2, NOW())
+		ON CONFLICT ("ruleId", "chat") DO UPDATE
+		SET "sentAt" = NOW()
+		WHERE "fzAutoReplyCooldown"."sentAt" <= NOW() - make_interval(secs => $3)
+	`
+	res, err := r.db.Exec(query, ruleID, chat, cooldownSeconds)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ReleaseCooldown ends the cooldown of a rule in a chat, so a reply that
+// could not be sent does not keep the chat from being answered.
+func (r *AutoReplyRepository) ReleaseCooldown(ruleID, chat string) error {
+	_, err := r.db.Exec(`DELETE FROM "fzAutoReplyCooldown" WHERE "ruleId" = $1 AND "chat" = $2`, ruleID, chat)
+	return err
+}
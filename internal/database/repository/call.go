@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+type CallRepository struct {
+	db *sqlx.DB
+}
+
+func NewCallRepository(db *sqlx.DB) *CallRepository {
+	return &CallRepository{db: db}
+}
+
+// GetPolicy returns the call policy of a session, or nil if it has none.
+func (r *CallRepository) GetPolicy(sessionID string) (*model.CallPolicy, error) {
+	var policy model.CallPolicy
+	query := `
+		SELECT "sessionId", "mode", "window", "message", "updatedAt"
+		FROM "fzCallPolicy"
+		WHERE "sessionId" = $1
+	`
+
+	err := r.db.Get(&policy, query, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+func (r *CallRepository) SavePolicy(sessionID string, req *model.CallPolicyRequest) (*model.CallPolicy, error) {
+	query := `
+		INSERT INTO "fzCallPolicy" ("sessionId", "mode", "window", "message")
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ("sessionId") DO UPDATE
+		SET "mode" = EXCLUDED."mode", "window" = EXCLUDED."window", "message" = EXCLUDED."message", "updatedAt" = NOW()
+	`
+	if _, err := r.db.Exec(query, sessionID, req.Mode, req.Window, req.Message); err != nil {
+		return nil, err
+	}
+
+	return r.GetPolicy(sessionID)
+}
+
+func (r *CallRepository) DeletePolicy(sessionID string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM "fzCallPolicy" WHERE "sessionId" = $1`, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *CallRepository) AddLog(entry *model.CallLog) error {
+	entry.ID = generateID()
+	query := `
+		INSERT INTO "fzCallLog" ("id", "sessionId", "callId", "caller", "mode", "messageSent", "error")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(query, entry.ID, entry.SessionID, entry.CallID, entry.Caller, entry.Mode, entry.MessageSent, entry.Error)
+	return err
+}
+
+func (r *CallRepository) ListLogs(sessionID string, limit int) ([]model.CallLog, error) {
+	logs := []model.CallLog{}
+	query := `
+		SELECT "id", "sessionId", "callId", "caller", "mode", "messageSent", "error", "createdAt"
+		FROM "fzCallLog"
+		WHERE "sessionId" = $1
+		ORDER BY "createdAt" DESC
+		LIMIT $2
+	`
+
+	if err := r.db.Select(&logs, query, sessionID, limit); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"fiozap/internal/model"
+)
+
+const (
+	campaignColumns  = `"id", "userId", "sessionId", "name", "kind", "payload", "templateId", "ratePerMinute", "jitterSeconds", "window", "status", "leaseOwner", "leaseUntil", "createdAt", "updatedAt", "completedAt"`
+	recipientColumns = `"campaignId", "phone", "variables", "status", "error", "messageId", "updatedAt"`
+
+	recipientInsertBatch = 1000
+)
+
+type CampaignRepository struct {
+	db *sqlx.DB
+}
+
+func NewCampaignRepository(db *sqlx.DB) *CampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+// Create inserts a campaign together with its recipients, so a failure
+// leaves no campaign behind.
+func (r *CampaignRepository) Create(userID, sessionID string, req *model.CampaignCreateRequest, recipients []model.CampaignRecipientInput) (*model.Campaign, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var campaign model.Campaign
+	query := `
+		INSERT INTO "fzCampaign" ("id", "userId", "sessionId", "name", "kind", "payload", "templateId", "ratePerMinute", "jitterSeconds", "window")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + campaignColumns
+
+	err = tx.Get(&campaign, query, generateID(), userID, sessionID, req.Name, req.Kind, []byte(req.Payload),
+		req.TemplateID, req.RatePerMinute, req.JitterSeconds, req.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := addRecipients(tx, campaign.ID, recipients); err != nil {
+		return nil, err
+	}
+
+	return &campaign, tx.Commit()
+}
+
+func (r *CampaignRepository) GetByID(id string) (*model.Campaign, error) {
+	var campaign model.Campaign
+	query := `SELECT ` + campaignColumns + ` FROM "fzCampaign" WHERE "id" = $1`
+
+	if err := r.db.Get(&campaign, query, id); err != nil {
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+func (r *CampaignRepository) GetBySession(sessionID, id string) (*model.Campaign, error) {
+	var campaign model.Campaign
+	query := `SELECT ` + campaignColumns + ` FROM "fzCampaign" WHERE "sessionId" = $1 AND "id" = $2`
+
+	if err := r.db.Get(&campaign, query, sessionID, id); err != nil {
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+func (r *CampaignRepository) ListBySession(sessionID string) ([]model.Campaign, error) {
+	campaigns := []model.Campaign{}
+	query := `SELECT ` + campaignColumns + ` FROM "fzCampaign" WHERE "sessionId" = $1 ORDER BY "createdAt" DESC`
+
+	if err := r.db.Select(&campaigns, query, sessionID); err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+func (r *CampaignRepository) ListRunning() ([]model.Campaign, error) {
+	campaigns := []model.Campaign{}
+	query := `SELECT ` + campaignColumns + ` FROM "fzCampaign" WHERE "status" = 'running'`
+
+	if err := r.db.Select(&campaigns, query); err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// UpdateStatus moves a campaign to status if its current status is one of
+// from. It reports false when the campaign does not exist or is in another
+// status.
+func (r *CampaignRepository) UpdateStatus(sessionID, id, status string, from ...string) (bool, error) {
+	query := `
+		UPDATE "fzCampaign"
+		SET "status" = $3, "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "id" = $2 AND "status" = ANY($4)
+	`
+	res, err := r.db.Exec(query, sessionID, id, status, pq.Array(from))
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Complete marks a running campaign as completed. It reports false when the
+// campaign was paused or canceled meanwhile.
+func (r *CampaignRepository) Complete(id string) (bool, error) {
+	query := `
+		UPDATE "fzCampaign"
+		SET "status" = 'completed', "completedAt" = NOW(), "updatedAt" = NOW(), "leaseOwner" = '', "leaseUntil" = NULL
+		WHERE "id" = $1 AND "status" = 'running'
+	`
+	res, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// AcquireLease gives owner the exclusive right to run a campaign for ttl. It
+// succeeds when the campaign is running and unleased, its lease expired, or
+// owner already holds it.
+func (r *CampaignRepository) AcquireLease(id, owner string, ttl time.Duration) (bool, error) {
+	query := `
+		UPDATE "fzCampaign"
+		SET "leaseOwner" = $2, "leaseUntil" = NOW() + make_interval(secs => $3)
+		WHERE "id" = $1 AND "status" = 'running'
+		  AND ("leaseUntil" IS NULL OR "leaseUntil" < NOW() OR "leaseOwner" = $2)
+	`
+	res, err := r.db.Exec(query, id, owner, ttl.Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *CampaignRepository) ReleaseLease(id, owner string) error {
+	query := `UPDATE "fzCampaign" SET "leaseOwner" = '', "leaseUntil" = NULL WHERE "id" = $1 AND "leaseOwner" = $2`
+	_, err := r.db.Exec(query, id, owner)
+	return err
+}
+
+func (r *CampaignRepository) Progress(id string) (*model.CampaignProgress, error) {
+	var progress model.CampaignProgress
+	query := `
+		SELECT
+			COUNT(*) AS "total",
+			COUNT(*) FILTER (WHERE "status" = 'pending') AS "pending",
+			COUNT(*) FILTER (WHERE "status" = 'sending') AS "sending",
+			COUNT(*) FILTER (WHERE "status" = 'sent') AS "sent",
+			COUNT(*) FILTER (WHERE "status" = 'failed') AS "failed",
+			COUNT(*) FILTER (WHERE "status" = 'invalid') AS "invalid"
+		FROM "fzCampaignRecipient"
+		WHERE "campaignId" = $1
+	`
+
+	if err := r.db.Get(&progress, query, id); err != nil {
+		return nil, err
+	}
+
+	return &progress, nil
+}
+
+// AddRecipients inserts recipients in batches, skipping phones already in the
+// campaign. It returns the number of recipients added.
+func (r *CampaignRepository) AddRecipients(campaignID string, recipients []model.CampaignRecipientInput) (int, error) {
+	return addRecipients(r.db, campaignID, recipients)
+}
+
+func addRecipients(db sqlx.Execer, campaignID string, recipients []model.CampaignRecipientInput) (int, error) {
+	added := 0
+
+	for start := 0; start < len(recipients); start += recipientInsertBatch {
+		end := min(start+recipientInsertBatch, len(recipients))
+		batch := recipients[start:end]
+
+		values := make([]string, 0, len(batch))
+		args := make([]interface{}, 0, len(batch)*3)
+		for i, rec := range batch {
+			vars, err := json.Marshal(rec.Variables)
+			if err != nil {
+				return added, err
+			}
+			if rec.Variables == nil {
+				vars = []byte("{}")
+			}
+			values = append(values, fmt.Sprintf("($%d, $%d, $%d)", i*3+1, i*3+2, i*3+3))
+			args = append(args, campaignID, rec.Phone, vars)
+		}
+
+		query := `
+			INSERT INTO "fzCampaignRecipient" ("campaignId", "phone", "variables")
+			VALUES ` + strings.Join(values, ", ") + `
+			ON CONFLICT ("campaignId", "phone") DO NOTHING
+		`
+		res, err := db.Exec(query, args...)
+		if err != nil {
+			return added, err
+		}
+
+		n, _ := res.RowsAffected()
+		added += int(n)
+	}
+
+	return added, nil
+}
+
+func (r *CampaignRepository) ListRecipients(campaignID, status string, limit, offset int) ([]model.CampaignRecipient, error) {
+	recipients := []model.CampaignRecipient{}
+	query := `
+		SELECT ` + recipientColumns + `
+		FROM "fzCampaignRecipient"
+		WHERE "campaignId" = $1 AND ($2::text = '' OR "status" = $2)
+		ORDER BY "position"
+		LIMIT $3 OFFSET $4
+	`
+
+	if err := r.db.Select(&recipients, query, campaignID, status, limit, offset); err != nil {
+		return nil, err
+	}
+
+	return recipients, nil
+}
+
+// ClaimRecipient marks the next pending recipient as sending and returns it,
+// or nil when none is left.
+func (r *CampaignRepository) ClaimRecipient(campaignID string) (*model.CampaignRecipient, error) {
+	var recipient model.CampaignRecipient
+	query := `
+		UPDATE "fzCampaignRecipient"
+		SET "status" = 'sending', "updatedAt" = NOW()
+		WHERE ("campaignId", "phone") = (
+			SELECT "campaignId", "phone" FROM "fzCampaignRecipient"
+			WHERE "campaignId" = $1 AND "status" = 'pending'
+			ORDER BY "position"
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + recipientColumns
+
+	if err := r.db.Get(&recipient, query, campaignID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &recipient, nil
+}
+
+func (r *CampaignRepository) UpdateRecipient(campaignID, phone, status, reason, messageID string) error {
+	query := `
+		UPDATE "fzCampaignRecipient"
+		SET "status" = $3, "error" = $4, "messageId" = $5, "updatedAt" = NOW()
+		WHERE "campaignId" = $1 AND "phone" = $2
+	`
+	_, err := r.db.Exec(query, campaignID, phone, status, reason, messageID)
+	return err
+}
+
+// ReleaseRecipient returns a claimed recipient to pending without sending.
+func (r *CampaignRepository) ReleaseRecipient(campaignID, phone string) error {
+	return r.UpdateRecipient(campaignID, phone, model.RecipientStatusPending, "", "")
+}
+
+// FailStaleRecipients fails recipients left sending by an instance that
+// stopped mid-send. They are not retried, since the send may have gone
+// through.
+func (r *CampaignRepository) FailStaleRecipients(campaignID string, olderThan time.Duration) error {
+	query := `
+		UPDATE "fzCampaignRecipient"
+		SET "status" = 'failed', "error" = 'interrupted while sending', "updatedAt" = NOW()
+		WHERE "campaignId" = $1 AND "status" = 'sending' AND "updatedAt" < NOW() - make_interval(secs => $2)
+	`
+	_, err := r.db.Exec(query, campaignID, olderThan.Seconds())
+	return err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const chatwootColumns = `"sessionId", "enabled", "baseUrl", "accountId", "inboxId", "apiToken", "webhookToken", "ignoreGroups", "createdAt", "updatedAt"`
+
+type ChatwootRepository struct {
+	db *sqlx.DB
+}
+
+func NewChatwootRepository(db *sqlx.DB) *ChatwootRepository {
+	return &ChatwootRepository{db: db}
+}
+
+// Get returns the Chatwoot configuration of a session, or nil if it has none.
+func (r *ChatwootRepository) Get(sessionID string) (*model.ChatwootConfig, error) {
+	var cfg model.ChatwootConfig
+	query := `SELECT ` + chatwootColumns + ` FROM "fzChatwoot" WHERE "sessionId" = $1`
+
+	err := r.db.Get(&cfg, query, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// Save stores the Chatwoot configuration of a session. The webhook token of
+// an existing configuration is kept.
+func (r *ChatwootRepository) Save(cfg *model.ChatwootConfig) (*model.ChatwootConfig, error) {
+	query := `
+		INSERT INTO "fzChatwoot" ("sessionId", "enabled", "baseUrl", "accountId", "inboxId", "apiToken", "webhookToken", "ignoreGroups")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT ("sessionId") DO UPDATE
+		SET "enabled" = EXCLUDED."enabled", "baseUrl" = EXCLUDED."baseUrl", "accountId" = EXCLUDED."accountId",
+		    "inboxId" = EXCLUDED."inboxId", "apiToken" = EXCLUDED."apiToken", "ignoreGroups" = EXCLUDED."ignoreGroups",
+		    "updatedAt" = NOW()
+	`
+	_, err := r.db.Exec(query, cfg.SessionID, cfg.Enabled, cfg.BaseURL, cfg.AccountID, cfg.InboxID,
+		cfg.APIToken, generateID(), cfg.IgnoreGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(cfg.SessionID)
+}
+
+// Delete removes the Chatwoot configuration of a session together with its
+// conversation links.
+func (r *ChatwootRepository) Delete(sessionID string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM "fzChatwoot" WHERE "sessionId" = $1`, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetConversation returns the conversation linked to a chat, or nil if it has
+// none.
+func (r *ChatwootRepository) GetConversation(sessionID, chat string) (*model.ChatwootConversation, error) {
+	var conv model.ChatwootConversation
+	query := `
+		SELECT "sessionId", "chat", "contactId", "conversationId", "createdAt"
+		FROM "fzChatwootConversation"
+		WHERE "sessionId" = $1 AND "chat" = $2
+	`
+
+	err := r.db.Get(&conv, query, sessionID, chat)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &conv, nil
+}
+
+// GetChat returns the chat linked to a Chatwoot conversation, or "" if no
+// chat is.
+func (r *ChatwootRepository) GetChat(sessionID string, conversationID int64) (string, error) {
+	var chat string
+	query := `
+		SELECT "chat" FROM "fzChatwootConversation"
+		WHERE "sessionId" = $1 AND "conversationId" = $2
+		ORDER BY "createdAt" DESC
+		LIMIT 1
+	`
+
+	err := r.db.Get(&chat, query, sessionID, conversationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return chat, err
+}
+
+func (r *ChatwootRepository) SaveConversation(conv *model.ChatwootConversation) error {
+	query := `
+		INSERT INTO "fzChatwootConversation" ("sessionId", "chat", "contactId", "conversationId")
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ("sessionId", "chat") DO UPDATE
+		SET "contactId" = EXCLUDED."contactId", "conversationId" = EXCLUDED."conversationId", "createdAt" = NOW()
+	`
+	_, err := r.db.Exec(query, conv.SessionID, conv.Chat, conv.ContactID, conv.ConversationID)
+	return err
+}
+
+func (r *ChatwootRepository) DeleteConversation(sessionID, chat string) error {
+	_, err := r.db.Exec(`DELETE FROM "fzChatwootConversation" WHERE "sessionId" = $1 AND "chat" = $2`, sessionID, chat)
+	return err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const connectorColumns = `"sessionId", "enabled", "url", "authToken", "timeoutSeconds", "debounceMs", "fallbackMessage", "ignoreGroups", "createdAt", "updatedAt"`
+
+type ConnectorRepository struct {
+	db *sqlx.DB
+}
+
+func NewConnectorRepository(db *sqlx.DB) *ConnectorRepository {
+	return &ConnectorRepository{db: db}
+}
+
+// Get returns the connector of a session, or nil if it has none.
+func (r *ConnectorRepository) Get(sessionID string) (*model.Connector, error) {
+	var c model.Connector
+	query := `SELECT ` + connectorColumns + ` FROM "fzConnector" WHERE "sessionId" = $1`
+
+	err := r.db.Get(&c, query, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (r *ConnectorRepository) Save(c *model.Connector) (*model.Connector, error) {
+	query := `
+		INSERT INTO "fzConnector" ("sessionId", "enabled", "url", "authToken", "timeoutSeconds", "debounceMs", "fallbackMessage", "ignoreGroups")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT ("sessionId") DO UPDATE
+		SET "enabled" = EXCLUDED."enabled", "url" = EXCLUDED."url", "authToken" = EXCLUDED."authToken",
+		    "timeoutSeconds" = EXCLUDED."timeoutSeconds", "debounceMs" = EXCLUDED."debounceMs",
+		    "fallbackMessage" = EXCLUDED."fallbackMessage", "ignoreGroups" = EXCLUDED."ignoreGroups", "updatedAt" = NOW()
+	`
+	_, err := r.db.Exec(query, c.SessionID, c.Enabled, c.URL, c.AuthToken, c.TimeoutSeconds, c.DebounceMs,
+		c.FallbackMessage, c.IgnoreGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(c.SessionID)
+}
+
+func (r *ConnectorRepository) Delete(sessionID string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM "fzConnector" WHERE "sessionId" = $1`, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const flowStateColumns = `"sessionId", "chat", "state", "status", "expiresAt", "createdAt", "updatedAt"`
+
+type FlowRepository struct {
+	db *sqlx.DB
+}
+
+func NewFlowRepository(db *sqlx.DB) *FlowRepository {
+	return &FlowRepository{db: db}
+}
+
+// Get returns the flow of a session, or nil if it has none.
+func (r *FlowRepository) Get(sessionID string) (*model.Flow, error) {
+	var flow model.Flow
+	query := `SELECT "sessionId", "definition", "enabled", "updatedAt" FROM "fzFlow" WHERE "sessionId" = $1`
+
+	err := r.db.Get(&flow, query, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &flow, nil
+}
+
+func (r *FlowRepository) Save(sessionID string, definition []byte, enabled bool) (*model.Flow, error) {
+	var flow model.Flow
+	query := `
+		INSERT INTO "fzFlow" ("sessionId", "definition", "enabled")
+		VALUES ($1, $2, $3)
+		ON CONFLICT ("sessionId") DO UPDATE
+		SET "definition" = EXCLUDED."definition", "enabled" = EXCLUDED."enabled", "updatedAt" = NOW()
+		RETURNING "sessionId", "definition", "enabled", "updatedAt"
+	`
+
+	if err := r.db.Get(&flow, query, sessionID, definition, enabled); err != nil {
+		return nil, err
+	}
+
+	return &flow, nil
+}
+
+// Delete removes the flow of a session together with its conversations.
+func (r *FlowRepository) Delete(sessionID string) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM "fzFlowState" WHERE "sessionId" = $1`, sessionID); err != nil {
+		return false, err
+	}
+
+	res, err := tx.Exec(`DELETE FROM "fzFlow" WHERE "sessionId" = $1`, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, tx.Commit()
+}
+
+// GetState returns the conversation of a chat, or nil if it has none.
+func (r *FlowRepository) GetState(sessionID, chat string) (*model.FlowState, error) {
+	var state model.FlowState
+	query := `SELECT ` + flowStateColumns + ` FROM "fzFlowState" WHERE "sessionId" = $1 AND "chat" = $2`
+
+	err := r.db.Get(&state, query, sessionID, chat)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func (r *FlowRepository) SaveState(sessionID, chat, state, status string, expiresAt *time.Time) error {
+	query := `
+		INSERT INTO "fzFlowState" ("sessionId", "chat", "state", "status", "expiresAt")
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ("sessionId", "chat") DO UPDATE
+		SET "state" = EXCLUDED."state", "status" = EXCLUDED."status", "expiresAt" = EXCLUDED."expiresAt", "updatedAt" = NOW()
+	`
+	_, err := r.db.Exec(query, sessionID, chat, state, status, expiresAt)
+	return err
+}
+
+func (r *FlowRepository) DeleteState(sessionID, chat string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM "fzFlowState" WHERE "sessionId" = $1 AND "chat" = $2`, sessionID, chat)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListStates returns the conversations of a session that have not expired.
+func (r *FlowRepository) ListStates(sessionID, status string) ([]model.FlowState, error) {
+	states := []model.FlowState{}
+	query := `
+		SELECT ` + flowStateColumns + ` FROM "fzFlowState"
+		WHERE "sessionId" = $1 AND ($2::text = '' OR "status" = $2)
+		  AND ("expiresAt" IS NULL OR "expiresAt" > NOW())
+		ORDER BY "updatedAt" DESC
+	`
+
+	if err := r.db.Select(&states, query, sessionID, status); err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const idempotencyColumns = `"userId", "key", "requestHash", "status", "statusCode", "contentType", "responseBody", "createdAt", "expiresAt"`
+
+type IdempotencyRepository struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve claims key for a new request. It reports true when the key was
+// free or expired; otherwise it returns the record of the earlier request.
+func (r *IdempotencyRepository) Reserve(userID, key, requestHash string, ttl time.Duration) (*model.IdempotencyRecord, bool, error) {
+	var record model.IdempotencyRecord
+	query := `
+		INSERT INTO "fzIdempotency" ("userId", "key", "requestHash", "expiresAt")
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+		ON CONFLICT ("userId", "key") DO UPDATE
+		SET "requestHash" = EXCLUDED."requestHash", "status" = 'processing', "statusCode" = 0,
+			"contentType" = '', "responseBody" = NULL, "createdAt" = NOW(), "expiresAt" = EXCLUDED."expiresAt"
+		WHERE "fzIdempotency"."expiresAt" < NOW()
+		RETURNING ` + idempotencyColumns
+
+	err := r.db.Get(&record, query, userID, key, requestHash, ttl.Seconds())
+	if err == nil {
+		return &record, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, err
+	}
+
+	query = `SELECT ` + idempotencyColumns + ` FROM "fzIdempotency" WHERE "userId" = $1 AND "key" = $2`
+	if err := r.db.Get(&record, query, userID, key); err != nil {
+		return nil, false, err
+	}
+
+	return &record, false, nil
+}
+
+func (r *IdempotencyRepository) Complete(userID, key string, statusCode int, contentType string, body []byte) error {
+	query := `
+		UPDATE "fzIdempotency"
+		SET "status" = 'done', "statusCode" = $3, "contentType" = $4, "responseBody" = $5
+		WHERE "userId" = $1 AND "key" = $2
+	`
+	_, err := r.db.Exec(query, userID, key, statusCode, contentType, body)
+	return err
+}
+
+// Release frees a reserved key so the request can be retried.
+func (r *IdempotencyRepository) Release(userID, key string) error {
+	_, err := r.db.Exec(`DELETE FROM "fzIdempotency" WHERE "userId" = $1 AND "key" = $2`, userID, key)
+	return err
+}
+
+func (r *IdempotencyRepository) DeleteExpired() error {
+	_, err := r.db.Exec(`DELETE FROM "fzIdempotency" WHERE "expiresAt" < NOW()`)
+	return err
+}
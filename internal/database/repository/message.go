@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+type MessageRepository struct {
+	db *sqlx.DB
+}
+
+func NewMessageRepository(db *sqlx.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+func (r *MessageRepository) Save(msg *model.StoredMessage) error {
+	query := `
+		INSERT INTO "fzMessage" ("id", "sessionId", "chat", "sender", "fromMe", "type", "text", "raw", "timestamp")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT ("sessionId", "id") DO NOTHING
+	`
+	_, err := r.db.Exec(query, msg.ID, msg.SessionID, msg.Chat, msg.Sender, msg.FromMe, msg.Type, msg.Text, msg.Raw, msg.Timestamp)
+	return err
+}
+
+func (r *MessageRepository) GetByID(sessionID, id string) (*model.StoredMessage, error) {
+	var msg model.StoredMessage
+	query := `
+		SELECT "id", "sessionId", "chat", "sender", "fromMe", "type", "text", "raw", "timestamp", "createdAt",
+			"editedAt", "revoked", "reactions"
+		FROM "fzMessage"
+		WHERE "sessionId" = $1 AND "id" = $2
+	`
+
+	if err := r.db.Get(&msg, query, sessionID, id); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+func (r *MessageRepository) Edit(sessionID, id, text string, editedAt time.Time) error {
+	query := `UPDATE "fzMessage" SET "text" = $3, "editedAt" = $4 WHERE "sessionId" = $1 AND "id" = $2`
+	_, err := r.db.Exec(query, sessionID, id, text, editedAt)
+	return err
+}
+
+// Revoke marks a message as deleted for everyone and drops its content.
+func (r *MessageRepository) Revoke(sessionID, id string) error {
+	query := `UPDATE "fzMessage" SET "revoked" = TRUE, "text" = '', "raw" = NULL WHERE "sessionId" = $1 AND "id" = $2`
+	_, err := r.db.Exec(query, sessionID, id)
+	return err
+}
+
+// SetReaction records the reaction of actor to a message. An empty emoji
+// removes it.
+func (r *MessageRepository) SetReaction(sessionID, id, actor, emoji string) error {
+	query := `
+		UPDATE "fzMessage"
+		SET "reactions" = CASE WHEN $4::text = '' THEN "reactions" - $3::text
+			ELSE "reactions" || jsonb_build_object($3::text, $4::text) END
+		WHERE "sessionId" = $1 AND "id" = $2
+	`
+	_, err := r.db.Exec(query, sessionID, id, actor, emoji)
+	return err
+}
+
+// DeleteOlderThan removes messages sent before cutoff and returns how many
+// were removed.
+func (r *MessageRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM "fzMessage" WHERE "timestamp" < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
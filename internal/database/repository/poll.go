@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"fiozap/internal/model"
+)
+
+type PollRepository struct {
+	db *sqlx.DB
+}
+
+func NewPollRepository(db *sqlx.DB) *PollRepository {
+	return &PollRepository{db: db}
+}
+
+func (r *PollRepository) Save(poll *model.Poll) error {
+	query := `
+		INSERT INTO "fzPoll" ("id", "sessionId", "chat", "sender", "name", "options", "selectableCount")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT ("sessionId", "id") DO NOTHING
+	`
+	_, err := r.db.Exec(query, poll.ID, poll.SessionID, poll.Chat, poll.Sender, poll.Name, poll.Options, poll.SelectableCount)
+	return err
+}
+
+func (r *PollRepository) GetByID(sessionID, id string) (*model.Poll, error) {
+	var poll model.Poll
+	query := `
+		SELECT "id", "sessionId", "chat", "sender", "name", "options", "selectableCount", "createdAt"
+		FROM "fzPoll"
+		WHERE "sessionId" = $1 AND "id" = $2
+	`
+
+	if err := r.db.Get(&poll, query, sessionID, id); err != nil {
+		return nil, err
+	}
+
+	return &poll, nil
+}
+
+// SaveVote stores the selection of a voter, replacing an older one. Votes
+// arriving out of order do not overwrite newer ones.
+func (r *PollRepository) SaveVote(sessionID, pollID, voter string, options []string, timestamp time.Time) error {
+	query := `
+		INSERT INTO "fzPollVote" ("pollId", "sessionId", "voter", "options", "timestamp")
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ("sessionId", "pollId", "voter") DO UPDATE
+		SET "options" = EXCLUDED."options", "timestamp" = EXCLUDED."timestamp"
+		WHERE "fzPollVote"."timestamp" <= EXCLUDED."timestamp"
+	`
+	_, err := r.db.Exec(query, pollID, sessionID, voter, pq.StringArray(options), timestamp)
+	return err
+}
+
+func (r *PollRepository) ListVotes(sessionID, pollID string) ([]model.PollVote, error) {
+	votes := []model.PollVote{}
+	query := `
+		SELECT "pollId", "sessionId", "voter", "options", "timestamp"
+		FROM "fzPollVote"
+		WHERE "sessionId" = $1 AND "pollId" = $2
+		ORDER BY "timestamp"
+	`
+
+	if err := r.db.Select(&votes, query, sessionID, pollID); err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
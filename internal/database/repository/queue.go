@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const queueColumns = `"id", "userId", "sessionId", "kind", "payload", "status", "attempts", "error", "messageId", "notBefore", "createdAt", "updatedAt"`
+
+type QueueRepository struct {
+	db *sqlx.DB
+}
+
+func NewQueueRepository(db *sqlx.DB) *QueueRepository {
+	return &QueueRepository{db: db}
+}
+
+func (r *QueueRepository) Create(userID, sessionID, kind string, payload []byte, messageID string) (*model.QueueItem, error) {
+	var item model.QueueItem
+	query := `
+		INSERT INTO "fzMessageQueue" ("id", "userId", "sessionId", "kind", "payload", "messageId")
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + queueColumns
+
+	if err := r.db.Get(&item, query, generateID(), userID, sessionID, kind, payload, messageID); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *QueueRepository) GetByID(sessionID, id string) (*model.QueueItem, error) {
+	var item model.QueueItem
+	query := `SELECT ` + queueColumns + ` FROM "fzMessageQueue" WHERE "sessionId" = $1 AND "id" = $2`
+
+	if err := r.db.Get(&item, query, sessionID, id); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *QueueRepository) ListBySession(sessionID, status string, limit int) ([]model.QueueItem, error) {
+	items := []model.QueueItem{}
+	query := `
+		SELECT ` + queueColumns + `
+		FROM "fzMessageQueue"
+		WHERE "sessionId" = $1 AND ($2::text = '' OR "status" = $2)
+		ORDER BY "createdAt" DESC
+		LIMIT $3
+	`
+
+	if err := r.db.Select(&items, query, sessionID, status, limit); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// PendingSessions returns the sessions that have items ready to be sent.
+func (r *QueueRepository) PendingSessions() ([]string, error) {
+	var sessions []string
+	query := `
+		SELECT DISTINCT "sessionId"
+		FROM "fzMessageQueue"
+		WHERE "status" = 'pending' AND "notBefore" <= NOW()
+	`
+	err := r.db.Select(&sessions, query)
+	return sessions, err
+}
+
+// ClaimNext marks the oldest ready item of a session as sending and returns
+// it, or nil when there is none. SKIP LOCKED keeps concurrent workers from
+// claiming the same row.
+func (r *QueueRepository) ClaimNext(sessionID string) (*model.QueueItem, error) {
+	var item model.QueueItem
+	query := `
+		UPDATE "fzMessageQueue"
+		SET "status" = 'sending', "attempts" = "attempts" + 1, "updatedAt" = NOW()
+		WHERE "id" = (
+			SELECT "id" FROM "fzMessageQueue"
+			WHERE "sessionId" = $1 AND "status" = 'pending' AND "notBefore" <= NOW()
+			ORDER BY "notBefore", "createdAt"
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + queueColumns
+
+	if err := r.db.Get(&item, query, sessionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *QueueRepository) MarkSent(id, messageID string) error {
+	query := `UPDATE "fzMessageQueue" SET "status" = 'sent', "messageId" = $2, "error" = '', "updatedAt" = NOW() WHERE "id" = $1`
+	_, err := r.db.Exec(query, id, messageID)
+	return err
+}
+
+func (r *QueueRepository) MarkFailed(id, reason string) error {
+	query := `UPDATE "fzMessageQueue" SET "status" = 'failed', "error" = $2, "updatedAt" = NOW() WHERE "id" = $1`
+	_, err := r.db.Exec(query, id, reason)
+	return err
+}
+
+// Retry puts a claimed item back in the queue to be sent again after delay.
+func (r *QueueRepository) Retry(id, reason string, delay time.Duration) error {
+	query := `
+		UPDATE "fzMessageQueue"
+		SET "status" = 'pending', "error" = $2, "notBefore" = NOW() + make_interval(secs => $3), "updatedAt" = NOW()
+		WHERE "id" = $1
+	`
+	_, err := r.db.Exec(query, id, reason, delay.Seconds())
+	return err
+}
+
+// Release returns a claimed item to the queue without counting the attempt.
+func (r *QueueRepository) Release(id string) error {
+	query := `UPDATE "fzMessageQueue" SET "status" = 'pending', "attempts" = "attempts" - 1, "updatedAt" = NOW() WHERE "id" = $1`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// ReleaseStale releases items claimed longer than olderThan ago, left behind
+// by a worker that stopped mid-send. Items claimed by live workers are kept.
+func (r *QueueRepository) ReleaseStale(olderThan time.Duration) error {
+	query := `
+		UPDATE "fzMessageQueue"
+		SET "status" = 'pending', "updatedAt" = NOW()
+		WHERE "status" = 'sending' AND "updatedAt" < NOW() - make_interval(secs => $1)
+	`
+	_, err := r.db.Exec(query, olderThan.Seconds())
+	return err
+}
+
+// Cancel cancels a pending item. It reports false when the item does not
+// exist or is no longer pending.
+func (r *QueueRepository) Cancel(sessionID, id string) (bool, error) {
+	query := `
+		UPDATE "fzMessageQueue"
+		SET "status" = 'canceled', "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "id" = $2 AND "status" = 'pending'
+	`
+	res, err := r.db.Exec(query, sessionID, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
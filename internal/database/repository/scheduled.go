@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const scheduledColumns = `"id", "userId", "sessionId", "kind", "payload", "sendAt", "timezone", "status", "error", "messageId", "createdAt", "updatedAt"`
+
+type ScheduledRepository struct {
+	db *sqlx.DB
+}
+
+func NewScheduledRepository(db *sqlx.DB) *ScheduledRepository {
+	return &ScheduledRepository{db: db}
+}
+
+func (r *ScheduledRepository) Create(userID, sessionID, kind string, payload []byte, sendAt time.Time, timezone string) (*model.ScheduledMessage, error) {
+	var msg model.ScheduledMessage
+	query := `
+		INSERT INTO "fzScheduled" ("id", "userId", "sessionId", "kind", "payload", "sendAt", "timezone")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + scheduledColumns
+
+	if err := r.db.Get(&msg, query, generateID(), userID, sessionID, kind, payload, sendAt, timezone); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+func (r *ScheduledRepository) GetByID(sessionID, id string) (*model.ScheduledMessage, error) {
+	var msg model.ScheduledMessage
+	query := `SELECT ` + scheduledColumns + ` FROM "fzScheduled" WHERE "sessionId" = $1 AND "id" = $2`
+
+	if err := r.db.Get(&msg, query, sessionID, id); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+func (r *ScheduledRepository) ListBySession(sessionID, status string) ([]model.ScheduledMessage, error) {
+	msgs := []model.ScheduledMessage{}
+	query := `
+		SELECT ` + scheduledColumns + `
+		FROM "fzScheduled"
+		WHERE "sessionId" = $1 AND ($2::text = '' OR "status" = $2)
+		ORDER BY "sendAt" ASC
+	`
+
+	if err := r.db.Select(&msgs, query, sessionID, status); err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// ClaimDue marks up to limit due messages as sending and returns them. SKIP
+// LOCKED keeps other instances from claiming the same rows.
+func (r *ScheduledRepository) ClaimDue(limit int) ([]model.ScheduledMessage, error) {
+	msgs := []model.ScheduledMessage{}
+	query := `
+		UPDATE "fzScheduled"
+		SET "status" = 'sending', "updatedAt" = NOW()
+		WHERE "id" IN (
+			SELECT "id" FROM "fzScheduled"
+			WHERE "status" = 'scheduled' AND "sendAt" <= NOW()
+			ORDER BY "sendAt"
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + scheduledColumns
+
+	if err := r.db.Select(&msgs, query, limit); err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// Touch refreshes the claim of a message right before it is sent, so a long
+// batch does not go stale. It reports false when the message is no longer
+// being sent, e.g. because another instance failed it as stale.
+func (r *ScheduledRepository) Touch(id string) (bool, error) {
+	query := `UPDATE "fzScheduled" SET "updatedAt" = NOW() WHERE "id" = $1 AND "status" = 'sending'`
+	res, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MarkSent records the sent message. It reports false when the message was
+// no longer being sent.
+func (r *ScheduledRepository) MarkSent(id, messageID string) (bool, error) {
+	query := `UPDATE "fzScheduled" SET "status" = 'sent', "messageId" = $2, "updatedAt" = NOW() WHERE "id" = $1 AND "status" = 'sending'`
+	res, err := r.db.Exec(query, id, messageID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MarkFailed records why a message could not be sent. It reports false when
+// the message was no longer being sent.
+func (r *ScheduledRepository) MarkFailed(id, reason string) (bool, error) {
+	query := `UPDATE "fzScheduled" SET "status" = 'failed', "error" = $2, "updatedAt" = NOW() WHERE "id" = $1 AND "status" = 'sending'`
+	res, err := r.db.Exec(query, id, reason)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// FailStale fails messages claimed longer than olderThan ago by an instance
+// that stopped mid-send. They are not retried, since the send may have gone
+// through.
+func (r *ScheduledRepository) FailStale(olderThan time.Duration) error {
+	query := `
+		UPDATE "fzScheduled"
+		SET "status" = 'failed', "error" = 'interrupted while sending', "updatedAt" = NOW()
+		WHERE "status" = 'sending' AND "updatedAt" < NOW() - make_interval(secs => $1)
+	`
+	_, err := r.db.Exec(query, olderThan.Seconds())
+	return err
+}
+
+// Reschedule moves a scheduled message to a new time. It reports false when
+// the message does not exist or was already sent or canceled.
+func (r *ScheduledRepository) Reschedule(sessionID, id string, sendAt time.Time, timezone string) (bool, error) {
+	query := `
+		UPDATE "fzScheduled"
+		SET "sendAt" = $3, "timezone" = $4, "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "id" = $2 AND "status" = 'scheduled'
+	`
+	res, err := r.db.Exec(query, sessionID, id, sendAt, timezone)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Cancel cancels a scheduled message. It reports false when the message does
+// not exist or was already sent or canceled.
+func (r *ScheduledRepository) Cancel(sessionID, id string) (bool, error) {
+	query := `
+		UPDATE "fzScheduled"
+		SET "status" = 'canceled', "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "id" = $2 AND "status" = 'scheduled'
+	`
+	res, err := r.db.Exec(query, sessionID, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
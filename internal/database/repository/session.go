@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -35,7 +36,8 @@ func (r *SessionRepository) Create(userID string, req *model.SessionCreateReques
 func (r *SessionRepository) GetByID(id string) (*model.Session, error) {
 	var session model.Session
 	query := `
-		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl", "createdAt"
+		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl",
+		       "alert", "alertReason", "alertAt", "bannedUntil", "createdAt"
 		FROM "fzSession" 
 		WHERE "id" = $1
 	`
@@ -50,7 +52,8 @@ func (r *SessionRepository) GetByID(id string) (*model.Session, error) {
 func (r *SessionRepository) GetByUserAndName(userID, name string) (*model.Session, error) {
 	var session model.Session
 	query := `
-		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl", "createdAt"
+		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl",
+		       "alert", "alertReason", "alertAt", "bannedUntil", "createdAt"
 		FROM "fzSession" 
 		WHERE "userId" = $1 AND "name" = $2
 	`
@@ -65,7 +68,8 @@ func (r *SessionRepository) GetByUserAndName(userID, name string) (*model.Sessio
 func (r *SessionRepository) GetAllByUser(userID string) ([]model.Session, error) {
 	var sessions []model.Session
 	query := `
-		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl", "createdAt"
+		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl",
+		       "alert", "alertReason", "alertAt", "bannedUntil", "createdAt"
 		FROM "fzSession" 
 		WHERE "userId" = $1
 		ORDER BY "createdAt" DESC
@@ -81,7 +85,8 @@ func (r *SessionRepository) GetAllByUser(userID string) ([]model.Session, error)
 func (r *SessionRepository) GetAll() ([]model.Session, error) {
 	var sessions []model.Session
 	query := `
-		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl", "createdAt"
+		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl",
+		       "alert", "alertReason", "alertAt", "bannedUntil", "createdAt"
 		FROM "fzSession"
 		ORDER BY "createdAt" DESC
 	`
@@ -150,6 +155,28 @@ func (r *SessionRepository) UpdateJID(id string, jid string) error {
 	return err
 }
 
+// SetAlert records an alert on a session and marks it disconnected.
+// bannedUntil is only set for temporary bans.
+func (r *SessionRepository) SetAlert(id, alert, reason string, bannedUntil *time.Time) error {
+	query := `
+		UPDATE "fzSession"
+		SET "alert" = $1, "alertReason" = $2, "alertAt" = NOW(), "bannedUntil" = $3, "connected" = 0
+		WHERE "id" = $4
+	`
+	_, err := r.db.Exec(query, alert, reason, bannedUntil, id)
+	return err
+}
+
+func (r *SessionRepository) ClearAlert(id string) error {
+	query := `
+		UPDATE "fzSession"
+		SET "alert" = '', "alertReason" = '', "alertAt" = NULL, "bannedUntil" = NULL
+		WHERE "id" = $1 AND ("alert" <> '' OR "bannedUntil" IS NOT NULL)
+	`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
 func (r *SessionRepository) UpdateQRCode(id string, qrcode string) error {
 	query := `UPDATE "fzSession" SET "qrCode" = $1 WHERE "id" = $2`
 	_, err := r.db.Exec(query, qrcode, id)
@@ -165,7 +192,8 @@ func (r *SessionRepository) UpdateWebhook(id string, webhook string, events stri
 func (r *SessionRepository) GetConnectedSessions() ([]model.Session, error) {
 	var sessions []model.Session
 	query := `
-		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl", "createdAt"
+		SELECT "id", "userId", "name", "jid", "qrCode", "connected", "webhook", "events", "proxyUrl",
+		       "alert", "alertReason", "alertAt", "bannedUntil", "createdAt"
 		FROM "fzSession" 
 		WHERE "connected" = 1
 	`
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"fiozap/internal/model"
+)
+
+const templateColumns = `"id", "userId", "name", "kind", "payload", "defaults", "createdAt", "updatedAt"`
+
+type TemplateRepository struct {
+	db *sqlx.DB
+}
+
+func NewTemplateRepository(db *sqlx.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+func (r *TemplateRepository) Create(userID string, req *model.TemplateRequest) (*model.Template, error) {
+	defaults, err := marshalDefaults(req.Defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	var tmpl model.Template
+	query := `
+		INSERT INTO "fzTemplate" ("id", "userId", "name", "kind", "payload", "defaults")
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + templateColumns
+
+	if err := r.db.Get(&tmpl, query, generateID(), userID, req.Name, req.Kind, []byte(req.Payload), defaults); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+func (r *TemplateRepository) GetByID(userID, id string) (*model.Template, error) {
+	var tmpl model.Template
+	query := `SELECT ` + templateColumns + ` FROM "fzTemplate" WHERE "userId" = $1 AND "id" = $2`
+
+	if err := r.db.Get(&tmpl, query, userID, id); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+func (r *TemplateRepository) GetByName(userID, name string) (*model.Template, error) {
+	var tmpl model.Template
+	query := `SELECT ` + templateColumns + ` FROM "fzTemplate" WHERE "userId" = $1 AND "name" = $2`
+
+	if err := r.db.Get(&tmpl, query, userID, name); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+func (r *TemplateRepository) ListByUser(userID string) ([]model.Template, error) {
+	templates := []model.Template{}
+	query := `SELECT ` + templateColumns + ` FROM "fzTemplate" WHERE "userId" = $1 ORDER BY "name"`
+
+	if err := r.db.Select(&templates, query, userID); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// Update replaces a template. It returns nil when the template does not exist.
+func (r *TemplateRepository) Update(userID, id string, req *model.TemplateRequest) (*model.Template, error) {
+	defaults, err := marshalDefaults(req.Defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	var tmpl model.Template
+	query := `
+		UPDATE "fzTemplate"
+		SET "name" = $3, "kind" = $4, "payload" = $5, "defaults" = $6, "updatedAt" = NOW()
+		WHERE "userId" = $1 AND "id" = $2
+		RETURNING ` + templateColumns
+
+	if err := r.db.Get(&tmpl, query, userID, id, req.Name, req.Kind, []byte(req.Payload), defaults); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
+
+func (r *TemplateRepository) Delete(userID, id string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM "fzTemplate" WHERE "userId" = $1 AND "id" = $2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func marshalDefaults(defaults map[string]string) ([]byte, error) {
+	if defaults == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(defaults)
+}
@@ -28,16 +28,22 @@ func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
 }
 
 func (r *WebhookRepository) Create(userID, sessionID, eventType string, payload interface{}) error {
+	return r.CreateWithPriority(userID, sessionID, eventType, payload, 0)
+}
+
+// CreateWithPriority stores an event that is delivered before pending events
+// of a lower priority.
+func (r *WebhookRepository) CreateWithPriority(userID, sessionID, eventType string, payload interface{}, priority int) error {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
 	query := `
-		INSERT INTO "fzWebhook" ("userId", "sessionId", "eventType", "payload", "status", "attempts", "createdAt")
-		VALUES ($1, $2, $3, $4, 'pending', 0, NOW())
+		INSERT INTO "fzWebhook" ("userId", "sessionId", "eventType", "payload", "status", "attempts", "priority", "createdAt")
+		VALUES ($1, $2, $3, $4, 'pending', 0, $5, NOW())
 	`
-	_, err = r.db.Exec(query, userID, sessionID, eventType, payloadBytes)
+	_, err = r.db.Exec(query, userID, sessionID, eventType, payloadBytes, priority)
 	return err
 }
 
@@ -47,7 +53,7 @@ func (r *WebhookRepository) GetPending(limit int) ([]WebhookEvent, error) {
 		SELECT "id", "userId", COALESCE("sessionId", '') as "sessionId", "eventType", "payload", "status", "attempts", "lastAttempt", "createdAt"
 		FROM "fzWebhook"
 		WHERE "status" = 'pending' AND "attempts" < 3
-		ORDER BY "createdAt" ASC
+		ORDER BY "priority" DESC, "createdAt" ASC
 		LIMIT $1
 	`
 	err := r.db.Select(&events, query, limit)
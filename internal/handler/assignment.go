@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type AssignmentHandler struct {
+	assignmentService *service.AssignmentService
+}
+
+func NewAssignmentHandler(assignmentService *service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{assignmentService: assignmentService}
+}
+
+// List godoc
+// @Summary List chat assignments
+// @Tags Assignments
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param status query string false "Filter by status (open, pending, closed)"
+// @Param agent_id query string false "Filter by agent"
+// @Success 200 {array} model.ChatAssignment
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/assignments [get]
+func (h *AssignmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	query := r.URL.Query()
+	assignments, err := h.assignmentService.List(session.ID, query.Get("status"), query.Get("agent_id"))
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, assignments)
+}
+
+// Get godoc
+// @Summary Get chat assignment
+// @Tags Assignments
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param chat path string true "Chat JID or phone"
+// @Success 200 {object} model.ChatAssignment
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/assignments/{chat} [get]
+func (h *AssignmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	a, err := h.assignmentService.Get(session.ID, chi.URLParam(r, "chat"))
+	if err != nil {
+		respondAssignmentError(w, err)
+		return
+	}
+
+	model.RespondOK(w, a)
+}
+
+// Assign godoc
+// @Summary Assign chat
+// @Description Assigns an unassigned, pending or closed chat to an agent and emits ChatAssigned. Chats another agent is handling must be transferred
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param chat path string true "Chat JID or phone"
+// @Param request body model.ChatAssignRequest true "Assignment"
+// @Success 200 {object} model.ChatAssignment
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/assignments/{chat}/assign [post]
+func (h *AssignmentHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	h.assign(w, r, h.assignmentService.Assign)
+}
+
+// Transfer godoc
+// @Summary Transfer chat
+// @Description Moves an open or pending chat to another agent and emits ChatAssigned
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param chat path string true "Chat JID or phone"
+// @Param request body model.ChatAssignRequest true "Assignment"
+// @Success 200 {object} model.ChatAssignment
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/assignments/{chat}/transfer [post]
+func (h *AssignmentHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	h.assign(w, r, h.assignmentService.Transfer)
+}
+
+func (h *AssignmentHandler) assign(w http.ResponseWriter, r *http.Request, action func(userID, sessionID, chat string, req *model.ChatAssignRequest) (*model.ChatAssignment, error)) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ChatAssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	a, err := action(user.ID, session.ID, chi.URLParam(r, "chat"), &req)
+	if err != nil {
+		respondAssignmentError(w, err)
+		return
+	}
+
+	model.RespondOK(w, a)
+}
+
+// Close godoc
+// @Summary Close chat
+// @Description Closes a chat and emits ChatClosed. The chat is opened again when the contact writes
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param chat path string true "Chat JID or phone"
+// @Param request body model.ChatCloseRequest false "Close options"
+// @Success 200 {object} model.ChatAssignment
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/assignments/{chat}/close [post]
+func (h *AssignmentHandler) Close(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ChatCloseRequest
+	if r.Body != nil && r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			model.RespondBadRequest(w, errors.New("invalid payload"))
+			return
+		}
+	}
+
+	a, err := h.assignmentService.Close(user.ID, session.ID, chi.URLParam(r, "chat"), &req)
+	if err != nil {
+		respondAssignmentError(w, err)
+		return
+	}
+
+	model.RespondOK(w, a)
+}
+
+func respondAssignmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrAssignmentNotFound):
+		model.RespondNotFound(w, err)
+	case errors.Is(err, service.ErrChatAssigned), errors.Is(err, service.ErrChatClosed), errors.Is(err, service.ErrAssignmentConflict):
+		model.RespondError(w, http.StatusConflict, err)
+	case errors.Is(err, service.ErrInvalidAssignment), errors.Is(err, service.ErrInvalidJID):
+		model.RespondBadRequest(w, err)
+	default:
+		model.RespondInternalError(w, err)
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type AutoReplyHandler struct {
+	autoReplyService *service.AutoReplyService
+}
+
+func NewAutoReplyHandler(autoReplyService *service.AutoReplyService) *AutoReplyHandler {
+	return &AutoReplyHandler{autoReplyService: autoReplyService}
+}
+
+// Create godoc
+// @Summary Create auto-reply
+// @Description Creates a rule that answers incoming messages matching its text pattern, chat type, sender lists and business hours. Rules are tried by descending priority; the first match answers unless it answered the same chat within its cooldown
+// @Tags AutoReplies
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.AutoReplyRequest true "Auto-reply rule"
+// @Success 200 {object} model.AutoReply
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/autoreplies [post]
+func (h *AutoReplyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.AutoReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	rule, err := h.autoReplyService.Create(user.ID, session.ID, &req)
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, rule)
+}
+
+// List godoc
+// @Summary List auto-replies
+// @Tags AutoReplies
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {array} model.AutoReply
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/autoreplies [get]
+func (h *AutoReplyHandler) List(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	rules, err := h.autoReplyService.List(session.ID)
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, rules)
+}
+
+// Get godoc
+// @Summary Get auto-reply
+// @Tags AutoReplies
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param ruleId path string true "Rule ID"
+// @Success 200 {object} model.AutoReply
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/autoreplies/{ruleId} [get]
+func (h *AutoReplyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	rule, err := h.autoReplyService.Get(session.ID, chi.URLParam(r, "ruleId"))
+	if errors.Is(err, service.ErrAutoReplyNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, rule)
+}
+
+// Update godoc
+// @Summary Update auto-reply
+// @Tags AutoReplies
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param ruleId path string true "Rule ID"
+// @Param request body model.AutoReplyRequest true "Auto-reply rule"
+// @Success 200 {object} model.AutoReply
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/autoreplies/{ruleId} [put]
+func (h *AutoReplyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.AutoReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	rule, err := h.autoReplyService.Update(user.ID, session.ID, chi.URLParam(r, "ruleId"), &req)
+	if errors.Is(err, service.ErrAutoReplyNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, rule)
+}
+
+// Delete godoc
+// @Summary Delete auto-reply
+// @Tags AutoReplies
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param ruleId path string true "Rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/autoreplies/{ruleId} [delete]
+func (h *AutoReplyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	ruleID := chi.URLParam(r, "ruleId")
+	err := h.autoReplyService.Delete(session.ID, ruleID)
+	if errors.Is(err, service.ErrAutoReplyNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{
+		"rule_id": ruleID,
+		"deleted": true,
+	})
+}
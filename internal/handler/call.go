@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type CallHandler struct {
+	callService *service.CallService
+}
+
+func NewCallHandler(callService *service.CallService) *CallHandler {
+	return &CallHandler{callService: callService}
+}
+
+// GetPolicy godoc
+// @Summary Get call policy
+// @Tags Calls
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.CallPolicy
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/call/policy [get]
+func (h *CallHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	policy, err := h.callService.GetPolicy(session.ID)
+	if errors.Is(err, service.ErrCallPolicyNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, policy)
+}
+
+// SetPolicy godoc
+// @Summary Set call policy
+// @Description Rejects incoming calls automatically. Mode "always" rejects every call, "hours" rejects calls while window matches and "non_contacts" rejects callers that are not in the address book. When message is set it is sent to the caller after the call is rejected. Rejected calls are logged and emitted as CallRejected
+// @Tags Calls
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.CallPolicyRequest true "Call policy"
+// @Success 200 {object} model.CallPolicy
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/call/policy [put]
+func (h *CallHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	var req model.CallPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	policy, err := h.callService.SetPolicy(session.ID, &req)
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, policy)
+}
+
+// DeletePolicy godoc
+// @Summary Delete call policy
+// @Description Stops rejecting calls automatically
+// @Tags Calls
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/call/policy [delete]
+func (h *CallHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	if err := h.callService.DeletePolicy(session.ID); err != nil {
+		if errors.Is(err, service.ErrCallPolicyNotFound) {
+			model.RespondNotFound(w, err)
+			return
+		}
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"deleted": true})
+}
+
+// ListLog godoc
+// @Summary List rejected calls
+// @Description Returns the latest 100 calls rejected by the call policy
+// @Tags Calls
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {array} model.CallLog
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/call/log [get]
+func (h *CallHandler) ListLog(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	logs, err := h.callService.ListLog(session.ID)
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, logs)
+}
@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+const (
+	contentTypeCSV      = "text/csv"
+	maxRecipientsUpload = 32 << 20
+)
+
+type CampaignHandler struct {
+	campaignService *service.CampaignService
+}
+
+func NewCampaignHandler(campaignService *service.CampaignService) *CampaignHandler {
+	return &CampaignHandler{campaignService: campaignService}
+}
+
+// Create godoc
+// @Summary Create campaign
+// @Description Creates a draft campaign. Payload follows the scheduled message format for the given kind, or kind is template with template_id; strings may use {{name}} placeholders filled from each recipient's variables, and the phone is set per recipient. Malformed phones are skipped and listed in rejected
+// @Tags Campaigns
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.CampaignCreateRequest true "Campaign data"
+// @Success 200 {object} model.Campaign
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns [post]
+func (h *CampaignHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRecipientsUpload)
+
+	var req model.CampaignCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	campaign, err := h.campaignService.Create(user.ID, session.ID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCampaign) {
+			model.RespondBadRequest(w, err)
+			return
+		}
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, campaign)
+}
+
+// List godoc
+// @Summary List campaigns
+// @Tags Campaigns
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {array} model.Campaign
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns [get]
+func (h *CampaignHandler) List(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	campaigns, err := h.campaignService.List(session.ID)
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, campaigns)
+}
+
+// Get godoc
+// @Summary Get campaign
+// @Description Returns the campaign with its progress counters
+// @Tags Campaigns
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param campaignId path string true "Campaign ID"
+// @Success 200 {object} model.Campaign
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns/{campaignId} [get]
+func (h *CampaignHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	campaign, err := h.campaignService.Get(session.ID, chi.URLParam(r, "campaignId"))
+	if err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, campaign)
+}
+
+// AddRecipients godoc
+// @Summary Add campaign recipients
+// @Description Accepts a JSON body or a text/csv body with a header row. The CSV phone column is required and every other column becomes a variable. Duplicate and malformed numbers are skipped
+// @Tags Campaigns
+// @Accept json,text/csv
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param campaignId path string true "Campaign ID"
+// @Param request body model.CampaignRecipientsRequest true "Recipients"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns/{campaignId}/recipients [post]
+func (h *CampaignHandler) AddRecipients(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	id := chi.URLParam(r, "campaignId")
+	r.Body = http.MaxBytesReader(w, r.Body, maxRecipientsUpload)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == contentTypeCSV {
+		result, err := h.campaignService.AddRecipientsCSV(session.ID, id, r.Body)
+		if err != nil {
+			model.RespondBadRequest(w, err)
+			return
+		}
+		model.RespondOK(w, result)
+		return
+	}
+
+	var req model.CampaignRecipientsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	result, err := h.campaignService.AddRecipients(session.ID, id, req.Recipients)
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, result)
+}
+
+// ListRecipients godoc
+// @Summary List campaign recipients
+// @Description Returns up to 500 recipients in list order
+// @Tags Campaigns
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param campaignId path string true "Campaign ID"
+// @Param status query string false "Filter by status (pending, sending, sent, failed, invalid)"
+// @Param offset query int false "Number of recipients to skip"
+// @Success 200 {array} model.CampaignRecipient
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns/{campaignId}/recipients [get]
+func (h *CampaignHandler) ListRecipients(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o > 0 {
+			offset = o
+		}
+	}
+
+	recipients, err := h.campaignService.ListRecipients(session.ID, chi.URLParam(r, "campaignId"), r.URL.Query().Get("status"), offset)
+	if err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, recipients)
+}
+
+// Start godoc
+// @Summary Start campaign
+// @Description Starts sending a draft campaign, respecting its rate and time window
+// @Tags Campaigns
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param campaignId path string true "Campaign ID"
+// @Success 200 {object} model.Campaign
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns/{campaignId}/start [post]
+func (h *CampaignHandler) Start(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.campaignService.Start)
+}
+
+// Pause godoc
+// @Summary Pause campaign
+// @Tags Campaigns
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param campaignId path string true "Campaign ID"
+// @Success 200 {object} model.Campaign
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns/{campaignId}/pause [post]
+func (h *CampaignHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.campaignService.Pause)
+}
+
+// Resume godoc
+// @Summary Resume campaign
+// @Tags Campaigns
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param campaignId path string true "Campaign ID"
+// @Success 200 {object} model.Campaign
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns/{campaignId}/resume [post]
+func (h *CampaignHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.campaignService.Resume)
+}
+
+// Cancel godoc
+// @Summary Cancel campaign
+// @Description Recipients not sent yet are left pending
+// @Tags Campaigns
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param campaignId path string true "Campaign ID"
+// @Success 200 {object} model.Campaign
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/campaigns/{campaignId}/cancel [post]
+func (h *CampaignHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.campaignService.Cancel)
+}
+
+func (h *CampaignHandler) changeStatus(w http.ResponseWriter, r *http.Request, change func(sessionID, id string) (*model.Campaign, error)) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	campaign, err := change(session.ID, chi.URLParam(r, "campaignId"))
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, campaign)
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+// PinChat godoc
+// @Summary Pin chat
+// @Description pin=true/false
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.PinChatMessage true "Pin data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/pin [post]
+func (h *MessageHandler) PinChat(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.PinChatMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Phone == "" {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	result, err := h.messageService.PinChat(r.Context(), user.ID, session.ID, &req)
+	if err != nil {
+		respondChatError(w, err)
+		return
+	}
+
+	model.RespondOK(w, result)
+}
+
+// MuteChat godoc
+// @Summary Mute chat
+// @Description mute=true/false. duration_seconds limits the mute, 0 mutes forever
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.MuteChatMessage true "Mute data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/mute [post]
+func (h *MessageHandler) MuteChat(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.MuteChatMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Phone == "" {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	if req.DurationSeconds < 0 {
+		model.RespondBadRequest(w, errors.New("duration_seconds must not be negative"))
+		return
+	}
+
+	result, err := h.messageService.MuteChat(r.Context(), user.ID, session.ID, &req)
+	if err != nil {
+		respondChatError(w, err)
+		return
+	}
+
+	model.RespondOK(w, result)
+}
+
+// ReadChat godoc
+// @Summary Mark chat as read or unread
+// @Description read=true/false. Only changes the unread mark on the linked devices; use markread to send read receipts
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.ReadChatMessage true "Read data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/read [post]
+func (h *MessageHandler) ReadChat(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ReadChatMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Phone == "" {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	result, err := h.messageService.MarkChatRead(r.Context(), user.ID, session.ID, &req)
+	if err != nil {
+		respondChatError(w, err)
+		return
+	}
+
+	model.RespondOK(w, result)
+}
+
+// DeleteChat godoc
+// @Summary Delete chat
+// @Description Deletes the chat and its messages from the linked devices
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.DeleteChatMessage true "Chat"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/delete [post]
+func (h *MessageHandler) DeleteChat(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.DeleteChatMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Phone == "" {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	result, err := h.messageService.DeleteChat(r.Context(), user.ID, session.ID, &req)
+	if err != nil {
+		respondChatError(w, err)
+		return
+	}
+
+	model.RespondOK(w, result)
+}
+
+// ClearChat godoc
+// @Summary Clear chat
+// @Description Clears the messages of the chat but keeps the chat. Starred messages are kept when keep_starred is set
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.ClearChatMessage true "Chat"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/clear [post]
+func (h *MessageHandler) ClearChat(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ClearChatMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Phone == "" {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	result, err := h.messageService.ClearChat(r.Context(), user.ID, session.ID, &req)
+	if err != nil {
+		respondChatError(w, err)
+		return
+	}
+
+	model.RespondOK(w, result)
+}
+
+// StarMessage godoc
+// @Summary Star message
+// @Description star=true/false. Messages received in groups need sender_phone
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.StarMessage true "Star data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/star [post]
+func (h *MessageHandler) StarMessage(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.StarMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Phone == "" {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	if req.MessageID == "" {
+		model.RespondBadRequest(w, errors.New("message_id is required"))
+		return
+	}
+
+	result, err := h.messageService.StarMessage(r.Context(), user.ID, session.ID, &req)
+	if err != nil {
+		respondChatError(w, err)
+		return
+	}
+
+	model.RespondOK(w, result)
+}
+
+func respondChatError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidJID), errors.Is(err, service.ErrSenderPhoneRequired):
+		model.RespondBadRequest(w, err)
+	case errors.Is(err, service.ErrNoSession), errors.Is(err, service.ErrSessionNotLoggedIn):
+		model.RespondError(w, http.StatusConflict, err)
+	default:
+		model.RespondInternalError(w, err)
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/chatwoot"
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type ChatwootHandler struct {
+	chatwootService *service.ChatwootService
+}
+
+func NewChatwootHandler(chatwootService *service.ChatwootService) *ChatwootHandler {
+	return &ChatwootHandler{chatwootService: chatwootService}
+}
+
+// Get godoc
+// @Summary Get Chatwoot integration
+// @Tags Chatwoot
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.ChatwootConfig
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chatwoot [get]
+func (h *ChatwootHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	cfg, err := h.chatwootService.Get(session.ID)
+	if errors.Is(err, service.ErrChatwootNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, cfg)
+}
+
+// Set godoc
+// @Summary Set Chatwoot integration
+// @Description Connects the session to an API channel inbox of Chatwoot. Incoming messages and their attachments are posted to a conversation of the contact, created on the first message of a chat. Set the webhook of the inbox to the public URL of this API followed by webhookPath so agent replies are sent to the chat. Group chats are skipped unless ignore_groups is false
+// @Tags Chatwoot
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.ChatwootConfigRequest true "Chatwoot integration"
+// @Success 200 {object} model.ChatwootConfig
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chatwoot [put]
+func (h *ChatwootHandler) Set(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	var req model.ChatwootConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	cfg, err := h.chatwootService.Set(session.ID, &req)
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, cfg)
+}
+
+// Delete godoc
+// @Summary Delete Chatwoot integration
+// @Description Stops bridging the session and forgets its Chatwoot conversations
+// @Tags Chatwoot
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chatwoot [delete]
+func (h *ChatwootHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	if err := h.chatwootService.Delete(session.ID); err != nil {
+		if errors.Is(err, service.ErrChatwootNotFound) {
+			model.RespondNotFound(w, err)
+			return
+		}
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"deleted": true})
+}
+
+// Webhook godoc
+// @Summary Chatwoot webhook
+// @Description Receives the webhook of the Chatwoot inbox, authenticated by the token of webhookPath. Public outgoing messages of agents are sent to the chat of their conversation; other events are acknowledged and ignored
+// @Tags Chatwoot
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param token query string true "Webhook token"
+// @Param request body chatwoot.WebhookEvent true "Chatwoot event"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /chatwoot/{sessionId}/webhook [post]
+func (h *ChatwootHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	var evt chatwoot.WebhookEvent
+	if err := json.NewDecoder(r.Body).Decode(&evt); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	accepted, err := h.chatwootService.HandleWebhook(chi.URLParam(r, "sessionId"), r.URL.Query().Get("token"), &evt)
+	switch {
+	case errors.Is(err, service.ErrChatwootNotFound):
+		model.RespondNotFound(w, err)
+		return
+	case errors.Is(err, service.ErrChatwootToken):
+		model.RespondUnauthorized(w, err)
+		return
+	case err != nil:
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"accepted": accepted})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type ConnectorHandler struct {
+	connectorService *service.ConnectorService
+}
+
+func NewConnectorHandler(connectorService *service.ConnectorService) *ConnectorHandler {
+	return &ConnectorHandler{connectorService: connectorService}
+}
+
+// Get godoc
+// @Summary Get connector
+// @Tags Connector
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.Connector
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/connector [get]
+func (h *ConnectorHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	cfg, err := h.connectorService.Get(session.ID)
+	if errors.Is(err, service.ErrConnectorNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, cfg)
+}
+
+// Set godoc
+// @Summary Set connector
+// @Description Calls url for the incoming messages of the session and runs the actions it answers with: text, media, react, read and typing. Messages of a chat arriving within debounce_ms of each other are sent in one call. When the call fails or times out, fallback_message is sent to the chat. Group chats are skipped unless ignore_groups is false
+// @Tags Connector
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.ConnectorRequest true "Connector"
+// @Success 200 {object} model.Connector
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/connector [put]
+func (h *ConnectorHandler) Set(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	var req model.ConnectorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	cfg, err := h.connectorService.Set(session.ID, &req)
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, cfg)
+}
+
+// Delete godoc
+// @Summary Delete connector
+// @Description Stops calling the connector for incoming messages
+// @Tags Connector
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/connector [delete]
+func (h *ConnectorHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	if err := h.connectorService.Delete(session.ID); err != nil {
+		if errors.Is(err, service.ErrConnectorNotFound) {
+			model.RespondNotFound(w, err)
+			return
+		}
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"deleted": true})
+}
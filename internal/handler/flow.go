@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+const maxFlowSize = 1 << 20
+
+type FlowHandler struct {
+	flowService *service.FlowService
+}
+
+func NewFlowHandler(flowService *service.FlowService) *FlowHandler {
+	return &FlowHandler{flowService: flowService}
+}
+
+// Get godoc
+// @Summary Get flow
+// @Tags Flows
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.Flow
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/flow [get]
+func (h *FlowHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	flow, err := h.flowService.Get(session.ID)
+	if errors.Is(err, service.ErrFlowNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, flow)
+}
+
+// Save godoc
+// @Summary Set flow
+// @Description Replaces the conversational flow of the session, a state machine run on incoming messages and list or button replies. The definition is sent as JSON, or as YAML with a YAML content type. Running conversations continue in the new definition
+// @Tags Flows
+// @Accept json,application/x-yaml
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.FlowDefinition true "Flow definition"
+// @Success 200 {object} model.Flow
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/flow [put]
+func (h *FlowHandler) Save(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxFlowSize))
+	if err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	isYAML := strings.Contains(r.Header.Get("Content-Type"), "yaml")
+	flow, err := h.flowService.Save(session.ID, body, isYAML)
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, flow)
+}
+
+// Delete godoc
+// @Summary Delete flow
+// @Description Deletes the flow of the session and all its conversations
+// @Tags Flows
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/flow [delete]
+func (h *FlowHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	if err := h.flowService.Delete(session.ID); err != nil {
+		if errors.Is(err, service.ErrFlowNotFound) {
+			model.RespondNotFound(w, err)
+			return
+		}
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"deleted": true})
+}
+
+// ListStates godoc
+// @Summary List flow conversations
+// @Tags Flows
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param status query string false "Filter by status (active, handoff)"
+// @Success 200 {array} model.FlowState
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/flow/states [get]
+func (h *FlowHandler) ListStates(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	states, err := h.flowService.ListStates(session.ID, r.URL.Query().Get("status"))
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, states)
+}
+
+// GetState godoc
+// @Summary Get flow conversation
+// @Tags Flows
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param chat path string true "Chat JID or phone"
+// @Success 200 {object} model.FlowState
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/flow/states/{chat} [get]
+func (h *FlowHandler) GetState(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	state, err := h.flowService.GetState(session.ID, chi.URLParam(r, "chat"))
+	if err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, state)
+}
+
+// ResetState godoc
+// @Summary Reset flow conversation
+// @Description Ends the conversation of a chat and hands it back from a human to the flow; the next message of the contact starts over
+// @Tags Flows
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param chat path string true "Chat JID or phone"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/flow/states/{chat} [delete]
+func (h *FlowHandler) ResetState(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	chat := chi.URLParam(r, "chat")
+	if err := h.flowService.ResetState(session.ID, chat); err != nil {
+		if errors.Is(err, service.ErrFlowStateNotFound) {
+			model.RespondNotFound(w, err)
+			return
+		}
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{
+		"chat":  chat,
+		"reset": true,
+	})
+}
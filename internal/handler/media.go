@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+const (
+	contentTypeMultipart = "multipart/form-data"
+	contentTypeBinary    = "application/octet-stream"
+	maxFormFieldSize     = 64 << 10
+)
+
+// errInvalidUpload reports a multipart upload that does not fit the endpoint.
+var errInvalidUpload = errors.New("invalid upload")
+
+// decodeMediaRequest decodes a media send request from a JSON body or from a
+// multipart/form-data body. In multipart requests the form fields must come
+// before the file part named fileField. The file is spooled to a temporary
+// file of at most maxSize bytes so the rest of the body can be checked before
+// anything is sent; the returned cleanup removes it and is never nil.
+func decodeMediaRequest(r *http.Request, fileField string, maxSize int64, dst interface{}) (*model.MediaUpload, func(), error) {
+	noop := func() {}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != contentTypeMultipart {
+		return nil, noop, json.NewDecoder(r.Body).Decode(dst)
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, noop, err
+	}
+
+	fields := make(map[string]string)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, noop, err
+		}
+
+		if part.FormName() == fileField && part.FileName() != "" {
+			return decodeUpload(mr, part, fileField, maxSize, fields, dst)
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize))
+		if err != nil {
+			return nil, noop, err
+		}
+		fields[part.FormName()] = string(value)
+	}
+
+	return nil, noop, setFormFields(dst, fields)
+}
+
+// decodeUpload spools the file part and checks that it is the last part and
+// of a type accepted for fileField.
+func decodeUpload(mr *multipart.Reader, part *multipart.Part, fileField string, maxSize int64, fields map[string]string, dst interface{}) (*model.MediaUpload, func(), error) {
+	noop := func() {}
+
+	if err := setFormFields(dst, fields); err != nil {
+		return nil, noop, err
+	}
+
+	mimeType := part.Header.Get("Content-Type")
+	if mimeType == contentTypeBinary {
+		mimeType = ""
+	}
+	if mimeType != "" && !service.AcceptsMediaType(fileField, mimeType) {
+		return nil, noop, fmt.Errorf("%w: %s does not accept %s", errInvalidUpload, fileField, mimeType)
+	}
+
+	file, err := os.CreateTemp("", "fiozap-upload-*")
+	if err != nil {
+		return nil, noop, err
+	}
+	cleanup := func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}
+
+	n, err := io.Copy(file, io.LimitReader(part, maxSize+1))
+	if err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	if n > maxSize {
+		cleanup()
+		return nil, noop, fmt.Errorf("%w: %s exceeds the maximum size of %d bytes", errInvalidUpload, fileField, maxSize)
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		cleanup()
+		if err == nil {
+			err = fmt.Errorf("%w: form fields must come before the %s part", errInvalidUpload, fileField)
+		}
+		return nil, noop, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+
+	return &model.MediaUpload{
+		Reader:   file,
+		FileName: part.FileName(),
+		MimeType: mimeType,
+	}, cleanup, nil
+}
+
+// payloadError keeps the message of upload errors and reports every other
+// decoding error as an invalid payload.
+func payloadError(err error) error {
+	if errors.Is(err, errInvalidUpload) {
+		return err
+	}
+	return errors.New("invalid payload")
+}
+
+// setFormFields assigns form values to the struct fields with the matching
+// json tag. Non-string fields are parsed as JSON, so "true" sets a bool.
+// Embedded structs such as model.SendOptions are filled in the same way.
+func setFormFields(dst interface{}, fields map[string]string) error {
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous && t.Field(i).Type.Kind() == reflect.Struct {
+			if err := setFormFields(v.Field(i).Addr().Interface(), fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		value, ok := fields[name]
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.String {
+			field.SetString(value)
+			continue
+		}
+
+		if err := json.Unmarshal([]byte(value), field.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid value for %s", name)
+		}
+	}
+
+	return nil
+}
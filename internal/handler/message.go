@@ -3,7 +3,13 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
 
 	"fiozap/internal/middleware"
 	"fiozap/internal/model"
@@ -20,6 +26,7 @@ func NewMessageHandler(messageService *service.MessageService) *MessageHandler {
 
 // SendText godoc
 // @Summary Send text
+// @Description reply_to quotes a message, mentions @-mentions users (include @number in the text). link_preview fetches a preview card for the first https URL; preview supplies one explicitly. queue=true stores the message in the send queue and returns a queue_id.
 // @Tags Messages
 // @Accept json
 // @Produce json
@@ -64,9 +71,9 @@ func (h *MessageHandler) SendText(w http.ResponseWriter, r *http.Request) {
 
 // SendImage godoc
 // @Summary Send image
-// @Description Base64 data:image/* required
+// @Description Base64 data:image/*, https URL or multipart upload
 // @Tags Messages
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Param sessionId path string true "Session ID"
 // @Param message body model.ImageMessage true "Image data"
@@ -83,17 +90,20 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req model.ImageMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
+	upload, cleanup, err := decodeMediaRequest(r, "image", h.messageService.MaxMediaSize(), &req)
+	if err != nil {
+		model.RespondBadRequest(w, payloadError(err))
 		return
 	}
+	defer cleanup()
+	req.Upload = upload
 
 	if req.Phone == "" {
 		model.RespondBadRequest(w, errors.New("phone is required"))
 		return
 	}
 
-	if req.Image == "" {
+	if req.Image == "" && req.Upload == nil {
 		model.RespondBadRequest(w, errors.New("image is required"))
 		return
 	}
@@ -109,9 +119,9 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 
 // SendAudio godoc
 // @Summary Send audio
-// @Description Base64 data:audio/* required
+// @Description Base64 data:audio/*, https URL or multipart upload
 // @Tags Messages
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Param sessionId path string true "Session ID"
 // @Param message body model.AudioMessage true "Audio data"
@@ -128,17 +138,20 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req model.AudioMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
+	upload, cleanup, err := decodeMediaRequest(r, "audio", h.messageService.MaxMediaSize(), &req)
+	if err != nil {
+		model.RespondBadRequest(w, payloadError(err))
 		return
 	}
+	defer cleanup()
+	req.Upload = upload
 
 	if req.Phone == "" {
 		model.RespondBadRequest(w, errors.New("phone is required"))
 		return
 	}
 
-	if req.Audio == "" {
+	if req.Audio == "" && req.Upload == nil {
 		model.RespondBadRequest(w, errors.New("audio is required"))
 		return
 	}
@@ -154,9 +167,9 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 
 // SendVideo godoc
 // @Summary Send video
-// @Description Base64 data:video/* required
+// @Description Base64 data:video/*, https URL or multipart upload
 // @Tags Messages
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Param sessionId path string true "Session ID"
 // @Param message body model.VideoMessage true "Video data"
@@ -173,17 +186,20 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req model.VideoMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
+	upload, cleanup, err := decodeMediaRequest(r, "video", h.messageService.MaxMediaSize(), &req)
+	if err != nil {
+		model.RespondBadRequest(w, payloadError(err))
 		return
 	}
+	defer cleanup()
+	req.Upload = upload
 
 	if req.Phone == "" {
 		model.RespondBadRequest(w, errors.New("phone is required"))
 		return
 	}
 
-	if req.Video == "" {
+	if req.Video == "" && req.Upload == nil {
 		model.RespondBadRequest(w, errors.New("video is required"))
 		return
 	}
@@ -199,9 +215,9 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 
 // SendDocument godoc
 // @Summary Send document
-// @Description Base64 with filename required
+// @Description Base64 (filename required), https URL or multipart upload
 // @Tags Messages
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Param sessionId path string true "Session ID"
 // @Param message body model.DocumentMessage true "Document data"
@@ -218,22 +234,25 @@ func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req model.DocumentMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
+	upload, cleanup, err := decodeMediaRequest(r, "document", h.messageService.MaxMediaSize(), &req)
+	if err != nil {
+		model.RespondBadRequest(w, payloadError(err))
 		return
 	}
+	defer cleanup()
+	req.Upload = upload
 
 	if req.Phone == "" {
 		model.RespondBadRequest(w, errors.New("phone is required"))
 		return
 	}
 
-	if req.Document == "" {
+	if req.Document == "" && req.Upload == nil {
 		model.RespondBadRequest(w, errors.New("document is required"))
 		return
 	}
 
-	if req.FileName == "" {
+	if req.FileName == "" && req.Upload == nil && strings.HasPrefix(req.Document, "data:") {
 		model.RespondBadRequest(w, errors.New("filename is required"))
 		return
 	}
@@ -417,9 +436,9 @@ func (h *MessageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 // SendSticker godoc
 // @Summary Send sticker
-// @Description WebP format required
+// @Description WebP as base64, https URL or multipart upload
 // @Tags Messages
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Param sessionId path string true "Session ID"
 // @Param message body model.StickerMessage true "Sticker data"
@@ -436,17 +455,20 @@ func (h *MessageHandler) SendSticker(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req model.StickerMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
+	upload, cleanup, err := decodeMediaRequest(r, "sticker", h.messageService.MaxMediaSize(), &req)
+	if err != nil {
+		model.RespondBadRequest(w, payloadError(err))
 		return
 	}
+	defer cleanup()
+	req.Upload = upload
 
 	if req.Phone == "" {
 		model.RespondBadRequest(w, errors.New("phone is required"))
 		return
 	}
 
-	if req.Sticker == "" {
+	if req.Sticker == "" && req.Upload == nil {
 		model.RespondBadRequest(w, errors.New("sticker is required"))
 		return
 	}
@@ -738,7 +760,7 @@ func (h *MessageHandler) SetStatusText(w http.ResponseWriter, r *http.Request) {
 
 // DownloadImage godoc
 // @Summary Download image
-// @Description Returns base64
+// @Description Returns base64. Use GET /chat/media to stream large files.
 // @Tags Chat
 // @Accept json
 // @Produce json
@@ -749,31 +771,12 @@ func (h *MessageHandler) SetStatusText(w http.ResponseWriter, r *http.Request) {
 // @Security ApiKeyAuth
 // @Router /sessions/{sessionId}/chat/downloadimage [post]
 func (h *MessageHandler) DownloadImage(w http.ResponseWriter, r *http.Request) {
-	user := middleware.GetUserFromContext(r.Context())
-	session := middleware.GetSessionFromContext(r.Context())
-	if user == nil || session == nil {
-		model.RespondUnauthorized(w, errors.New("user not found"))
-		return
-	}
-
-	var req model.DownloadMediaMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
-		return
-	}
-
-	result, err := h.messageService.DownloadImage(r.Context(), user.ID, session.ID, &req)
-	if err != nil {
-		model.RespondInternalError(w, err)
-		return
-	}
-
-	model.RespondOK(w, result)
+	h.downloadMedia(w, r, "image")
 }
 
 // DownloadVideo godoc
 // @Summary Download video
-// @Description Returns base64
+// @Description Returns base64. Use GET /chat/media to stream large files.
 // @Tags Chat
 // @Accept json
 // @Produce json
@@ -784,31 +787,12 @@ func (h *MessageHandler) DownloadImage(w http.ResponseWriter, r *http.Request) {
 // @Security ApiKeyAuth
 // @Router /sessions/{sessionId}/chat/downloadvideo [post]
 func (h *MessageHandler) DownloadVideo(w http.ResponseWriter, r *http.Request) {
-	user := middleware.GetUserFromContext(r.Context())
-	session := middleware.GetSessionFromContext(r.Context())
-	if user == nil || session == nil {
-		model.RespondUnauthorized(w, errors.New("user not found"))
-		return
-	}
-
-	var req model.DownloadMediaMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
-		return
-	}
-
-	result, err := h.messageService.DownloadVideo(r.Context(), user.ID, session.ID, &req)
-	if err != nil {
-		model.RespondInternalError(w, err)
-		return
-	}
-
-	model.RespondOK(w, result)
+	h.downloadMedia(w, r, "video")
 }
 
 // DownloadAudio godoc
 // @Summary Download audio
-// @Description Returns base64
+// @Description Returns base64. Use GET /chat/media to stream large files.
 // @Tags Chat
 // @Accept json
 // @Produce json
@@ -819,6 +803,42 @@ func (h *MessageHandler) DownloadVideo(w http.ResponseWriter, r *http.Request) {
 // @Security ApiKeyAuth
 // @Router /sessions/{sessionId}/chat/downloadaudio [post]
 func (h *MessageHandler) DownloadAudio(w http.ResponseWriter, r *http.Request) {
+	h.downloadMedia(w, r, "audio")
+}
+
+// DownloadDocument godoc
+// @Summary Download document
+// @Description Returns base64. Use GET /chat/media to stream large files.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.DownloadMediaMessage true "Download data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/downloaddocument [post]
+func (h *MessageHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	h.downloadMedia(w, r, "document")
+}
+
+// DownloadSticker godoc
+// @Summary Download sticker
+// @Description Returns base64. Use GET /chat/media to stream large files.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.DownloadMediaMessage true "Download data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/chat/downloadsticker [post]
+func (h *MessageHandler) DownloadSticker(w http.ResponseWriter, r *http.Request) {
+	h.downloadMedia(w, r, "sticker")
+}
+
+func (h *MessageHandler) downloadMedia(w http.ResponseWriter, r *http.Request, mediaType string) {
 	user := middleware.GetUserFromContext(r.Context())
 	session := middleware.GetSessionFromContext(r.Context())
 	if user == nil || session == nil {
@@ -831,8 +851,9 @@ func (h *MessageHandler) DownloadAudio(w http.ResponseWriter, r *http.Request) {
 		model.RespondBadRequest(w, errors.New("invalid payload"))
 		return
 	}
+	req.Type = mediaType
 
-	result, err := h.messageService.DownloadAudio(r.Context(), user.ID, session.ID, &req)
+	result, err := h.messageService.DownloadMedia(r.Context(), user.ID, session.ID, &req)
 	if err != nil {
 		model.RespondInternalError(w, err)
 		return
@@ -841,54 +862,51 @@ func (h *MessageHandler) DownloadAudio(w http.ResponseWriter, r *http.Request) {
 	model.RespondOK(w, result)
 }
 
-// DownloadDocument godoc
-// @Summary Download document
-// @Description Returns base64
+// StreamMedia godoc
+// @Summary Stream media
+// @Description Streams decrypted media as a binary response, addressed by the media descriptor fields. Byte fields are base64 encoded.
 // @Tags Chat
 // @Accept json
-// @Produce json
+// @Produce octet-stream
 // @Param sessionId path string true "Session ID"
-// @Param message body model.DownloadMediaMessage true "Download data"
-// @Success 200 {object} map[string]interface{}
+// @Param message body model.DownloadMediaMessage true "Media descriptor"
+// @Success 200 {file} binary
 // @Failure 400 {object} map[string]interface{}
 // @Security ApiKeyAuth
-// @Router /sessions/{sessionId}/chat/downloaddocument [post]
-func (h *MessageHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
-	user := middleware.GetUserFromContext(r.Context())
-	session := middleware.GetSessionFromContext(r.Context())
-	if user == nil || session == nil {
-		model.RespondUnauthorized(w, errors.New("user not found"))
-		return
-	}
-
+// @Router /sessions/{sessionId}/chat/media [post]
+func (h *MessageHandler) StreamMedia(w http.ResponseWriter, r *http.Request) {
 	var req model.DownloadMediaMessage
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		model.RespondBadRequest(w, errors.New("invalid payload"))
 		return
 	}
+	req.MessageID = ""
 
-	result, err := h.messageService.DownloadDocument(r.Context(), user.ID, session.ID, &req)
-	if err != nil {
-		model.RespondInternalError(w, err)
+	if req.Type == "" {
+		model.RespondBadRequest(w, errors.New("type is required"))
 		return
 	}
 
-	model.RespondOK(w, result)
+	h.streamMedia(w, r, &req)
 }
 
-// DownloadSticker godoc
-// @Summary Download sticker
-// @Description Returns base64
+// StreamMessageMedia godoc
+// @Summary Stream stored message media
+// @Description Streams the decrypted media of a message kept in the message store as a binary response.
 // @Tags Chat
-// @Accept json
-// @Produce json
+// @Produce octet-stream
 // @Param sessionId path string true "Session ID"
-// @Param message body model.DownloadMediaMessage true "Download data"
-// @Success 200 {object} map[string]interface{}
+// @Param messageId path string true "Message ID"
+// @Success 200 {file} binary
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Security ApiKeyAuth
-// @Router /sessions/{sessionId}/chat/downloadsticker [post]
-func (h *MessageHandler) DownloadSticker(w http.ResponseWriter, r *http.Request) {
+// @Router /sessions/{sessionId}/chat/media/{messageId} [get]
+func (h *MessageHandler) StreamMessageMedia(w http.ResponseWriter, r *http.Request) {
+	h.streamMedia(w, r, &model.DownloadMediaMessage{MessageID: chi.URLParam(r, "messageId")})
+}
+
+func (h *MessageHandler) streamMedia(w http.ResponseWriter, r *http.Request, req *model.DownloadMediaMessage) {
 	user := middleware.GetUserFromContext(r.Context())
 	session := middleware.GetSessionFromContext(r.Context())
 	if user == nil || session == nil {
@@ -896,19 +914,38 @@ func (h *MessageHandler) DownloadSticker(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var req model.DownloadMediaMessage
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		model.RespondBadRequest(w, errors.New("invalid payload"))
+	file, err := h.messageService.DownloadMediaFile(r.Context(), user.ID, session.ID, req)
+	switch {
+	case errors.Is(err, service.ErrMediaNotFound):
+		model.RespondNotFound(w, err)
 		return
-	}
-
-	result, err := h.messageService.DownloadSticker(r.Context(), user.ID, session.ID, &req)
-	if err != nil {
+	case errors.Is(err, service.ErrInvalidMedia):
+		model.RespondBadRequest(w, err)
+		return
+	case err != nil:
 		model.RespondInternalError(w, err)
 		return
 	}
+	defer file.Close()
 
-	model.RespondOK(w, result)
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	fileName := file.FileName
+	if fileName == "" {
+		fileName = "media"
+		if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+			fileName += exts[0]
+		}
+	}
+
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, file)
 }
 
 // ArchiveChat godoc
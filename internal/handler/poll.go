@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type PollHandler struct {
+	pollService *service.PollService
+}
+
+func NewPollHandler(pollService *service.PollService) *PollHandler {
+	return &PollHandler{pollService: pollService}
+}
+
+// Results godoc
+// @Summary Get poll results
+// @Description Returns the vote count and voters of each option of a poll sent or received by this session. Each voter's latest vote counts; withdrawn votes are not counted
+// @Tags Polls
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param messageId path string true "Poll message ID"
+// @Success 200 {object} model.PollResults
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/polls/{messageId}/results [get]
+func (h *PollHandler) Results(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	results, err := h.pollService.Results(session.ID, chi.URLParam(r, "messageId"))
+	if err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, results)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService: privacyService}
+}
+
+// Get godoc
+// @Summary Get privacy settings
+// @Description Fetches the current privacy settings of the session account from WhatsApp
+// @Tags Privacy
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.PrivacySettings
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/privacy [get]
+func (h *PrivacyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	settings, err := h.privacyService.Get(r.Context(), user.ID, session.ID)
+	if err != nil {
+		respondPrivacyError(w, err)
+		return
+	}
+
+	model.RespondOK(w, settings)
+}
+
+// Set godoc
+// @Summary Set privacy settings
+// @Description Changes the given privacy settings and returns all of them. Changes made on another device emit PrivacySettingsChanged
+// @Tags Privacy
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.PrivacySettingsRequest true "Privacy settings"
+// @Success 200 {object} model.PrivacySettings
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/privacy [put]
+func (h *PrivacyHandler) Set(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.PrivacySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	settings, err := h.privacyService.Set(r.Context(), user.ID, session.ID, &req)
+	if err != nil {
+		respondPrivacyError(w, err)
+		return
+	}
+
+	model.RespondOK(w, settings)
+}
+
+func respondPrivacyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPrivacy):
+		model.RespondBadRequest(w, err)
+	case errors.Is(err, service.ErrNoSession), errors.Is(err, service.ErrSessionNotLoggedIn):
+		model.RespondError(w, http.StatusConflict, err)
+	default:
+		model.RespondInternalError(w, err)
+	}
+}
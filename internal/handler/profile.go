@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type ProfileHandler struct {
+	profileService *service.ProfileService
+}
+
+func NewProfileHandler(profileService *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+// Get godoc
+// @Summary Get own profile
+// @Description Returns the JID, push name, about text and profile picture of the session account
+// @Tags Profile
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} model.Profile
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/profile [get]
+func (h *ProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	profile, err := h.profileService.Get(r.Context(), user.ID, session.ID)
+	if err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	model.RespondOK(w, profile)
+}
+
+// SetName godoc
+// @Summary Set own name
+// @Description Changes the push name shown to contacts that have not saved the number (max 25 characters)
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.ProfileNameRequest true "Name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/profile/name [post]
+func (h *ProfileHandler) SetName(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ProfileNameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if err := h.profileService.SetName(r.Context(), user.ID, session.ID, req.Name); err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"name": req.Name})
+}
+
+// SetAbout godoc
+// @Summary Set own about text
+// @Description Changes the about text of the profile (max 139 characters)
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.ProfileAboutRequest true "About text"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/profile/about [post]
+func (h *ProfileHandler) SetAbout(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ProfileAboutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if err := h.profileService.SetAbout(r.Context(), user.ID, session.ID, req.About); err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"about": req.About})
+}
+
+// SetPhoto godoc
+// @Summary Set own profile photo
+// @Description Image as base64 data URL or https URL. It is converted to a JPEG of at most 640 pixels per side
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.ProfilePhotoRequest true "Photo"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/profile/photo [post]
+func (h *ProfileHandler) SetPhoto(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ProfilePhotoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	pictureID, err := h.profileService.SetPhoto(r.Context(), user.ID, session.ID, req.Image)
+	if err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]string{"pictureId": pictureID})
+}
+
+// RemovePhoto godoc
+// @Summary Remove own profile photo
+// @Tags Profile
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/profile/photo [delete]
+func (h *ProfileHandler) RemovePhoto(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	if err := h.profileService.RemovePhoto(r.Context(), user.ID, session.ID); err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"removed": true})
+}
+
+func respondProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProfile):
+		model.RespondBadRequest(w, err)
+	case errors.Is(err, service.ErrNoSession), errors.Is(err, service.ErrSessionNotLoggedIn):
+		model.RespondError(w, http.StatusConflict, err)
+	default:
+		model.RespondInternalError(w, err)
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type QueueHandler struct {
+	sendQueue *service.SendQueue
+}
+
+func NewQueueHandler(sendQueue *service.SendQueue) *QueueHandler {
+	return &QueueHandler{sendQueue: sendQueue}
+}
+
+// List godoc
+// @Summary List queued messages
+// @Description Latest 100 items of the send queue
+// @Tags Queue
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param status query string false "Filter by status (pending, sending, sent, failed, canceled)"
+// @Success 200 {array} model.QueueItem
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/queue [get]
+func (h *QueueHandler) List(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	items, err := h.sendQueue.List(session.ID, r.URL.Query().Get("status"))
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, items)
+}
+
+// Get godoc
+// @Summary Get queued message
+// @Tags Queue
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param queueId path string true "Queue item ID"
+// @Success 200 {object} model.QueueItem
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/queue/{queueId} [get]
+func (h *QueueHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	item, err := h.sendQueue.Get(session.ID, chi.URLParam(r, "queueId"))
+	if err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, item)
+}
+
+// Cancel godoc
+// @Summary Cancel queued message
+// @Description Only pending items can be canceled
+// @Tags Queue
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param queueId path string true "Queue item ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/queue/{queueId} [delete]
+func (h *QueueHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	queueID := chi.URLParam(r, "queueId")
+	if err := h.sendQueue.Cancel(session.ID, queueID); err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{
+		"queue_id": queueID,
+		"status":   model.QueueStatusCanceled,
+	})
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type ScheduledHandler struct {
+	scheduler *service.Scheduler
+}
+
+func NewScheduledHandler(scheduler *service.Scheduler) *ScheduledHandler {
+	return &ScheduledHandler{scheduler: scheduler}
+}
+
+// Create godoc
+// @Summary Schedule message
+// @Description Stores any send payload (kind: text, image, audio, video, document, location, contact, sticker, poll, list, buttons) to be sent at send_at
+// @Tags Scheduled
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body model.ScheduleRequest true "Schedule data"
+// @Success 200 {object} model.ScheduledMessage
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/scheduled [post]
+func (h *ScheduledHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Kind == "" {
+		model.RespondBadRequest(w, errors.New("kind is required"))
+		return
+	}
+
+	msg, err := h.scheduler.Create(user.ID, session.ID, &req)
+	if err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	model.RespondOK(w, msg)
+}
+
+// List godoc
+// @Summary List scheduled messages
+// @Tags Scheduled
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param status query string false "Filter by status (scheduled, sending, sent, failed, canceled)"
+// @Success 200 {array} model.ScheduledMessage
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/scheduled [get]
+func (h *ScheduledHandler) List(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	msgs, err := h.scheduler.List(session.ID, r.URL.Query().Get("status"))
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, msgs)
+}
+
+// Get godoc
+// @Summary Get scheduled message
+// @Tags Scheduled
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param scheduleId path string true "Scheduled message ID"
+// @Success 200 {object} model.ScheduledMessage
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/scheduled/{scheduleId} [get]
+func (h *ScheduledHandler) Get(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	msg, err := h.scheduler.Get(session.ID, chi.URLParam(r, "scheduleId"))
+	if err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	model.RespondOK(w, msg)
+}
+
+// Reschedule godoc
+// @Summary Reschedule message
+// @Description Only messages that were not sent or canceled yet can be rescheduled
+// @Tags Scheduled
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param scheduleId path string true "Scheduled message ID"
+// @Param request body model.RescheduleRequest true "New send time"
+// @Success 200 {object} model.ScheduledMessage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/scheduled/{scheduleId} [put]
+func (h *ScheduledHandler) Reschedule(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	var req model.RescheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	msg, err := h.scheduler.Reschedule(session.ID, chi.URLParam(r, "scheduleId"), &req)
+	if err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	model.RespondOK(w, msg)
+}
+
+// Cancel godoc
+// @Summary Cancel scheduled message
+// @Tags Scheduled
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param scheduleId path string true "Scheduled message ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/scheduled/{scheduleId} [delete]
+func (h *ScheduledHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	session := middleware.GetSessionFromContext(r.Context())
+	if session == nil {
+		model.RespondUnauthorized(w, errors.New("session not found"))
+		return
+	}
+
+	scheduleID := chi.URLParam(r, "scheduleId")
+	if err := h.scheduler.Cancel(session.ID, scheduleID); err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{
+		"schedule_id": scheduleID,
+		"status":      model.ScheduledStatusCanceled,
+	})
+}
+
+func respondScheduleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSchedule):
+		model.RespondBadRequest(w, err)
+	case errors.Is(err, service.ErrScheduleNotFound):
+		model.RespondNotFound(w, err)
+	default:
+		model.RespondInternalError(w, err)
+	}
+}
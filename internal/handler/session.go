@@ -171,6 +171,7 @@ func (h *SessionHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Security ApiKeyAuth
 // @Router /sessions/{sessionId}/connect [post]
@@ -196,6 +197,10 @@ func (h *SessionHandler) Connect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := h.sessionService.Connect(r.Context(), user.ID, session, immediate)
+	if errors.Is(err, service.ErrSessionBanned) {
+		model.RespondError(w, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
 		model.RespondInternalError(w, err)
 		return
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"fiozap/internal/middleware"
+	"fiozap/internal/model"
+	"fiozap/internal/service"
+)
+
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService}
+}
+
+// Create godoc
+// @Summary Create template
+// @Description Stores a message template. Kind and payload follow the scheduled message format without the phone; payload strings may use {{name}} placeholders, and defaults gives values for optional ones
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Param request body model.TemplateRequest true "Template data"
+// @Success 200 {object} model.Template
+// @Failure 400 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /templates [post]
+func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	tmpl, err := h.templateService.Create(user.ID, &req)
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, tmpl)
+}
+
+// List godoc
+// @Summary List templates
+// @Tags Templates
+// @Produce json
+// @Success 200 {array} model.Template
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /templates [get]
+func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	templates, err := h.templateService.List(user.ID)
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, templates)
+}
+
+// Get godoc
+// @Summary Get template
+// @Tags Templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} model.Template
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /templates/{templateId} [get]
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	tmpl, err := h.templateService.Get(user.ID, chi.URLParam(r, "templateId"))
+	if err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, tmpl)
+}
+
+// Update godoc
+// @Summary Update template
+// @Tags Templates
+// @Accept json
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param request body model.TemplateRequest true "Template data"
+// @Success 200 {object} model.Template
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /templates/{templateId} [put]
+func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	tmpl, err := h.templateService.Update(user.ID, chi.URLParam(r, "templateId"), &req)
+	if errors.Is(err, service.ErrTemplateNotFound) {
+		model.RespondNotFound(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondBadRequest(w, err)
+		return
+	}
+
+	model.RespondOK(w, tmpl)
+}
+
+// Delete godoc
+// @Summary Delete template
+// @Tags Templates
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /templates/{templateId} [delete]
+func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	templateID := chi.URLParam(r, "templateId")
+	if err := h.templateService.Delete(user.ID, templateID); err != nil {
+		model.RespondNotFound(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{
+		"template_id": templateID,
+		"deleted":     true,
+	})
+}
+
+// Send godoc
+// @Summary Send template
+// @Description Renders a template, chosen by template_id or template_name, with the given variables and sends it through the send endpoint of its kind. Requests missing a variable without a default are rejected. reply_to, mentions and queue work as on the other send endpoints.
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param message body model.TemplateSendRequest true "Message data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/messages/template [post]
+func (h *TemplateHandler) Send(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.TemplateSendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if req.Phone == "" {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	if req.TemplateID == "" && req.TemplateName == "" {
+		model.RespondBadRequest(w, errors.New("template_id or template_name is required"))
+		return
+	}
+
+	result, err := h.templateService.Send(r.Context(), user.ID, session.ID, &req)
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		model.RespondNotFound(w, err)
+	case errors.Is(err, service.ErrMissingVariables):
+		model.RespondBadRequest(w, err)
+	case err != nil:
+		model.RespondInternalError(w, err)
+	default:
+		model.RespondOK(w, result)
+	}
+}
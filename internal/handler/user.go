@@ -357,3 +357,93 @@ func (h *UserHandler) GetUserLID(w http.ResponseWriter, r *http.Request) {
 
 	model.RespondOK(w, result)
 }
+
+// GetBlocklist godoc
+// @Summary Get blocklist
+// @Description Lists the JIDs blocked by the session account
+// @Tags User
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Param sessionId path string true "Session ID"
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/user/blocklist [get]
+func (h *UserHandler) GetBlocklist(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	jids, err := h.userService.GetBlocklist(r.Context(), user.ID, session.ID)
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"jids": jids})
+}
+
+// Block godoc
+// @Summary Block contacts
+// @Description Blocks phone numbers or JIDs and returns the resulting blocklist. Changes emit Blocklist
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body model.BlocklistRequest true "Contacts"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Param sessionId path string true "Session ID"
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/user/block [post]
+func (h *UserHandler) Block(w http.ResponseWriter, r *http.Request) {
+	h.updateBlocklist(w, r, true)
+}
+
+// Unblock godoc
+// @Summary Unblock contacts
+// @Description Unblocks phone numbers or JIDs and returns the resulting blocklist. Changes emit Blocklist
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body model.BlocklistRequest true "Contacts"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Param sessionId path string true "Session ID"
+// @Security ApiKeyAuth
+// @Router /sessions/{sessionId}/user/unblock [post]
+func (h *UserHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	h.updateBlocklist(w, r, false)
+}
+
+func (h *UserHandler) updateBlocklist(w http.ResponseWriter, r *http.Request, block bool) {
+	user := middleware.GetUserFromContext(r.Context())
+	session := middleware.GetSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		model.RespondUnauthorized(w, errors.New("user not found"))
+		return
+	}
+
+	var req model.BlocklistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		model.RespondBadRequest(w, errors.New("invalid payload"))
+		return
+	}
+
+	if len(req.Phone) == 0 {
+		model.RespondBadRequest(w, errors.New("phone is required"))
+		return
+	}
+
+	jids, err := h.userService.UpdateBlocklist(r.Context(), user.ID, session.ID, req.Phone, block)
+	if errors.Is(err, service.ErrInvalidPhone) {
+		model.RespondBadRequest(w, err)
+		return
+	}
+	if err != nil {
+		model.RespondInternalError(w, err)
+		return
+	}
+
+	model.RespondOK(w, map[string]interface{}{"jids": jids})
+}
@@ -24,6 +24,48 @@ var supportedEventTypes = []string{
 	"GroupInfo",
 	"JoinedGroup",
 	"CallOffer",
+	"QueueSent",
+	"QueueFailed",
+	"ScheduledMessageSent",
+	"ScheduledMessageFailed",
+	"CampaignProgress",
+	"CampaignCompleted",
+	"PollVote",
+	"InteractiveReply",
+	"MessageEdited",
+	"MessageRevoked",
+	"MessageReaction",
+	"UndecryptableMessage",
+	"PushName",
+	"Picture",
+	"UserAbout",
+	"IdentityChange",
+	"Blocklist",
+	"OfflineSyncCompleted",
+	"KeepAliveTimeout",
+	"KeepAliveRestored",
+	"StreamReplaced",
+	"TemporaryBan",
+	"ConnectFailure",
+	"CallAccept",
+	"CallTerminate",
+	"NewsletterJoin",
+	"NewsletterLeave",
+	"NewsletterLiveUpdate",
+	"Mute",
+	"Pin",
+	"Archive",
+	"Star",
+	"DeleteChat",
+	"ClearChat",
+	"MarkChatAsRead",
+	"SessionAlert",
+	"CallRejected",
+	"FlowHandoff",
+	"ChatAssigned",
+	"ChatClosed",
+	"ChatReopened",
+	"PrivacySettingsChanged",
 	"All",
 }
 
@@ -0,0 +1,304 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+)
+
+const (
+	headerIdempotencyKey  = "Idempotency-Key"
+	headerIdempotentReply = "Idempotent-Replayed"
+	maxIdempotencyKey     = 255
+	idempotencyMemoryBody = 1 << 20
+	idempotencyMaxReply   = 1 << 20
+	idempotencyCleanup    = time.Hour
+)
+
+var (
+	errIdempotencyKeyTooLong = errors.New("Idempotency-Key must be at most 255 characters")
+	errIdempotencyMismatch   = errors.New("Idempotency-Key was already used with a different request")
+	errIdempotencyInProgress = errors.New("a request with this Idempotency-Key is still in progress")
+)
+
+// IdempotencyMiddleware makes mutating requests carrying an Idempotency-Key
+// header safe to retry. The first response to a key is stored and replayed
+// for repeats of the same request until it expires. Server errors are not
+// stored, so such requests can be retried with the same key, and neither are
+// responses over idempotencyMaxReply, such as media downloads, whose keys are
+// released so the request simply runs again.
+type IdempotencyMiddleware struct {
+	repo        *repository.IdempotencyRepository
+	ttl         time.Duration
+	lastCleanup atomic.Int64
+}
+
+func NewIdempotencyMiddleware(repo *repository.IdempotencyRepository, ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{repo: repo, ttl: ttl}
+}
+
+func (m *IdempotencyMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(headerIdempotencyKey)
+		if key == "" || !isMutating(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			model.RespondBadRequest(w, errIdempotencyKeyTooLong)
+			return
+		}
+
+		user := GetUserFromContext(r.Context())
+		if user == nil {
+			model.RespondUnauthorized(w, errUserNotFound)
+			return
+		}
+
+		body, requestHash, err := hashRequest(r)
+		if err != nil {
+			model.RespondBadRequest(w, err)
+			return
+		}
+		defer func() { _ = body.Close() }()
+		r.Body = body
+
+		m.cleanup()
+
+		record, reserved, err := m.repo.Reserve(user.ID, key, requestHash, m.ttl)
+		if err != nil {
+			model.RespondInternalError(w, err)
+			return
+		}
+
+		if !reserved {
+			switch {
+			case record.RequestHash != requestHash:
+				model.RespondError(w, http.StatusUnprocessableEntity, errIdempotencyMismatch)
+			case record.Status != model.IdempotencyStatusDone:
+				model.RespondError(w, http.StatusConflict, errIdempotencyInProgress)
+			default:
+				replay(w, record)
+			}
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			if !completed {
+				_ = m.repo.Release(user.ID, key)
+			}
+		}()
+
+		next.ServeHTTP(rw, r)
+
+		if rw.status >= http.StatusInternalServerError || rw.truncated {
+			return
+		}
+		if err := m.repo.Complete(user.ID, key, rw.status, rw.Header().Get("Content-Type"), rw.body.Bytes()); err != nil {
+			logger.WarnComponent("idempotency").Err(err).Msg("failed to store response")
+			return
+		}
+		completed = true
+	})
+}
+
+// cleanup deletes expired keys at most once per idempotencyCleanup.
+func (m *IdempotencyMiddleware) cleanup() {
+	now := time.Now().Unix()
+	last := m.lastCleanup.Load()
+	if now-last < int64(idempotencyCleanup.Seconds()) || !m.lastCleanup.CompareAndSwap(last, now) {
+		return
+	}
+
+	go func() {
+		if err := m.repo.DeleteExpired(); err != nil {
+			logger.WarnComponent("idempotency").Err(err).Msg("failed to delete expired keys")
+		}
+	}()
+}
+
+func replay(w http.ResponseWriter, record *model.IdempotencyRecord) {
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	w.Header().Set(headerIdempotentReply, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.ResponseBody)
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// hashRequest hashes the method, URL and body of r and returns a copy of the
+// body to hand on. Bodies larger than idempotencyMemoryBody are spooled to a
+// temporary file, so media uploads are not held in memory.
+//
+// Multipart bodies are hashed by their parts rather than their bytes, since
+// clients pick a new boundary for every request, so a retried upload hashes
+// the same as the original.
+func hashRequest(r *http.Request) (io.ReadCloser, string, error) {
+	body, err := copyBody(r.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	h := sha256.New()
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+
+	hashed := false
+	if boundary := multipartBoundary(r); boundary != "" {
+		partsHash := sha256.New()
+		if hashMultipart(partsHash, body, boundary) == nil {
+			_, _ = io.WriteString(h, "multipart "+sum(partsHash)+"\n")
+			hashed = true
+		}
+	}
+	if !hashed {
+		// Malformed multipart bodies fall back to their bytes.
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			_ = body.Close()
+			return nil, "", err
+		}
+		if _, err := io.Copy(h, body); err != nil {
+			_ = body.Close()
+			return nil, "", err
+		}
+	}
+
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		_ = body.Close()
+		return nil, "", err
+	}
+
+	return body, sum(h), nil
+}
+
+// copyBody reads a request body into memory, or into a temporary file when it
+// is larger than idempotencyMemoryBody.
+func copyBody(r io.Reader) (io.ReadSeekCloser, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(r, idempotencyMemoryBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if n <= idempotencyMemoryBody {
+		return memoryBody{bytes.NewReader(buf.Bytes())}, nil
+	}
+
+	file, err := os.CreateTemp("", "fiozap-request-*")
+	if err != nil {
+		return nil, err
+	}
+	spool := &spoolFile{File: file}
+
+	if _, err := io.Copy(file, io.MultiReader(&buf, r)); err != nil {
+		_ = spool.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = spool.Close()
+		return nil, err
+	}
+
+	return spool, nil
+}
+
+func multipartBoundary(r *http.Request) string {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return ""
+	}
+	return params["boundary"]
+}
+
+// hashMultipart writes the field name, file name, content type and a hash of
+// the content of every part to h.
+func hashMultipart(h io.Writer, body io.Reader, boundary string) error {
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		content := sha256.New()
+		if _, err := io.Copy(content, part); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "%q %q %q %s\n", part.FormName(), part.FileName(), part.Header.Get("Content-Type"), sum(content))
+	}
+}
+
+func sum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// memoryBody is a request body held in memory.
+type memoryBody struct {
+	*bytes.Reader
+}
+
+func (memoryBody) Close() error {
+	return nil
+}
+
+// spoolFile is a temporary request body that is removed when closed.
+type spoolFile struct {
+	*os.File
+}
+
+func (f *spoolFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.Name())
+	return err
+}
+
+// recordingWriter passes a response through while keeping a copy of it. The
+// copy is dropped once the response grows past idempotencyMaxReply.
+type recordingWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (rw *recordingWriter) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	switch {
+	case rw.truncated:
+	case rw.body.Len()+len(b) > idempotencyMaxReply:
+		rw.truncated = true
+		rw.body = bytes.Buffer{}
+	default:
+		rw.body.Write(b)
+	}
+	return rw.ResponseWriter.Write(b)
+}
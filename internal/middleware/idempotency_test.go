@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+)
+
+func multipartBody(t *testing.T, boundary, caption string, file []byte) (string, []byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := mw.SetBoundary(boundary); err != nil {
+		t.Fatal(err)
+	}
+	_ = mw.WriteField("phone", "5511999999999")
+	_ = mw.WriteField("caption", caption)
+	fw, err := mw.CreateFormFile("image", "photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write(file)
+	_ = mw.Close()
+
+	return mw.FormDataContentType(), buf.Bytes()
+}
+
+func TestHashRequest(t *testing.T) {
+	file := []byte("\xff\xd8\xff image bytes")
+	large := bytes.Repeat([]byte("x"), idempotencyMemoryBody+10)
+
+	type request struct {
+		method      string
+		target      string
+		contentType string
+		body        []byte
+	}
+	json := func(body string) request {
+		return request{"POST", "/sessions/s1/messages/text", "application/json", []byte(body)}
+	}
+	upload := func(boundary, caption string, file []byte) request {
+		contentType, body := multipartBody(t, boundary, caption, file)
+		return request{"POST", "/sessions/s1/messages/image", contentType, body}
+	}
+
+	tests := []struct {
+		name string
+		a, b request
+		same bool
+	}{
+		{"same json body", json(`{"phone":"1"}`), json(`{"phone":"1"}`), true},
+		{"different json body", json(`{"phone":"1"}`), json(`{"phone":"2"}`), false},
+		{"different method", json(`{}`), request{"PUT", "/sessions/s1/messages/text", "application/json", []byte(`{}`)}, false},
+		{"different path", json(`{}`), request{"POST", "/sessions/s2/messages/text", "application/json", []byte(`{}`)}, false},
+		{"multipart ignores the boundary", upload("aaaaaaaa", "hi", file), upload("bbbbbbbb", "hi", file), true},
+		{"multipart field changes", upload("aaaaaaaa", "hi", file), upload("bbbbbbbb", "bye", file), false},
+		{"multipart file changes", upload("aaaaaaaa", "hi", file), upload("bbbbbbbb", "hi", []byte("other")), false},
+		{"large multipart ignores the boundary", upload("aaaaaaaa", "hi", large), upload("bbbbbbbb", "hi", large), true},
+		{
+			"malformed multipart falls back to the bytes",
+			request{"POST", "/x", "multipart/form-data; boundary=zzz", []byte("not multipart")},
+			request{"POST", "/x", "multipart/form-data; boundary=yyy", []byte("not multipart")},
+			true,
+		},
+	}
+
+	hash := func(req request) string {
+		r := httptest.NewRequest(req.method, req.target, bytes.NewReader(req.body))
+		r.Header.Set("Content-Type", req.contentType)
+
+		body, h, err := hashRequest(r)
+		if err != nil {
+			t.Fatalf("hashRequest() error = %v", err)
+		}
+		defer func() { _ = body.Close() }()
+
+		got, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatalf("reading body copy: %v", err)
+		}
+		if !bytes.Equal(got, req.body) {
+			t.Fatalf("body copy differs from the request body")
+		}
+		return h
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := hash(tt.a), hash(tt.b)
+			if (a == b) != tt.same {
+				t.Errorf("hashes equal = %v, want %v", a == b, tt.same)
+			}
+			if len(a) != 64 {
+				t.Errorf("hash %q is not a hex sha256", a)
+			}
+		})
+	}
+}
+
+func TestRecordingWriter(t *testing.T) {
+	tests := []struct {
+		name          string
+		writes        []int
+		wantTruncated bool
+		wantRecorded  int
+	}{
+		{name: "small reply is kept", writes: []int{10, 20}, wantRecorded: 30},
+		{name: "reply at the limit is kept", writes: []int{idempotencyMaxReply - 1, 1}, wantRecorded: idempotencyMaxReply},
+		{name: "large reply is dropped", writes: []int{idempotencyMaxReply, 1}, wantTruncated: true},
+		{name: "writes after the limit stay dropped", writes: []int{idempotencyMaxReply + 1, 1}, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rw := &recordingWriter{ResponseWriter: rec}
+			total := 0
+			for _, n := range tt.writes {
+				if _, err := rw.Write(bytes.Repeat([]byte("x"), n)); err != nil {
+					t.Fatal(err)
+				}
+				total += n
+			}
+
+			if rec.Body.Len() != total {
+				t.Errorf("client received %d bytes, want %d", rec.Body.Len(), total)
+			}
+			if rw.truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", rw.truncated, tt.wantTruncated)
+			}
+			if rw.body.Len() != tt.wantRecorded {
+				t.Errorf("recorded %d bytes, want %d", rw.body.Len(), tt.wantRecorded)
+			}
+		})
+	}
+}
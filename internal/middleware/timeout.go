@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"regexp"
+	"time"
+
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// uploadPath matches the media send endpoints that accept multipart uploads.
+var uploadPath = regexp.MustCompile(`^/sessions/[^/]+/messages/(image|audio|video|document|sticker)/?$`)
+
+// Timeout cancels the context of a request after timeout, like chi's
+// Timeout middleware. Multipart uploads to the media send endpoints get
+// uploadTimeout instead, and the server read and write deadlines are
+// extended to match, so large files sent over slow links are not cut off.
+func Timeout(timeout, uploadTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		regular := chiMiddleware.Timeout(timeout)(next)
+		upload := chiMiddleware.Timeout(uploadTimeout)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isUpload(r) {
+				regular.ServeHTTP(w, r)
+				return
+			}
+
+			rc := http.NewResponseController(w)
+			deadline := time.Now().Add(uploadTimeout)
+			_ = rc.SetReadDeadline(deadline)
+			_ = rc.SetWriteDeadline(deadline)
+			upload.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isUpload(r *http.Request) bool {
+	if r.Method != http.MethodPost || !uploadPath.MatchString(r.URL.Path) {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "multipart/form-data"
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	ChatStatusOpen    = "open"
+	ChatStatusPending = "pending"
+	ChatStatusClosed  = "closed"
+)
+
+// ChatAssignment records which agent handles a chat of a session.
+type ChatAssignment struct {
+	SessionID  string     `json:"sessionId" db:"sessionId"`
+	Chat       string     `json:"chat" db:"chat"`
+	AgentID    string     `json:"agentId" db:"agentId"`
+	Status     string     `json:"status" db:"status"`
+	Notes      string     `json:"notes" db:"notes"`
+	AssignedAt *time.Time `json:"assignedAt,omitempty" db:"assignedAt"`
+	ClosedAt   *time.Time `json:"closedAt,omitempty" db:"closedAt"`
+	CreatedAt  time.Time  `json:"createdAt" db:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt" db:"updatedAt"`
+}
+
+// ChatAssignRequest assigns or transfers a chat to an agent. Status is open
+// (default) or pending; Notes replaces the notes of the chat when set.
+type ChatAssignRequest struct {
+	AgentID string  `json:"agent_id" example:"agent-42"`
+	Status  string  `json:"status,omitempty" example:"open"`
+	Notes   *string `json:"notes,omitempty"`
+}
+
+// ChatCloseRequest closes a chat. Notes replaces the notes of the chat when
+// set.
+type ChatCloseRequest struct {
+	Notes *string `json:"notes,omitempty"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	AutoReplyMatchExact    = "exact"
+	AutoReplyMatchContains = "contains"
+	AutoReplyMatchRegex    = "regex"
+)
+
+const (
+	AutoReplyChatAny    = "any"
+	AutoReplyChatDirect = "direct"
+	AutoReplyChatGroup  = "group"
+)
+
+// AutoReply is a rule that answers incoming messages of a session. Rules are
+// tried by descending priority and the first match answers, unless it is
+// still cooling down for that chat.
+type AutoReply struct {
+	ID              string          `json:"id" db:"id"`
+	SessionID       string          `json:"sessionId" db:"sessionId"`
+	Name            string          `json:"name" db:"name"`
+	Enabled         bool            `json:"enabled" db:"enabled"`
+	Priority        int             `json:"priority" db:"priority"`
+	MatchType       string          `json:"matchType" db:"matchType"`
+	Pattern         string          `json:"pattern" db:"pattern"`
+	CaseSensitive   bool            `json:"caseSensitive" db:"caseSensitive"`
+	ChatType        string          `json:"chatType" db:"chatType"`
+	Allow           pq.StringArray  `json:"allow" db:"allow" swaggertype:"array,string"`
+	Deny            pq.StringArray  `json:"deny" db:"deny" swaggertype:"array,string"`
+	Window          *TimeWindow     `json:"window,omitempty" db:"window"`
+	Kind            string          `json:"kind" db:"kind"`
+	Payload         json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	TemplateID      string          `json:"templateId,omitempty" db:"templateId"`
+	Quote           bool            `json:"quote" db:"quote"`
+	CooldownSeconds int             `json:"cooldownSeconds" db:"cooldownSeconds"`
+	CreatedAt       time.Time       `json:"createdAt" db:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt" db:"updatedAt"`
+}
+
+// AutoReplyRequest creates or replaces an auto-reply rule.
+//
+// MatchType is exact, contains or regex and is tested against the message
+// text. ChatType limits the rule to direct or group chats. Allow and Deny
+// list sender phone numbers or JIDs; an empty Allow list allows everyone.
+// Window limits the rule to business hours.
+//
+// Kind and Payload follow the scheduled message format without the phone,
+// or Kind is "template" with TemplateID. Payload strings and templates may use
+// the {{phone}}, {{name}}, {{chat}} and {{text}} placeholders of the incoming
+// message. Quote replies to the incoming message. CooldownSeconds is how long
+// the rule stays silent in a chat after answering it (default 60).
+type AutoReplyRequest struct {
+	Name            string          `json:"name" example:"opening-hours"`
+	Enabled         *bool           `json:"enabled,omitempty"`
+	Priority        int             `json:"priority,omitempty"`
+	MatchType       string          `json:"match_type" example:"contains"`
+	Pattern         string          `json:"pattern" example:"hours"`
+	CaseSensitive   bool            `json:"case_sensitive,omitempty"`
+	ChatType        string          `json:"chat_type,omitempty" example:"direct"`
+	Allow           []string        `json:"allow,omitempty"`
+	Deny            []string        `json:"deny,omitempty"`
+	Window          *TimeWindow     `json:"window,omitempty"`
+	Kind            string          `json:"kind" example:"text"`
+	Payload         json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+	TemplateID      string          `json:"template_id,omitempty"`
+	Quote           bool            `json:"quote,omitempty"`
+	CooldownSeconds int             `json:"cooldown_seconds,omitempty" example:"60"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	CallPolicyAlways      = "always"
+	CallPolicyHours       = "hours"
+	CallPolicyNonContacts = "non_contacts"
+)
+
+// CallPolicy decides which incoming calls of a session are rejected
+// automatically. With mode "hours" calls are rejected while Window matches.
+type CallPolicy struct {
+	SessionID string      `json:"sessionId" db:"sessionId"`
+	Mode      string      `json:"mode" db:"mode"`
+	Window    *TimeWindow `json:"window,omitempty" db:"window"`
+	Message   string      `json:"message,omitempty" db:"message"`
+	UpdatedAt time.Time   `json:"updatedAt" db:"updatedAt"`
+}
+
+type CallPolicyRequest struct {
+	Mode    string      `json:"mode" example:"always"`
+	Window  *TimeWindow `json:"window,omitempty"`
+	Message string      `json:"message,omitempty" example:"Sorry, we can't take calls. Please send us a message."`
+}
+
+// CallLog is a call that was rejected by a call policy.
+type CallLog struct {
+	ID          string    `json:"id" db:"id"`
+	SessionID   string    `json:"sessionId" db:"sessionId"`
+	CallID      string    `json:"callId" db:"callId"`
+	Caller      string    `json:"caller" db:"caller"`
+	Mode        string    `json:"mode" db:"mode"`
+	MessageSent bool      `json:"messageSent" db:"messageSent"`
+	Error       string    `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time `json:"createdAt" db:"createdAt"`
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	CampaignStatusDraft     = "draft"
+	CampaignStatusRunning   = "running"
+	CampaignStatusPaused    = "paused"
+	CampaignStatusCanceled  = "canceled"
+	CampaignStatusCompleted = "completed"
+)
+
+const (
+	RecipientStatusPending = "pending"
+	RecipientStatusSending = "sending"
+	RecipientStatusSent    = "sent"
+	RecipientStatusFailed  = "failed"
+	RecipientStatusInvalid = "invalid"
+)
+
+type Campaign struct {
+	ID            string            `json:"id" db:"id"`
+	UserID        string            `json:"userId" db:"userId"`
+	SessionID     string            `json:"sessionId" db:"sessionId"`
+	Name          string            `json:"name" db:"name"`
+	Kind          string            `json:"kind" db:"kind"`
+	Payload       json.RawMessage   `json:"payload" db:"payload" swaggertype:"object"`
+	TemplateID    string            `json:"templateId,omitempty" db:"templateId"`
+	RatePerMinute int               `json:"ratePerMinute" db:"ratePerMinute"`
+	JitterSeconds int               `json:"jitterSeconds" db:"jitterSeconds"`
+	Window        *TimeWindow       `json:"window,omitempty" db:"window"`
+	Status        string            `json:"status" db:"status"`
+	LeaseOwner    string            `json:"-" db:"leaseOwner"`
+	LeaseUntil    *time.Time        `json:"-" db:"leaseUntil"`
+	CreatedAt     time.Time         `json:"createdAt" db:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt" db:"updatedAt"`
+	CompletedAt   *time.Time        `json:"completedAt,omitempty" db:"completedAt"`
+	Progress      *CampaignProgress `json:"progress,omitempty" db:"-"`
+	// Rejected lists the malformed phones skipped when the campaign was
+	// created.
+	Rejected []string `json:"rejected,omitempty" db:"-"`
+}
+
+type CampaignProgress struct {
+	Total   int `json:"total" db:"total"`
+	Pending int `json:"pending" db:"pending"`
+	Sending int `json:"sending" db:"sending"`
+	Sent    int `json:"sent" db:"sent"`
+	Failed  int `json:"failed" db:"failed"`
+	Invalid int `json:"invalid" db:"invalid"`
+}
+
+type CampaignRecipient struct {
+	CampaignID string          `json:"campaignId" db:"campaignId"`
+	Phone      string          `json:"phone" db:"phone"`
+	Variables  json.RawMessage `json:"variables" db:"variables" swaggertype:"object"`
+	Status     string          `json:"status" db:"status"`
+	Error      string          `json:"error,omitempty" db:"error"`
+	MessageID  string          `json:"messageId,omitempty" db:"messageId"`
+	UpdatedAt  time.Time       `json:"updatedAt" db:"updatedAt"`
+}
+
+// CampaignRecipientInput is a recipient of a campaign. Variables fill the
+// {{name}} placeholders of the campaign message; {{phone}} is always set.
+type CampaignRecipientInput struct {
+	Phone     string            `json:"phone" example:"5511999999999"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// CampaignCreateRequest creates a draft campaign. Kind and payload follow the
+// scheduled message format, or Kind is "template" with TemplateID. Payload
+// strings and templates may contain {{name}} placeholders and the phone is
+// set per recipient. RatePerMinute defaults to QUEUE_RATE.
+type CampaignCreateRequest struct {
+	Name          string                   `json:"name" example:"black-friday"`
+	Kind          string                   `json:"kind" example:"text"`
+	Payload       json.RawMessage          `json:"payload,omitempty" swaggertype:"object"`
+	TemplateID    string                   `json:"template_id,omitempty"`
+	RatePerMinute int                      `json:"rate_per_minute,omitempty" example:"20"`
+	JitterSeconds int                      `json:"jitter_seconds,omitempty" example:"5"`
+	Window        *TimeWindow              `json:"window,omitempty"`
+	Recipients    []CampaignRecipientInput `json:"recipients,omitempty"`
+}
+
+type CampaignRecipientsRequest struct {
+	Recipients []CampaignRecipientInput `json:"recipients"`
+}
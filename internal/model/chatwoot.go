@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// ChatwootConfig connects a session to an API channel inbox of Chatwoot.
+// Incoming messages are posted to the inbox, and replies of agents reach the
+// session through the Chatwoot webhook at WebhookPath.
+type ChatwootConfig struct {
+	SessionID    string    `json:"sessionId" db:"sessionId"`
+	Enabled      bool      `json:"enabled" db:"enabled"`
+	BaseURL      string    `json:"baseUrl" db:"baseUrl"`
+	AccountID    int64     `json:"accountId" db:"accountId"`
+	InboxID      int64     `json:"inboxId" db:"inboxId"`
+	APIToken     string    `json:"-" db:"apiToken"`
+	WebhookToken string    `json:"-" db:"webhookToken"`
+	WebhookPath  string    `json:"webhookPath" db:"-"`
+	IgnoreGroups bool      `json:"ignoreGroups" db:"ignoreGroups"`
+	CreatedAt    time.Time `json:"createdAt" db:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updatedAt"`
+}
+
+// ChatwootConfigRequest configures the Chatwoot bridge of a session. Enabled
+// and ignore_groups default to true; api_token may be left empty to keep the
+// stored one. A base_url on a private address must be listed in
+// ALLOWED_PRIVATE_HOSTS.
+type ChatwootConfigRequest struct {
+	Enabled      *bool  `json:"enabled,omitempty"`
+	BaseURL      string `json:"base_url" example:"https://app.chatwoot.com"`
+	AccountID    int64  `json:"account_id" example:"1"`
+	InboxID      int64  `json:"inbox_id" example:"3"`
+	APIToken     string `json:"api_token,omitempty"`
+	IgnoreGroups *bool  `json:"ignore_groups,omitempty"`
+}
+
+// ChatwootConversation links a chat of a session to its Chatwoot contact and
+// conversation.
+type ChatwootConversation struct {
+	SessionID      string    `db:"sessionId"`
+	Chat           string    `db:"chat"`
+	ContactID      int64     `db:"contactId"`
+	ConversationID int64     `db:"conversationId"`
+	CreatedAt      time.Time `db:"createdAt"`
+}
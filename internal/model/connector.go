@@ -0,0 +1,95 @@
+package model
+
+import "time"
+
+// Connector action types.
+const (
+	ConnectorActionText   = "text"
+	ConnectorActionMedia  = "media"
+	ConnectorActionReact  = "react"
+	ConnectorActionRead   = "read"
+	ConnectorActionTyping = "typing"
+)
+
+// Connector forwards the incoming messages of a session to an HTTP endpoint,
+// such as a chatbot or LLM gateway, and runs the actions it answers with.
+// Messages of a chat that arrive within DebounceMs of each other are sent in
+// one call.
+type Connector struct {
+	SessionID       string    `json:"sessionId" db:"sessionId"`
+	Enabled         bool      `json:"enabled" db:"enabled"`
+	URL             string    `json:"url" db:"url"`
+	AuthToken       string    `json:"-" db:"authToken"`
+	TimeoutSeconds  int       `json:"timeoutSeconds" db:"timeoutSeconds"`
+	DebounceMs      int       `json:"debounceMs" db:"debounceMs"`
+	FallbackMessage string    `json:"fallbackMessage,omitempty" db:"fallbackMessage"`
+	IgnoreGroups    bool      `json:"ignoreGroups" db:"ignoreGroups"`
+	CreatedAt       time.Time `json:"createdAt" db:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt" db:"updatedAt"`
+}
+
+// ConnectorRequest configures the connector of a session.
+//
+// The endpoint must be an https URL on a public address; hosts listed in
+// ALLOWED_PRIVATE_HOSTS may also use http and private addresses. It
+// receives a ConnectorCall as JSON, with auth_token as bearer
+// token when set, and answers with a ConnectorResponse. An omitted auth_token
+// keeps the stored one. TimeoutSeconds limits the call (default 30, max 120)
+// and DebounceMs is how long to wait for more messages of a chat before
+// calling (default 1500, 0 calls at once). When the call fails,
+// FallbackMessage is sent to the chat if set.
+type ConnectorRequest struct {
+	Enabled         *bool   `json:"enabled,omitempty"`
+	URL             string  `json:"url" example:"https://bot.example.com/whatsapp"`
+	AuthToken       *string `json:"auth_token,omitempty"`
+	TimeoutSeconds  int     `json:"timeout_seconds,omitempty" example:"30"`
+	DebounceMs      *int    `json:"debounce_ms,omitempty" example:"1500"`
+	FallbackMessage string  `json:"fallback_message,omitempty" example:"Sorry, something went wrong. Please try again later."`
+	IgnoreGroups    *bool   `json:"ignore_groups,omitempty"`
+}
+
+// ConnectorCall is the body posted to the connector endpoint with the
+// messages of a chat received during the debounce period.
+type ConnectorCall struct {
+	SessionID string             `json:"sessionId"`
+	Chat      string             `json:"chat"`
+	Phone     string             `json:"phone,omitempty"`
+	Name      string             `json:"name,omitempty"`
+	IsGroup   bool               `json:"isGroup"`
+	Messages  []ConnectorMessage `json:"messages"`
+}
+
+type ConnectorMessage struct {
+	ID        string `json:"id"`
+	Sender    string `json:"sender"`
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	ReplyID   string `json:"replyId,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ConnectorResponse lists the actions to run, in order, for a call.
+type ConnectorResponse struct {
+	Actions []ConnectorAction `json:"actions"`
+}
+
+// ConnectorAction is one step of a connector response.
+//
+//   - text sends Text, quoting the last message when Quote is set.
+//   - media sends the file at URL (https or data URL) as MediaType image,
+//     video, audio, document or sticker, with Caption and FileName.
+//   - react reacts with Emoji to MessageID, by default the last message.
+//   - read marks the messages of the call as read.
+//   - typing shows the typing indicator for DurationMs (max 20000).
+type ConnectorAction struct {
+	Type       string `json:"type" example:"text"`
+	Text       string `json:"text,omitempty"`
+	Quote      bool   `json:"quote,omitempty"`
+	MediaType  string `json:"media_type,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Caption    string `json:"caption,omitempty"`
+	FileName   string `json:"filename,omitempty"`
+	Emoji      string `json:"emoji,omitempty"`
+	MessageID  string `json:"message_id,omitempty"`
+	DurationMs int    `json:"duration_ms,omitempty"`
+}
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	FlowStatusActive  = "active"
+	FlowStatusHandoff = "handoff"
+)
+
+// Flow is the conversational flow of a session.
+type Flow struct {
+	SessionID  string          `json:"sessionId" db:"sessionId"`
+	Definition json.RawMessage `json:"definition" db:"definition" swaggertype:"object"`
+	Enabled    bool            `json:"enabled" db:"enabled"`
+	UpdatedAt  time.Time       `json:"updatedAt" db:"updatedAt"`
+}
+
+// FlowDefinition is a state machine that answers incoming messages.
+//
+// A chat without a conversation enters Start when a message matches one of
+// Triggers, or with any message if there are none. A conversation that gets
+// no message for TimeoutSeconds (default 1800) starts over. Enabled false
+// keeps the flow stored without running it.
+type FlowDefinition struct {
+	Start                 string              `json:"start" yaml:"start" example:"menu"`
+	Triggers              FlowMatch           `json:"triggers,omitempty" yaml:"triggers" swaggertype:"array,string"`
+	TimeoutSeconds        int                 `json:"timeout_seconds,omitempty" yaml:"timeout_seconds" example:"1800"`
+	HandoffTimeoutSeconds int                 `json:"handoff_timeout_seconds,omitempty" yaml:"handoff_timeout_seconds"`
+	Enabled               *bool               `json:"enabled,omitempty" yaml:"enabled"`
+	States                map[string]FlowNode `json:"states" yaml:"states"`
+}
+
+// FlowNode is a state of a flow. Message is sent when the state is entered.
+// The next message of the contact is checked against Transitions in order;
+// without a match the conversation moves to Default, or Invalid is sent and
+// the state is kept. Next moves on right after Message without waiting.
+// Handoff pauses the flow in the chat for a human until the conversation is
+// reset or HandoffTimeoutSeconds pass; End finishes the conversation.
+type FlowNode struct {
+	Message     *FlowMessage     `json:"message,omitempty" yaml:"message"`
+	Transitions []FlowTransition `json:"transitions,omitempty" yaml:"transitions"`
+	Default     string           `json:"default,omitempty" yaml:"default"`
+	Invalid     *FlowMessage     `json:"invalid,omitempty" yaml:"invalid"`
+	Next        string           `json:"next,omitempty" yaml:"next"`
+	Handoff     bool             `json:"handoff,omitempty" yaml:"handoff"`
+	End         bool             `json:"end,omitempty" yaml:"end"`
+}
+
+// FlowTransition moves to Next when the message text equals one of Match
+// (ignoring case), the id of a chosen list row or button equals one of Match,
+// or the text matches Regex.
+type FlowTransition struct {
+	Match FlowMatch `json:"match,omitempty" yaml:"match" swaggertype:"array,string" example:"1,billing"`
+	Regex string    `json:"regex,omitempty" yaml:"regex"`
+	Next  string    `json:"next" yaml:"next" example:"billing"`
+}
+
+// FlowMatch is a list of texts a message is compared with. Numbers and
+// booleans are taken as their text, and a single value as a list of one, so
+// match: [1, billing] works as written in YAML.
+type FlowMatch []string
+
+func (m *FlowMatch) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		items = []json.RawMessage{data}
+	}
+
+	list := make(FlowMatch, 0, len(items))
+	for _, item := range items {
+		dec := json.NewDecoder(bytes.NewReader(item))
+		dec.UseNumber()
+
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case string:
+			list = append(list, v)
+		case json.Number:
+			list = append(list, v.String())
+		case bool:
+			list = append(list, strconv.FormatBool(v))
+		default:
+			return fmt.Errorf("match values must be text, got %s", item)
+		}
+	}
+
+	*m = list
+	return nil
+}
+
+func (m *FlowMatch) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*m = FlowMatch{value.Value}
+		return nil
+	}
+
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*m = list
+	return nil
+}
+
+// FlowMessage is sent by a flow. Kind and Payload follow the scheduled
+// message format without the phone; payload strings may use the {{phone}},
+// {{name}}, {{chat}} and {{text}} placeholders.
+type FlowMessage struct {
+	Kind    string          `json:"kind" example:"text"`
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+// UnmarshalYAML reads the payload as a YAML mapping and keeps it as JSON.
+func (m *FlowMessage) UnmarshalYAML(value *yaml.Node) error {
+	var msg struct {
+		Kind    string      `yaml:"kind"`
+		Payload interface{} `yaml:"payload"`
+	}
+	if err := value.Decode(&msg); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(jsonValue(msg.Payload))
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	m.Kind = msg.Kind
+	m.Payload = payload
+	return nil
+}
+
+// jsonValue turns the maps of a decoded YAML value into maps with string
+// keys, which JSON can encode.
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = jsonValue(item)
+		}
+		return val
+	case map[interface{}]interface{}:
+		doc := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			doc[fmt.Sprint(k)] = jsonValue(item)
+		}
+		return doc
+	case []interface{}:
+		for i, item := range val {
+			val[i] = jsonValue(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// FlowState is the conversation of a contact with the flow of a session.
+type FlowState struct {
+	SessionID string     `json:"sessionId" db:"sessionId"`
+	Chat      string     `json:"chat" db:"chat"`
+	State     string     `json:"state" db:"state"`
+	Status    string     `json:"status" db:"status"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" db:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt" db:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt" db:"updatedAt"`
+}
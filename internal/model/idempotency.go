@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	IdempotencyStatusProcessing = "processing"
+	IdempotencyStatusDone       = "done"
+)
+
+// IdempotencyRecord is the stored response to a request sent with an
+// Idempotency-Key header.
+type IdempotencyRecord struct {
+	UserID       string    `db:"userId"`
+	Key          string    `db:"key"`
+	RequestHash  string    `db:"requestHash"`
+	Status       string    `db:"status"`
+	StatusCode   int       `db:"statusCode"`
+	ContentType  string    `db:"contentType"`
+	ResponseBody []byte    `db:"responseBody"`
+	CreatedAt    time.Time `db:"createdAt"`
+	ExpiresAt    time.Time `db:"expiresAt"`
+}
@@ -1,9 +1,64 @@
 package model
 
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// MediaUpload carries a file uploaded in a multipart/form-data request.
+type MediaUpload struct {
+	Reader   io.Reader
+	FileName string
+	MimeType string
+}
+
+// ReplyTo identifies the message quoted by a send request. Participant is the
+// sender of the quoted message and Text its body; both are looked up in the
+// message store when omitted.
+type ReplyTo struct {
+	MessageID   string `json:"message_id"`
+	Participant string `json:"participant,omitempty"`
+	Text        string `json:"text,omitempty"`
+}
+
+// Typing shows the typing indicator, or recording for voice notes, before a
+// message is delivered. DurationMs is the time to type; otherwise it is
+// derived from the words of the text or caption at WPM words per minute.
+type Typing struct {
+	DurationMs int `json:"duration_ms,omitempty" example:"2000"`
+	WPM        int `json:"wpm,omitempty" example:"40"`
+}
+
+// SendOptions holds the options accepted by every send request. Queue stores
+// the request in the send queue instead of sending it right away; a queued
+// request types when it is sent by the queue.
+type SendOptions struct {
+	ReplyTo  *ReplyTo `json:"reply_to,omitempty"`
+	Mentions []string `json:"mentions,omitempty"`
+	Typing   *Typing  `json:"typing,omitempty"`
+	Queue    bool     `json:"queue,omitempty"`
+}
+
+// GetSendOptions gives access to the send options of any request embedding them.
+func (o *SendOptions) GetSendOptions() *SendOptions { return o }
+
+// LinkPreview is the preview card shown for the first URL of a text message.
+// Thumbnail is a base64 data URL of an image.
+type LinkPreview struct {
+	URL         string `json:"url,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Thumbnail   string `json:"thumbnail,omitempty"`
+}
+
 type TextMessage struct {
-	Phone   string `json:"phone"`
-	Message string `json:"message"`
-	ID      string `json:"id,omitempty"`
+	Phone       string       `json:"phone"`
+	Message     string       `json:"message"`
+	ID          string       `json:"id,omitempty"`
+	LinkPreview bool         `json:"link_preview,omitempty"`
+	Preview     *LinkPreview `json:"preview,omitempty"`
+	SendOptions
 }
 
 type ImageMessage struct {
@@ -12,6 +67,9 @@ type ImageMessage struct {
 	Caption  string `json:"caption,omitempty"`
 	ID       string `json:"id,omitempty"`
 	MimeType string `json:"mimetype,omitempty"`
+	SendOptions
+
+	Upload *MediaUpload `json:"-" swaggerignore:"true"`
 }
 
 type AudioMessage struct {
@@ -20,6 +78,9 @@ type AudioMessage struct {
 	ID       string `json:"id,omitempty"`
 	PTT      *bool  `json:"ptt,omitempty"`
 	MimeType string `json:"mimetype,omitempty"`
+	SendOptions
+
+	Upload *MediaUpload `json:"-" swaggerignore:"true"`
 }
 
 type VideoMessage struct {
@@ -28,6 +89,9 @@ type VideoMessage struct {
 	Caption  string `json:"caption,omitempty"`
 	ID       string `json:"id,omitempty"`
 	MimeType string `json:"mimetype,omitempty"`
+	SendOptions
+
+	Upload *MediaUpload `json:"-" swaggerignore:"true"`
 }
 
 type DocumentMessage struct {
@@ -37,6 +101,9 @@ type DocumentMessage struct {
 	Caption  string `json:"caption,omitempty"`
 	ID       string `json:"id,omitempty"`
 	MimeType string `json:"mimetype,omitempty"`
+	SendOptions
+
+	Upload *MediaUpload `json:"-" swaggerignore:"true"`
 }
 
 type LocationMessage struct {
@@ -46,6 +113,7 @@ type LocationMessage struct {
 	Name      string  `json:"name,omitempty"`
 	Address   string  `json:"address,omitempty"`
 	ID        string  `json:"id,omitempty"`
+	SendOptions
 }
 
 type ContactMessage struct {
@@ -53,6 +121,7 @@ type ContactMessage struct {
 	ContactName  string `json:"contact_name"`
 	ContactVCard string `json:"contact_vcard"`
 	ID           string `json:"id,omitempty"`
+	SendOptions
 }
 
 type ReactionMessage struct {
@@ -76,6 +145,9 @@ type StickerMessage struct {
 	PackName      string   `json:"pack_name,omitempty"`
 	PackPublisher string   `json:"pack_publisher,omitempty"`
 	Emojis        []string `json:"emojis,omitempty"`
+	SendOptions
+
+	Upload *MediaUpload `json:"-" swaggerignore:"true"`
 }
 
 type PollMessage struct {
@@ -83,6 +155,7 @@ type PollMessage struct {
 	Header  string   `json:"header"`
 	Options []string `json:"options"`
 	ID      string   `json:"id,omitempty"`
+	SendOptions
 }
 
 type ListItem struct {
@@ -104,6 +177,7 @@ type ListMessage struct {
 	Sections   []ListSection `json:"sections"`
 	FooterText string        `json:"footer_text,omitempty"`
 	ID         string        `json:"id,omitempty"`
+	SendOptions
 }
 
 type ButtonItem struct {
@@ -116,6 +190,7 @@ type ButtonsMessage struct {
 	Title   string       `json:"title"`
 	Buttons []ButtonItem `json:"buttons"`
 	ID      string       `json:"id,omitempty"`
+	SendOptions
 }
 
 type EditMessage struct {
@@ -135,11 +210,51 @@ type ArchiveChatMessage struct {
 	Archive bool   `json:"archive"`
 }
 
+type PinChatMessage struct {
+	Phone string `json:"phone"`
+	Pin   bool   `json:"pin"`
+}
+
+// MuteChatMessage mutes a chat for DurationSeconds, or forever when it is 0.
+type MuteChatMessage struct {
+	Phone           string `json:"phone"`
+	Mute            bool   `json:"mute"`
+	DurationSeconds int64  `json:"duration_seconds,omitempty" example:"28800"`
+}
+
+type ReadChatMessage struct {
+	Phone string `json:"phone"`
+	Read  bool   `json:"read"`
+}
+
+type DeleteChatMessage struct {
+	Phone string `json:"phone"`
+}
+
+// ClearChatMessage clears the messages of a chat, keeping the starred ones
+// when KeepStarred is set.
+type ClearChatMessage struct {
+	Phone       string `json:"phone"`
+	KeepStarred bool   `json:"keep_starred,omitempty"`
+}
+
+// StarMessage stars or unstars a message. SenderPhone is the author of a
+// message received in a group.
+type StarMessage struct {
+	Phone       string `json:"phone"`
+	MessageID   string `json:"message_id"`
+	SenderPhone string `json:"sender_phone,omitempty"`
+	FromMe      bool   `json:"from_me,omitempty"`
+	Star        bool   `json:"star"`
+}
+
 type StatusTextMessage struct {
 	Body string `json:"body"`
 }
 
 type DownloadMediaMessage struct {
+	Type          string `json:"type,omitempty"`
+	MessageID     string `json:"message_id,omitempty"`
 	URL           string `json:"url"`
 	DirectPath    string `json:"direct_path"`
 	MediaKey      []byte `json:"media_key"`
@@ -182,6 +297,12 @@ type RejectCallRequest struct {
 	CallID   string `json:"call_id"`
 }
 
+// BlocklistRequest lists the contacts to block or unblock as phone numbers or
+// JIDs.
+type BlocklistRequest struct {
+	Phone []string `json:"phone" example:"5511999999999"`
+}
+
 type ConnectRequest struct {
 	Subscribe []string `json:"subscribe,omitempty"`
 	Immediate bool     `json:"immediate,omitempty"`
@@ -195,3 +316,19 @@ type WebhookRequest struct {
 type PairPhoneRequest struct {
 	Phone string `json:"phone"`
 }
+
+type StoredMessage struct {
+	ID        string          `json:"id" db:"id"`
+	SessionID string          `json:"sessionId" db:"sessionId"`
+	Chat      string          `json:"chat" db:"chat"`
+	Sender    string          `json:"sender" db:"sender"`
+	FromMe    bool            `json:"fromMe" db:"fromMe"`
+	Type      string          `json:"type" db:"type"`
+	Text      string          `json:"text" db:"text"`
+	Raw       []byte          `json:"-" db:"raw"`
+	Timestamp time.Time       `json:"timestamp" db:"timestamp"`
+	CreatedAt time.Time       `json:"createdAt" db:"createdAt"`
+	EditedAt  *time.Time      `json:"editedAt,omitempty" db:"editedAt"`
+	Revoked   bool            `json:"revoked" db:"revoked"`
+	Reactions json.RawMessage `json:"reactions" db:"reactions" swaggertype:"object"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Poll struct {
+	ID              string         `json:"id" db:"id"`
+	SessionID       string         `json:"sessionId" db:"sessionId"`
+	Chat            string         `json:"chat" db:"chat"`
+	Sender          string         `json:"sender" db:"sender"`
+	Name            string         `json:"name" db:"name"`
+	Options         pq.StringArray `json:"options" db:"options" swaggertype:"array,string"`
+	SelectableCount int            `json:"selectableCount" db:"selectableCount"`
+	CreatedAt       time.Time      `json:"createdAt" db:"createdAt"`
+}
+
+// PollVote is the current selection of one voter. An empty selection means
+// the vote was withdrawn.
+type PollVote struct {
+	PollID    string         `json:"pollId" db:"pollId"`
+	SessionID string         `json:"sessionId" db:"sessionId"`
+	Voter     string         `json:"voter" db:"voter"`
+	Options   pq.StringArray `json:"options" db:"options" swaggertype:"array,string"`
+	Timestamp time.Time      `json:"timestamp" db:"timestamp"`
+}
+
+type PollOptionResult struct {
+	Name   string   `json:"name"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+type PollResults struct {
+	Poll    *Poll              `json:"poll"`
+	Voters  int                `json:"voters"`
+	Options []PollOptionResult `json:"options"`
+}
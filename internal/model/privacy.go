@@ -0,0 +1,31 @@
+package model
+
+// PrivacySettings are the WhatsApp privacy settings of the account of a
+// session.
+type PrivacySettings struct {
+	LastSeen     string `json:"lastSeen"`
+	Online       string `json:"online"`
+	Profile      string `json:"profile"`
+	About        string `json:"about"`
+	GroupAdd     string `json:"groupAdd"`
+	ReadReceipts string `json:"readReceipts"`
+	CallAdd      string `json:"callAdd"`
+}
+
+// PrivacySettingsRequest changes the privacy settings that are set; omitted
+// ones are kept.
+//
+//   - last_seen, profile, about and group_add: all, contacts,
+//     contact_blacklist or none.
+//   - online: all or match_last_seen.
+//   - read_receipts: all or none.
+//   - call_add: all or known.
+type PrivacySettingsRequest struct {
+	LastSeen     *string `json:"last_seen,omitempty" example:"contacts"`
+	Online       *string `json:"online,omitempty" example:"match_last_seen"`
+	Profile      *string `json:"profile,omitempty" example:"contacts"`
+	About        *string `json:"about,omitempty" example:"all"`
+	GroupAdd     *string `json:"group_add,omitempty" example:"contact_blacklist"`
+	ReadReceipts *string `json:"read_receipts,omitempty" example:"all"`
+	CallAdd      *string `json:"call_add,omitempty" example:"known"`
+}
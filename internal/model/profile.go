@@ -0,0 +1,25 @@
+package model
+
+// Profile is the WhatsApp profile of the account of a session.
+type Profile struct {
+	JID        string `json:"jid"`
+	LID        string `json:"lid,omitempty"`
+	PushName   string `json:"pushName"`
+	About      string `json:"about"`
+	PictureID  string `json:"pictureId,omitempty"`
+	PictureURL string `json:"pictureUrl,omitempty"`
+}
+
+type ProfileNameRequest struct {
+	Name string `json:"name" example:"FioZap Support"`
+}
+
+type ProfileAboutRequest struct {
+	About string `json:"about" example:"Available 9am-6pm"`
+}
+
+// ProfilePhotoRequest sets the profile photo from a base64 data URL or an
+// https URL. The image is converted to a square-fitting JPEG.
+type ProfilePhotoRequest struct {
+	Image string `json:"image" example:"data:image/jpeg;base64,/9j/4AAQ..."`
+}
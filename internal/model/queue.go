@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	QueueStatusPending  = "pending"
+	QueueStatusSending  = "sending"
+	QueueStatusSent     = "sent"
+	QueueStatusFailed   = "failed"
+	QueueStatusCanceled = "canceled"
+)
+
+type QueueItem struct {
+	ID        string          `json:"id" db:"id"`
+	UserID    string          `json:"userId" db:"userId"`
+	SessionID string          `json:"sessionId" db:"sessionId"`
+	Kind      string          `json:"kind" db:"kind"`
+	Payload   json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Status    string          `json:"status" db:"status"`
+	Attempts  int             `json:"attempts" db:"attempts"`
+	Error     string          `json:"error,omitempty" db:"error"`
+	MessageID string          `json:"messageId,omitempty" db:"messageId"`
+	NotBefore time.Time       `json:"notBefore" db:"notBefore"`
+	CreatedAt time.Time       `json:"createdAt" db:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updatedAt"`
+}
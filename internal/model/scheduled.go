@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ScheduledStatusScheduled = "scheduled"
+	ScheduledStatusSending   = "sending"
+	ScheduledStatusSent      = "sent"
+	ScheduledStatusFailed    = "failed"
+	ScheduledStatusCanceled  = "canceled"
+)
+
+type ScheduledMessage struct {
+	ID        string          `json:"id" db:"id"`
+	UserID    string          `json:"userId" db:"userId"`
+	SessionID string          `json:"sessionId" db:"sessionId"`
+	Kind      string          `json:"kind" db:"kind"`
+	Payload   json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	SendAt    time.Time       `json:"sendAt" db:"sendAt"`
+	Timezone  string          `json:"timezone" db:"timezone"`
+	Status    string          `json:"status" db:"status"`
+	Error     string          `json:"error,omitempty" db:"error"`
+	MessageID string          `json:"messageId,omitempty" db:"messageId"`
+	CreatedAt time.Time       `json:"createdAt" db:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updatedAt"`
+}
+
+// ScheduleRequest schedules any send payload. Kind names the send endpoint
+// (text, image, audio, video, document, location, contact, sticker, poll,
+// list, buttons) and payload is its request body. SendAt is RFC 3339, or a
+// local "2006-01-02 15:04[:05]" time in Timezone (IANA name, default UTC).
+type ScheduleRequest struct {
+	Kind     string          `json:"kind" example:"text"`
+	Payload  json.RawMessage `json:"payload" swaggertype:"object"`
+	SendAt   string          `json:"send_at" example:"2026-01-02 09:00"`
+	Timezone string          `json:"timezone,omitempty" example:"America/Sao_Paulo"`
+}
+
+type RescheduleRequest struct {
+	SendAt   string `json:"send_at" example:"2026-01-02 09:00"`
+	Timezone string `json:"timezone,omitempty" example:"America/Sao_Paulo"`
+}
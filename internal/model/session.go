@@ -13,6 +13,24 @@ type Session struct {
 	Events    string    `json:"events,omitempty" db:"events"`
 	ProxyURL  string    `json:"proxyUrl,omitempty" db:"proxyUrl"`
 	CreatedAt time.Time `json:"createdAt" db:"createdAt"`
+
+	// Alert records the last ban, replaced stream or connect failure of the
+	// session until it connects again.
+	Alert       string     `json:"alert,omitempty" db:"alert"`
+	AlertReason string     `json:"alertReason,omitempty" db:"alertReason"`
+	AlertAt     *time.Time `json:"alertAt,omitempty" db:"alertAt"`
+	BannedUntil *time.Time `json:"bannedUntil,omitempty" db:"bannedUntil"`
+}
+
+const (
+	SessionAlertTemporaryBan   = "temporary_ban"
+	SessionAlertStreamReplaced = "stream_replaced"
+	SessionAlertConnectFailure = "connect_failure"
+)
+
+// Banned reports whether a temporary ban of the session is still active.
+func (s *Session) Banned() bool {
+	return s.BannedUntil != nil && time.Now().Before(*s.BannedUntil)
 }
 
 type SessionCreateRequest struct {
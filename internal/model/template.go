@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Template struct {
+	ID        string          `json:"id" db:"id"`
+	UserID    string          `json:"userId" db:"userId"`
+	Name      string          `json:"name" db:"name"`
+	Kind      string          `json:"kind" db:"kind"`
+	Payload   json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Defaults  json.RawMessage `json:"defaults" db:"defaults" swaggertype:"object"`
+	Variables []string        `json:"variables" db:"-"`
+	CreatedAt time.Time       `json:"createdAt" db:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updatedAt"`
+}
+
+// TemplateRequest creates or replaces a template. Kind and payload follow the
+// scheduled message format without the phone, so a template can hold text,
+// a media URL with caption, buttons or a list. Payload strings may contain
+// {{name}} placeholders; Defaults gives values for the optional ones.
+type TemplateRequest struct {
+	Name     string            `json:"name" example:"welcome"`
+	Kind     string            `json:"kind" example:"text"`
+	Payload  json.RawMessage   `json:"payload" swaggertype:"object"`
+	Defaults map[string]string `json:"defaults,omitempty"`
+}
+
+// TemplateSendRequest renders a template, chosen by id or name, and sends it
+// to phone. Every placeholder without a default must be in Variables.
+type TemplateSendRequest struct {
+	TemplateID   string            `json:"template_id,omitempty"`
+	TemplateName string            `json:"template_name,omitempty" example:"welcome"`
+	Phone        string            `json:"phone" example:"5511999999999"`
+	Variables    map[string]string `json:"variables,omitempty"`
+	SendOptions
+}
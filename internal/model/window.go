@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// TimeWindow limits an activity to a daily time range. Start and End are
+// "15:04" clock times in Timezone (IANA name, default UTC); an End before
+// Start spans midnight. Days lists weekdays ("mon".."sun"), empty means every
+// day.
+type TimeWindow struct {
+	Start    string   `json:"start" example:"09:00"`
+	End      string   `json:"end" example:"18:00"`
+	Timezone string   `json:"timezone,omitempty" example:"America/Sao_Paulo"`
+	Days     []string `json:"days,omitempty" example:"mon,tue,wed,thu,fri"`
+}
+
+// Scan reads a window stored as JSONB.
+func (w *TimeWindow) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	default:
+		return errors.New("unsupported time window value")
+	}
+}
+
+// Value stores the window as JSONB.
+func (w *TimeWindow) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return json.Marshal(w)
+}
@@ -17,11 +17,17 @@ import (
 	"fiozap/internal/webhook"
 )
 
-const requestTimeout = 60 * time.Second
+const (
+	requestTimeout = 60 * time.Second
+	uploadTimeout  = 15 * time.Minute
+)
 
 type Router struct {
 	mux            chi.Router
 	dispatcher     *webhook.Dispatcher
+	sendQueue      *service.SendQueue
+	scheduler      *service.Scheduler
+	campaigns      *service.CampaignService
 	sessionService *service.SessionService
 }
 
@@ -31,27 +37,70 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 	r.Use(chiMiddleware.RequestID)
 	r.Use(chiMiddleware.RealIP)
 	r.Use(chiMiddleware.Recoverer)
-	r.Use(chiMiddleware.Timeout(requestTimeout))
+	r.Use(middleware.Timeout(requestTimeout, uploadTimeout))
 	r.Use(middleware.Logging)
 	r.Use(corsHandler(cfg))
 
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
 	webhookRepo := repository.NewWebhookRepository(db)
+	messageRepo := repository.NewMessageRepository(db)
+	queueRepo := repository.NewQueueRepository(db)
+	scheduledRepo := repository.NewScheduledRepository(db)
+	campaignRepo := repository.NewCampaignRepository(db)
+	templateRepo := repository.NewTemplateRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	pollRepo := repository.NewPollRepository(db)
+	callRepo := repository.NewCallRepository(db)
+	autoReplyRepo := repository.NewAutoReplyRepository(db)
+	flowRepo := repository.NewFlowRepository(db)
+	assignmentRepo := repository.NewAssignmentRepository(db)
+	chatwootRepo := repository.NewChatwootRepository(db)
+	connectorRepo := repository.NewConnectorRepository(db)
 
 	authMiddleware := middleware.NewAuthMiddleware(userRepo)
 	adminMiddleware := middleware.NewAdminMiddleware(cfg.AdminToken)
 	sessionMiddleware := middleware.NewSessionMiddleware(sessionRepo)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyRepo, cfg.IdempotencyTTL)
 
 	sessionService := service.NewSessionService(userRepo, sessionRepo, cfg)
 	sessionService.SetWebhookRepo(webhookRepo)
 	dispatcher := webhook.NewDispatcher(webhookRepo, sessionRepo)
+	dispatcher.SetAlertURL(cfg.AlertWebhookURL)
 	sessionService.SetDispatcher(dispatcher)
+	sessionService.SetPollRepo(pollRepo)
+	sessionService.SetCallRepo(callRepo)
 
-	messageService := service.NewMessageService(sessionService)
+	messageService := service.NewMessageService(sessionService, cfg)
+	if cfg.MessageStore {
+		sessionService.SetMessageRepo(messageRepo, cfg.MessageRetention)
+		messageService.SetMessageRepo(messageRepo)
+	}
+	sendQueue := service.NewSendQueue(queueRepo, messageService, cfg)
+	messageService.SetSendQueue(sendQueue)
+	scheduler := service.NewScheduler(scheduledRepo, messageService)
+	templateService := service.NewTemplateService(templateRepo, messageService)
+	campaignService := service.NewCampaignService(campaignRepo, messageService, templateService, cfg)
+	pollService := service.NewPollService(pollRepo)
+	callService := service.NewCallService(callRepo)
+	autoReplyService := service.NewAutoReplyService(autoReplyRepo, messageService, templateService)
+	sessionService.AddMessageHook(autoReplyService.HandleMessage)
+	flowService := service.NewFlowService(flowRepo, messageService, sessionService)
+	sessionService.AddPauseCheck(flowService.Paused)
+	assignmentService := service.NewAssignmentService(assignmentRepo, sessionService)
+	flowService.SetAssignments(assignmentService)
+	// A closed chat is reopened for its agent before the flow runs, so a
+	// handoff on the same message finds it open instead of queueing it.
+	sessionService.AddMessageHook(service.SequentialHooks(assignmentService.HandleMessage, flowService.HandleMessage))
+	chatwootService := service.NewChatwootService(chatwootRepo, messageService, sessionService, cfg.AllowedPrivateHosts)
+	sessionService.AddMessageHook(chatwootService.HandleMessage)
+	connectorService := service.NewConnectorService(connectorRepo, messageService, sessionService, cfg.AllowedPrivateHosts)
+	sessionService.AddMessageHook(connectorService.HandleMessage)
 	userService := service.NewUserService(sessionService)
 	groupService := service.NewGroupService(sessionService)
 	newsletterService := service.NewNewsletterService(sessionService)
+	profileService := service.NewProfileService(sessionService, messageService)
+	privacyService := service.NewPrivacyService(sessionService)
 
 	healthHandler := handler.NewHealthHandler()
 	adminHandler := handler.NewAdminHandler(userRepo)
@@ -60,11 +109,25 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 	userHandler := handler.NewUserHandler(userService)
 	groupHandler := handler.NewGroupHandler(groupService)
 	newsletterHandler := handler.NewNewsletterHandler(newsletterService)
+	profileHandler := handler.NewProfileHandler(profileService)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
 	webhookHandler := handler.NewWebhookHandler(sessionRepo)
+	queueHandler := handler.NewQueueHandler(sendQueue)
+	scheduledHandler := handler.NewScheduledHandler(scheduler)
+	campaignHandler := handler.NewCampaignHandler(campaignService)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	pollHandler := handler.NewPollHandler(pollService)
+	callHandler := handler.NewCallHandler(callService)
+	autoReplyHandler := handler.NewAutoReplyHandler(autoReplyService)
+	flowHandler := handler.NewFlowHandler(flowService)
+	assignmentHandler := handler.NewAssignmentHandler(assignmentService)
+	chatwootHandler := handler.NewChatwootHandler(chatwootService)
+	connectorHandler := handler.NewConnectorHandler(connectorService)
 
 	// Public routes
 	r.Get("/health", healthHandler.GetHealth)
 	r.Mount("/swagger", httpSwagger.WrapHandler)
+	r.Post("/chatwoot/{sessionId}/webhook", chatwootHandler.Webhook)
 
 	// Admin routes
 	r.Route("/admin", func(r chi.Router) {
@@ -83,8 +146,17 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 		r.Get("/sessions", sessionHandler.ListSessions)
 		r.Post("/sessions", sessionHandler.CreateSession)
 
+		r.Route("/templates", func(r chi.Router) {
+			r.Post("/", templateHandler.Create)
+			r.Get("/", templateHandler.List)
+			r.Get("/{templateId}", templateHandler.Get)
+			r.Put("/{templateId}", templateHandler.Update)
+			r.Delete("/{templateId}", templateHandler.Delete)
+		})
+
 		r.Route("/sessions/{sessionId}", func(r chi.Router) {
 			r.Use(sessionMiddleware.ValidateSession)
+			r.Use(idempotencyMiddleware.Handle)
 			r.Get("/", sessionHandler.GetSession)
 			r.Put("/", sessionHandler.UpdateSession)
 			r.Delete("/", sessionHandler.DeleteSession)
@@ -110,17 +182,26 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 				r.Post("/list", messageHandler.SendList)
 				r.Post("/buttons", messageHandler.SendButtons)
 				r.Post("/edit", messageHandler.Edit)
+				r.Post("/template", templateHandler.Send)
 			})
 
 			r.Route("/chat", func(r chi.Router) {
 				r.Post("/presence", userHandler.ChatPresence)
 				r.Post("/markread", messageHandler.MarkRead)
 				r.Post("/archive", messageHandler.ArchiveChat)
+				r.Post("/pin", messageHandler.PinChat)
+				r.Post("/mute", messageHandler.MuteChat)
+				r.Post("/read", messageHandler.ReadChat)
+				r.Post("/delete", messageHandler.DeleteChat)
+				r.Post("/clear", messageHandler.ClearChat)
+				r.Post("/star", messageHandler.StarMessage)
 				r.Post("/downloadimage", messageHandler.DownloadImage)
 				r.Post("/downloadvideo", messageHandler.DownloadVideo)
 				r.Post("/downloadaudio", messageHandler.DownloadAudio)
 				r.Post("/downloaddocument", messageHandler.DownloadDocument)
 				r.Post("/downloadsticker", messageHandler.DownloadSticker)
+				r.Post("/media", messageHandler.StreamMedia)
+				r.Get("/media/{messageId}", messageHandler.StreamMessageMedia)
 			})
 
 			r.Route("/status", func(r chi.Router) {
@@ -129,6 +210,10 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 
 			r.Route("/call", func(r chi.Router) {
 				r.Post("/reject", userHandler.RejectCall)
+				r.Get("/policy", callHandler.GetPolicy)
+				r.Put("/policy", callHandler.SetPolicy)
+				r.Delete("/policy", callHandler.DeletePolicy)
+				r.Get("/log", callHandler.ListLog)
 			})
 
 			r.Route("/user", func(r chi.Router) {
@@ -139,6 +224,22 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 				r.Post("/presence", userHandler.SendPresence)
 				r.Get("/newsletters", userHandler.GetNewsletters)
 				r.Get("/getlid", userHandler.GetUserLID)
+				r.Get("/blocklist", userHandler.GetBlocklist)
+				r.Post("/block", userHandler.Block)
+				r.Post("/unblock", userHandler.Unblock)
+			})
+
+			r.Route("/profile", func(r chi.Router) {
+				r.Get("/", profileHandler.Get)
+				r.Post("/name", profileHandler.SetName)
+				r.Post("/about", profileHandler.SetAbout)
+				r.Post("/photo", profileHandler.SetPhoto)
+				r.Delete("/photo", profileHandler.RemovePhoto)
+			})
+
+			r.Route("/privacy", func(r chi.Router) {
+				r.Get("/", privacyHandler.Get)
+				r.Put("/", privacyHandler.Set)
 			})
 
 			r.Route("/group", func(r chi.Router) {
@@ -166,6 +267,71 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 				r.Delete("/", webhookHandler.Delete)
 			})
 
+			r.Get("/polls/{messageId}/results", pollHandler.Results)
+
+			r.Route("/queue", func(r chi.Router) {
+				r.Get("/", queueHandler.List)
+				r.Get("/{queueId}", queueHandler.Get)
+				r.Delete("/{queueId}", queueHandler.Cancel)
+			})
+
+			r.Route("/scheduled", func(r chi.Router) {
+				r.Post("/", scheduledHandler.Create)
+				r.Get("/", scheduledHandler.List)
+				r.Get("/{scheduleId}", scheduledHandler.Get)
+				r.Put("/{scheduleId}", scheduledHandler.Reschedule)
+				r.Delete("/{scheduleId}", scheduledHandler.Cancel)
+			})
+
+			r.Route("/campaigns", func(r chi.Router) {
+				r.Post("/", campaignHandler.Create)
+				r.Get("/", campaignHandler.List)
+				r.Get("/{campaignId}", campaignHandler.Get)
+				r.Post("/{campaignId}/recipients", campaignHandler.AddRecipients)
+				r.Get("/{campaignId}/recipients", campaignHandler.ListRecipients)
+				r.Post("/{campaignId}/start", campaignHandler.Start)
+				r.Post("/{campaignId}/pause", campaignHandler.Pause)
+				r.Post("/{campaignId}/resume", campaignHandler.Resume)
+				r.Post("/{campaignId}/cancel", campaignHandler.Cancel)
+			})
+
+			r.Route("/autoreplies", func(r chi.Router) {
+				r.Post("/", autoReplyHandler.Create)
+				r.Get("/", autoReplyHandler.List)
+				r.Get("/{ruleId}", autoReplyHandler.Get)
+				r.Put("/{ruleId}", autoReplyHandler.Update)
+				r.Delete("/{ruleId}", autoReplyHandler.Delete)
+			})
+
+			r.Route("/flow", func(r chi.Router) {
+				r.Get("/", flowHandler.Get)
+				r.Put("/", flowHandler.Save)
+				r.Delete("/", flowHandler.Delete)
+				r.Get("/states", flowHandler.ListStates)
+				r.Get("/states/{chat}", flowHandler.GetState)
+				r.Delete("/states/{chat}", flowHandler.ResetState)
+			})
+
+			r.Route("/assignments", func(r chi.Router) {
+				r.Get("/", assignmentHandler.List)
+				r.Get("/{chat}", assignmentHandler.Get)
+				r.Post("/{chat}/assign", assignmentHandler.Assign)
+				r.Post("/{chat}/transfer", assignmentHandler.Transfer)
+				r.Post("/{chat}/close", assignmentHandler.Close)
+			})
+
+			r.Route("/chatwoot", func(r chi.Router) {
+				r.Get("/", chatwootHandler.Get)
+				r.Put("/", chatwootHandler.Set)
+				r.Delete("/", chatwootHandler.Delete)
+			})
+
+			r.Route("/connector", func(r chi.Router) {
+				r.Get("/", connectorHandler.Get)
+				r.Put("/", connectorHandler.Set)
+				r.Delete("/", connectorHandler.Delete)
+			})
+
 			r.Route("/newsletter", func(r chi.Router) {
 				r.Get("/list", newsletterHandler.List)
 				r.Get("/info", newsletterHandler.GetInfo)
@@ -185,6 +351,9 @@ func New(cfg *config.Config, db *sqlx.DB) *Router {
 	return &Router{
 		mux:            r,
 		dispatcher:     dispatcher,
+		sendQueue:      sendQueue,
+		scheduler:      scheduler,
+		campaigns:      campaignService,
 		sessionService: sessionService,
 	}
 }
@@ -201,6 +370,30 @@ func (rt *Router) StopDispatcher() {
 	rt.dispatcher.Stop()
 }
 
+func (rt *Router) StartSendQueue() {
+	rt.sendQueue.Start()
+}
+
+func (rt *Router) StopSendQueue() {
+	rt.sendQueue.Stop()
+}
+
+func (rt *Router) StartScheduler() {
+	rt.scheduler.Start()
+}
+
+func (rt *Router) StopScheduler() {
+	rt.scheduler.Stop()
+}
+
+func (rt *Router) StartCampaigns() {
+	rt.campaigns.StartRunner()
+}
+
+func (rt *Router) StopCampaigns() {
+	rt.campaigns.StopRunner()
+}
+
 func (rt *Router) GetSessionService() *service.SessionService {
 	return rt.sessionService
 }
@@ -214,7 +407,7 @@ func corsHandler(cfg *config.Config) func(http.Handler) http.Handler {
 			}
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Token, X-Request-ID")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Token, X-Request-ID, Idempotency-Key")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+)
+
+// assignmentAttempts bounds how often a change is retried when the assignment
+// of the chat changes concurrently.
+const assignmentAttempts = 3
+
+var (
+	ErrAssignmentNotFound = errors.New("chat assignment not found")
+	ErrInvalidAssignment  = errors.New("invalid chat assignment")
+	// ErrAssignmentConflict is returned when the assignment of a chat kept
+	// changing while it was being updated.
+	ErrAssignmentConflict = errors.New("chat assignment changed concurrently, try again")
+	// ErrChatAssigned is returned when assigning a chat another agent is
+	// handling; such chats are transferred instead.
+	ErrChatAssigned = errors.New("chat is assigned to another agent, transfer it instead")
+	ErrChatClosed   = errors.New("chat is closed")
+)
+
+// AssignmentService keeps track of the agent handling each chat of a session.
+// Closed chats are opened again when the contact writes.
+type AssignmentService struct {
+	repo           *repository.AssignmentRepository
+	sessionService *SessionService
+}
+
+func NewAssignmentService(repo *repository.AssignmentRepository, sessionService *SessionService) *AssignmentService {
+	return &AssignmentService{
+		repo:           repo,
+		sessionService: sessionService,
+	}
+}
+
+func (s *AssignmentService) Get(sessionID, chat string) (*model.ChatAssignment, error) {
+	jid, err := parseJID(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.repo.Get(sessionID, jid.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat assignment: %w", err)
+	}
+	if a == nil {
+		return nil, ErrAssignmentNotFound
+	}
+	return a, nil
+}
+
+func (s *AssignmentService) List(sessionID, status, agentID string) ([]model.ChatAssignment, error) {
+	return s.repo.List(sessionID, status, agentID)
+}
+
+// Assign gives a chat that is unassigned, pending or closed to an agent.
+func (s *AssignmentService) Assign(userID, sessionID, chat string, req *model.ChatAssignRequest) (*model.ChatAssignment, error) {
+	return s.assign(userID, sessionID, chat, req, false)
+}
+
+// Transfer moves an open or pending chat to another agent.
+func (s *AssignmentService) Transfer(userID, sessionID, chat string, req *model.ChatAssignRequest) (*model.ChatAssignment, error) {
+	return s.assign(userID, sessionID, chat, req, true)
+}
+
+func (s *AssignmentService) assign(userID, sessionID, chat string, req *model.ChatAssignRequest, transfer bool) (*model.ChatAssignment, error) {
+	if req.AgentID == "" {
+		return nil, fmt.Errorf("%w: agent_id is required", ErrInvalidAssignment)
+	}
+	switch req.Status {
+	case "":
+		req.Status = model.ChatStatusOpen
+	case model.ChatStatusOpen, model.ChatStatusPending:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidAssignment, req.Status)
+	}
+
+	jid, err := parseJID(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	// The assignment is only written if it is still the one the checks ran
+	// on; otherwise they run again on the current one.
+	for attempt := 0; attempt < assignmentAttempts; attempt++ {
+		a, err := s.repo.Get(sessionID, jid.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chat assignment: %w", err)
+		}
+
+		switch {
+		case transfer && a == nil:
+			return nil, ErrAssignmentNotFound
+		case transfer && a.Status == model.ChatStatusClosed:
+			return nil, ErrChatClosed
+		case !transfer && a != nil && a.Status != model.ChatStatusClosed && a.AgentID != "" && a.AgentID != req.AgentID:
+			return nil, ErrChatAssigned
+		}
+
+		next := model.ChatAssignment{SessionID: sessionID, Chat: jid.String()}
+		previous := ""
+		if a != nil {
+			next = *a
+			previous = a.AgentID
+		}
+
+		now := time.Now()
+		next.AgentID = req.AgentID
+		next.Status = req.Status
+		next.AssignedAt = &now
+		next.ClosedAt = nil
+		if req.Notes != nil {
+			next.Notes = *req.Notes
+		}
+
+		var saved *model.ChatAssignment
+		if a == nil {
+			saved, err = s.repo.Insert(&next)
+		} else {
+			saved, err = s.repo.Update(&next, a.AgentID, a.Status)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save chat assignment: %w", err)
+		}
+		if saved == nil {
+			continue
+		}
+
+		s.sessionService.handleEvent(userID, sessionID, "ChatAssigned", map[string]interface{}{
+			"chat":            saved.Chat,
+			"agentId":         saved.AgentID,
+			"previousAgentId": previous,
+			"status":          saved.Status,
+			"notes":           saved.Notes,
+			"transfer":        transfer,
+		})
+
+		return saved, nil
+	}
+
+	return nil, ErrAssignmentConflict
+}
+
+func (s *AssignmentService) Close(userID, sessionID, chat string, req *model.ChatCloseRequest) (*model.ChatAssignment, error) {
+	for attempt := 0; attempt < assignmentAttempts; attempt++ {
+		a, err := s.Get(sessionID, chat)
+		if err != nil {
+			return nil, err
+		}
+
+		next := *a
+		now := time.Now()
+		next.Status = model.ChatStatusClosed
+		next.ClosedAt = &now
+		if req.Notes != nil {
+			next.Notes = *req.Notes
+		}
+
+		saved, err := s.repo.Update(&next, a.AgentID, a.Status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save chat assignment: %w", err)
+		}
+		if saved == nil {
+			continue
+		}
+
+		s.sessionService.handleEvent(userID, sessionID, "ChatClosed", map[string]interface{}{
+			"chat":    saved.Chat,
+			"agentId": saved.AgentID,
+			"notes":   saved.Notes,
+		})
+
+		return saved, nil
+	}
+
+	return nil, ErrAssignmentConflict
+}
+
+// HandleMessage reopens a closed chat when the contact writes in it.
+func (s *AssignmentService) HandleMessage(userID, sessionID string, evt *events.Message) {
+	info := evt.Info
+	if info.IsFromMe || info.Chat.Server == types.BroadcastServer || info.Chat.Server == types.NewsletterServer {
+		return
+	}
+
+	a, err := s.repo.Reopen(sessionID, info.Chat.String())
+	if err != nil {
+		logger.WarnComponent("assignment").Str("session_id", sessionID).Err(err).Msg("failed to reopen chat")
+		return
+	}
+	if a == nil {
+		return
+	}
+
+	s.sessionService.handleEvent(userID, sessionID, "ChatReopened", map[string]interface{}{
+		"chat":      a.Chat,
+		"agentId":   a.AgentID,
+		"from":      info.Sender.String(),
+		"timestamp": info.Timestamp.Unix(),
+	})
+}
+
+// queue marks a chat as waiting for an agent, e.g. after a flow hands it off.
+func (s *AssignmentService) queue(sessionID, chat string) {
+	if err := s.repo.MarkPending(sessionID, chat); err != nil {
+		logger.WarnComponent("assignment").Str("session_id", sessionID).Err(err).Msg("failed to queue chat")
+	}
+}
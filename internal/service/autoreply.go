@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+	"fiozap/internal/wameow"
+)
+
+const (
+	autoReplyDefaultCooldown = 60
+	autoReplySendTimeout     = 60 * time.Second
+	// answerMaxAge keeps messages delivered late, e.g. after a reconnect,
+	// from being answered automatically.
+	answerMaxAge = 5 * time.Minute
+)
+
+var ErrAutoReplyNotFound = errors.New("auto-reply not found")
+
+// AutoReplyService manages the auto-reply rules of sessions and answers
+// incoming messages that match them.
+type AutoReplyService struct {
+	repo            *repository.AutoReplyRepository
+	messageService  *MessageService
+	templateService *TemplateService
+	regexps         ruleRegexps
+}
+
+func NewAutoReplyService(repo *repository.AutoReplyRepository, messageService *MessageService, templateService *TemplateService) *AutoReplyService {
+	return &AutoReplyService{
+		repo:            repo,
+		messageService:  messageService,
+		templateService: templateService,
+		regexps:         ruleRegexps{byRule: make(map[string]compiledRule)},
+	}
+}
+
+func (s *AutoReplyService) Create(userID, sessionID string, req *model.AutoReplyRequest) (*model.AutoReply, error) {
+	if err := s.validate(userID, req); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.Create(sessionID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auto-reply: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *AutoReplyService) List(sessionID string) ([]model.AutoReply, error) {
+	return s.repo.ListBySession(sessionID)
+}
+
+func (s *AutoReplyService) Get(sessionID, id string) (*model.AutoReply, error) {
+	rule, err := s.repo.GetByID(sessionID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAutoReplyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auto-reply: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *AutoReplyService) Update(userID, sessionID, id string, req *model.AutoReplyRequest) (*model.AutoReply, error) {
+	if err := s.validate(userID, req); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.Update(sessionID, id, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update auto-reply: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrAutoReplyNotFound
+	}
+	return rule, nil
+}
+
+func (s *AutoReplyService) Delete(sessionID, id string) error {
+	deleted, err := s.repo.Delete(sessionID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete auto-reply: %w", err)
+	}
+	if !deleted {
+		return ErrAutoReplyNotFound
+	}
+	s.regexps.forget(id)
+	return nil
+}
+
+// validate checks a rule and fills in its defaults.
+func (s *AutoReplyService) validate(userID string, req *model.AutoReplyRequest) error {
+	if req.Name == "" {
+		return errors.New("name is required")
+	}
+	if req.Pattern == "" {
+		return errors.New("pattern is required")
+	}
+
+	switch req.MatchType {
+	case model.AutoReplyMatchExact, model.AutoReplyMatchContains:
+	case model.AutoReplyMatchRegex:
+		if _, err := compileRule(req.Pattern, req.CaseSensitive); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid match_type %q", req.MatchType)
+	}
+
+	switch req.ChatType {
+	case "":
+		req.ChatType = model.AutoReplyChatAny
+	case model.AutoReplyChatAny, model.AutoReplyChatDirect, model.AutoReplyChatGroup:
+	default:
+		return fmt.Errorf("invalid chat_type %q", req.ChatType)
+	}
+
+	if err := validateWindow(req.Window); err != nil {
+		return err
+	}
+
+	if req.Kind == templateKind {
+		if req.TemplateID == "" {
+			return errors.New("template_id is required for kind template")
+		}
+		if _, err := s.templateService.Get(userID, req.TemplateID); err != nil {
+			return err
+		}
+		req.Payload = []byte("{}")
+	} else {
+		req.TemplateID = ""
+		if len(req.Payload) == 0 {
+			return errors.New("payload is required")
+		}
+		payload, err := renderPayload(req.Payload, "autoreply", nil)
+		if err != nil {
+			return err
+		}
+		if err := validateSendPayload(req.Kind, payload); err != nil {
+			return err
+		}
+	}
+
+	if req.Enabled == nil {
+		enabled := true
+		req.Enabled = &enabled
+	}
+	if req.CooldownSeconds <= 0 {
+		req.CooldownSeconds = autoReplyDefaultCooldown
+	}
+	if req.Allow == nil {
+		req.Allow = []string{}
+	}
+	if req.Deny == nil {
+		req.Deny = []string{}
+	}
+	return nil
+}
+
+// HandleMessage answers an incoming message with the first enabled rule of
+// the session that matches it. A matching rule that is cooling down for the
+// chat keeps the message unanswered.
+func (s *AutoReplyService) HandleMessage(userID, sessionID string, evt *events.Message) {
+	if !answerable(evt) {
+		return
+	}
+
+	info := evt.Info
+	text := wameow.MessageText(evt)
+	if text == "" {
+		return
+	}
+	if s.messageService.sessionService.BotPaused(sessionID, info.Chat.String()) {
+		return
+	}
+
+	rules, err := s.repo.ListEnabled(sessionID)
+	if err != nil {
+		logger.WarnComponent("autoreply").Str("session_id", sessionID).Err(err).Msg("failed to get rules")
+		return
+	}
+
+	now := time.Now()
+	for i := range rules {
+		rule := &rules[i]
+		if !ruleMatches(rule, evt, text, now, s.regexps.get(rule)) {
+			continue
+		}
+
+		claimed, err := s.repo.ClaimCooldown(rule.ID, info.Chat.String(), rule.CooldownSeconds)
+		if err != nil {
+			logger.WarnComponent("autoreply").Str("rule_id", rule.ID).Err(err).Msg("failed to claim cooldown")
+			return
+		}
+		if !claimed {
+			return
+		}
+
+		if err := s.reply(userID, sessionID, rule, evt, text); err != nil {
+			logger.WarnComponent("autoreply").Str("rule_id", rule.ID).Str("chat", info.Chat.String()).Err(err).Msg("reply failed")
+			if err := s.repo.ReleaseCooldown(rule.ID, info.Chat.String()); err != nil {
+				logger.WarnComponent("autoreply").Str("rule_id", rule.ID).Err(err).Msg("failed to release cooldown")
+			}
+			return
+		}
+		logger.Component("autoreply").Str("rule_id", rule.ID).Str("chat", info.Chat.String()).Msg("replied")
+		return
+	}
+}
+
+func (s *AutoReplyService) reply(userID, sessionID string, rule *model.AutoReply, evt *events.Message, text string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), autoReplySendTimeout)
+	defer cancel()
+
+	chat := evt.Info.Chat.String()
+	vars := messageVars(evt, text)
+
+	var opts model.SendOptions
+	if rule.Quote {
+		opts.ReplyTo = &model.ReplyTo{
+			MessageID:   evt.Info.ID,
+			Participant: evt.Info.Sender.ToNonAD().String(),
+			Text:        text,
+		}
+	}
+
+	if rule.Kind == templateKind {
+		_, err := s.templateService.Send(ctx, userID, sessionID, &model.TemplateSendRequest{
+			TemplateID:  rule.TemplateID,
+			Phone:       chat,
+			Variables:   vars,
+			SendOptions: opts,
+		})
+		return err
+	}
+
+	payload, err := renderPayload(rule.Payload, chat, vars)
+	if err != nil {
+		return err
+	}
+	if payload, err = withSendOptions(payload, &opts); err != nil {
+		return err
+	}
+
+	_, err = s.messageService.Send(ctx, userID, sessionID, rule.Kind, payload)
+	return err
+}
+
+// ruleMatches reports whether a rule answers a message. re is the compiled
+// pattern of regex rules.
+func ruleMatches(rule *model.AutoReply, evt *events.Message, text string, now time.Time, re *regexp.Regexp) bool {
+	switch rule.ChatType {
+	case model.AutoReplyChatDirect:
+		if evt.Info.IsGroup {
+			return false
+		}
+	case model.AutoReplyChatGroup:
+		if !evt.Info.IsGroup {
+			return false
+		}
+	}
+
+	if len(rule.Allow) > 0 && !senderListed(rule.Allow, evt) {
+		return false
+	}
+	if senderListed(rule.Deny, evt) {
+		return false
+	}
+	if rule.Window != nil && !windowContains(rule.Window, now) {
+		return false
+	}
+
+	return textMatches(rule, text, re)
+}
+
+func textMatches(rule *model.AutoReply, text string, re *regexp.Regexp) bool {
+	text = strings.TrimSpace(text)
+	pattern := rule.Pattern
+	if !rule.CaseSensitive && rule.MatchType != model.AutoReplyMatchRegex {
+		text = strings.ToLower(text)
+		pattern = strings.ToLower(pattern)
+	}
+
+	switch rule.MatchType {
+	case model.AutoReplyMatchExact:
+		return text == pattern
+	case model.AutoReplyMatchContains:
+		return strings.Contains(text, pattern)
+	case model.AutoReplyMatchRegex:
+		return re != nil && re.MatchString(text)
+	}
+	return false
+}
+
+func compileRule(pattern string, caseSensitive bool) (*regexp.Regexp, error) {
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// ruleRegexps caches the compiled patterns of regex rules by rule id, so
+// incoming messages are not matched by compiling every pattern again. An
+// entry is replaced when the pattern of its rule changes.
+type ruleRegexps struct {
+	mu     sync.Mutex
+	byRule map[string]compiledRule
+}
+
+type compiledRule struct {
+	pattern       string
+	caseSensitive bool
+	re            *regexp.Regexp
+}
+
+// get returns the compiled pattern of a regex rule, or nil for other rules
+// and patterns that do not compile.
+func (c *ruleRegexps) get(rule *model.AutoReply) *regexp.Regexp {
+	if rule.MatchType != model.AutoReplyMatchRegex {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.byRule[rule.ID]; ok && cached.pattern == rule.Pattern && cached.caseSensitive == rule.CaseSensitive {
+		return cached.re
+	}
+
+	re, err := compileRule(rule.Pattern, rule.CaseSensitive)
+	if err != nil {
+		re = nil
+	}
+	c.byRule[rule.ID] = compiledRule{pattern: rule.Pattern, caseSensitive: rule.CaseSensitive, re: re}
+	return re
+}
+
+func (c *ruleRegexps) forget(ruleID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.byRule, ruleID)
+}
+
+// answerable reports whether an incoming message may be answered
+// automatically. Own messages, status and channel posts, changes of other
+// messages and messages delivered late are not.
+func answerable(evt *events.Message) bool {
+	info := evt.Info
+	if info.IsFromMe || info.Chat.Server == types.BroadcastServer || info.Chat.Server == types.NewsletterServer {
+		return false
+	}
+	return time.Since(info.Timestamp) <= answerMaxAge && wameow.ParseMessageChange(evt) == nil
+}
+
+// messageVars returns the placeholder values of automatic answers to a
+// message.
+func messageVars(evt *events.Message, text string) map[string]string {
+	return map[string]string{
+		"phone": senderPhone(evt),
+		"name":  evt.Info.PushName,
+		"chat":  evt.Info.Chat.String(),
+		"text":  text,
+	}
+}
+
+// senderListed reports whether the sender of a message, by phone number or
+// JID, is in list.
+func senderListed(list []string, evt *events.Message) bool {
+	for _, entry := range list {
+		entry = strings.TrimPrefix(strings.TrimSpace(entry), "+")
+		for _, jid := range []types.JID{evt.Info.Sender, evt.Info.SenderAlt} {
+			if jid.IsEmpty() {
+				continue
+			}
+			if entry == jid.User || entry == jid.ToNonAD().String() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// senderPhone returns the phone number of the sender of a message, which is
+// empty when only its LID is known.
+func senderPhone(evt *events.Message) string {
+	for _, jid := range []types.JID{evt.Info.Sender, evt.Info.SenderAlt} {
+		if jid.Server == types.DefaultUserServer {
+			return jid.User
+		}
+	}
+	return ""
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+)
+
+const (
+	callRejectTimeout = 30 * time.Second
+	callLogLimit      = 100
+)
+
+var ErrCallPolicyNotFound = errors.New("call policy not found")
+
+// CallService manages the call policies of sessions. The policies are applied
+// by SessionService when a call offer arrives.
+type CallService struct {
+	repo *repository.CallRepository
+}
+
+func NewCallService(repo *repository.CallRepository) *CallService {
+	return &CallService{repo: repo}
+}
+
+func (s *CallService) GetPolicy(sessionID string) (*model.CallPolicy, error) {
+	policy, err := s.repo.GetPolicy(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call policy: %w", err)
+	}
+	if policy == nil {
+		return nil, ErrCallPolicyNotFound
+	}
+	return policy, nil
+}
+
+func (s *CallService) SetPolicy(sessionID string, req *model.CallPolicyRequest) (*model.CallPolicy, error) {
+	switch req.Mode {
+	case model.CallPolicyAlways, model.CallPolicyNonContacts:
+	case model.CallPolicyHours:
+		if req.Window == nil {
+			return nil, errors.New("window is required for mode hours")
+		}
+	default:
+		return nil, fmt.Errorf("invalid mode %q", req.Mode)
+	}
+	if err := validateWindow(req.Window); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.repo.SavePolicy(sessionID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save call policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (s *CallService) DeletePolicy(sessionID string) error {
+	deleted, err := s.repo.DeletePolicy(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete call policy: %w", err)
+	}
+	if !deleted {
+		return ErrCallPolicyNotFound
+	}
+	return nil
+}
+
+// ListLog returns the latest calls rejected by the policy of a session.
+func (s *CallService) ListLog(sessionID string) ([]model.CallLog, error) {
+	return s.repo.ListLogs(sessionID, callLogLimit)
+}
+
+// handleCallOffer rejects an incoming call when the call policy of the
+// session applies to it, sends the policy message to the caller, and logs
+// and emits the call as CallRejected.
+func (s *SessionService) handleCallOffer(userID, sessionID string, client *whatsmeow.Client, evt *events.CallOffer) {
+	policy, err := s.callRepo.GetPolicy(sessionID)
+	if err != nil {
+		logger.Warnf("Failed to get call policy: %v", err)
+		return
+	}
+	if policy == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), callRejectTimeout)
+	defer cancel()
+
+	if !callPolicyApplies(ctx, client, policy, evt) {
+		return
+	}
+
+	caller := evt.CallCreator.ToNonAD()
+	entry := &model.CallLog{
+		SessionID: sessionID,
+		CallID:    evt.CallID,
+		Caller:    caller.String(),
+		Mode:      policy.Mode,
+	}
+
+	if err := client.RejectCall(ctx, evt.CallCreator, evt.CallID); err != nil {
+		entry.Error = fmt.Sprintf("failed to reject call: %v", err)
+	} else if policy.Message != "" {
+		msg := &waE2E.Message{Conversation: proto.String(policy.Message)}
+		if _, err := client.SendMessage(ctx, callReplyJID(evt), msg); err != nil {
+			entry.Error = fmt.Sprintf("failed to send message: %v", err)
+		} else {
+			entry.MessageSent = true
+		}
+	}
+
+	logger.Component("call").Str("session_id", sessionID).Str("call_id", evt.CallID).
+		Str("caller", entry.Caller).Str("mode", policy.Mode).Msg("call rejected")
+	if entry.Error != "" {
+		logger.WarnComponent("call").Str("session_id", sessionID).Str("call_id", evt.CallID).Msg(entry.Error)
+	}
+
+	if err := s.callRepo.AddLog(entry); err != nil {
+		logger.Warnf("Failed to log rejected call %s: %v", evt.CallID, err)
+	}
+
+	s.handleEvent(userID, sessionID, "CallRejected", map[string]interface{}{
+		"from":        entry.Caller,
+		"callId":      evt.CallID,
+		"mode":        policy.Mode,
+		"messageSent": entry.MessageSent,
+		"error":       entry.Error,
+		"timestamp":   evt.Timestamp.Unix(),
+	})
+}
+
+func callPolicyApplies(ctx context.Context, client *whatsmeow.Client, policy *model.CallPolicy, evt *events.CallOffer) bool {
+	switch policy.Mode {
+	case model.CallPolicyAlways:
+		return true
+	case model.CallPolicyHours:
+		return windowContains(policy.Window, time.Now())
+	case model.CallPolicyNonContacts:
+		return !isContact(ctx, client, evt.CallCreator, evt.CallCreatorAlt)
+	}
+	return false
+}
+
+// isContact reports whether any of the JIDs of a caller, which may be a
+// phone number or a LID, is in the address book of the session.
+func isContact(ctx context.Context, client *whatsmeow.Client, jids ...types.JID) bool {
+	for _, jid := range jids {
+		if jid.IsEmpty() {
+			continue
+		}
+		if info, err := client.Store.Contacts.GetContact(ctx, jid.ToNonAD()); err == nil && info.Found {
+			return true
+		}
+	}
+	return false
+}
+
+// callReplyJID prefers the phone number of the caller for the reply.
+func callReplyJID(evt *events.CallOffer) types.JID {
+	if evt.CallCreatorAlt.Server == types.DefaultUserServer {
+		return evt.CallCreatorAlt.ToNonAD()
+	}
+	return evt.CallCreator.ToNonAD()
+}
@@ -0,0 +1,565 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"fiozap/internal/config"
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+)
+
+const (
+	campaignPollInterval  = 2 * time.Second
+	campaignIdleWait      = 30 * time.Second
+	campaignLeaseTTL      = 5 * time.Minute
+	campaignStaleAfter    = 10 * time.Minute
+	campaignProgressEvery = 50
+	campaignMaxJitter     = 60
+	campaignListLimit     = 500
+	campaignComponent     = "campaign"
+)
+
+var ErrInvalidCampaign = errors.New("invalid campaign")
+
+// campaignStore is the CampaignRepository as used by the campaign service.
+type campaignStore interface {
+	Create(userID, sessionID string, req *model.CampaignCreateRequest, recipients []model.CampaignRecipientInput) (*model.Campaign, error)
+	GetBySession(sessionID, id string) (*model.Campaign, error)
+	ListBySession(sessionID string) ([]model.Campaign, error)
+	ListRunning() ([]model.Campaign, error)
+	UpdateStatus(sessionID, id, status string, from ...string) (bool, error)
+	Complete(id string) (bool, error)
+	AcquireLease(id, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(id, owner string) error
+	Progress(id string) (*model.CampaignProgress, error)
+	AddRecipients(campaignID string, recipients []model.CampaignRecipientInput) (int, error)
+	ListRecipients(campaignID, status string, limit, offset int) ([]model.CampaignRecipient, error)
+	ClaimRecipient(campaignID string) (*model.CampaignRecipient, error)
+	UpdateRecipient(campaignID, phone, status, reason, messageID string) error
+	ReleaseRecipient(campaignID, phone string) error
+	FailStaleRecipients(campaignID string, olderThan time.Duration) error
+}
+
+// CampaignService manages broadcast campaigns and runs the running ones.
+// A campaign is run by one instance at a time, which holds a lease on it in
+// the database and renews it between sends.
+type CampaignService struct {
+	repo            campaignStore
+	messageService  *MessageService
+	templateService *TemplateService
+	defaultRate     int
+	owner           string
+
+	// sendFn, online, reserve and handleEvent default to the session and
+	// its send queue.
+	sendFn      func(campaign *model.Campaign, recipient *model.CampaignRecipient) bool
+	online      func(campaign *model.Campaign) bool
+	reserve     func(sessionID string) time.Duration
+	handleEvent func(userID, sessionID, eventType string, data interface{})
+
+	mu     sync.Mutex
+	active map[string]bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewCampaignService(repo *repository.CampaignRepository, messageService *MessageService, templateService *TemplateService, cfg *config.Config) *CampaignService {
+	s := &CampaignService{
+		repo:            repo,
+		messageService:  messageService,
+		templateService: templateService,
+		defaultRate:     cfg.QueueRate,
+		owner:           instanceID(),
+		handleEvent:     messageService.sessionService.handleEvent,
+		active:          make(map[string]bool),
+		stopCh:          make(chan struct{}),
+	}
+	s.sendFn = s.send
+	s.online = s.connected
+	s.reserve = s.reserveSend
+	return s
+}
+
+func (s *CampaignService) StartRunner() {
+	s.wg.Add(1)
+	go s.processLoop()
+	logger.Component(campaignComponent).Str("status", "running").Msg("campaign runner started")
+}
+
+func (s *CampaignService) StopRunner() {
+	close(s.stopCh)
+	s.wg.Wait()
+	logger.Component(campaignComponent).Str("status", "stopped").Msg("campaign runner stopped")
+}
+
+func (s *CampaignService) Create(userID, sessionID string, req *model.CampaignCreateRequest) (*model.Campaign, error) {
+	if err := s.validate(userID, req); err != nil {
+		return nil, err
+	}
+
+	recipients, rejected := normalizeRecipients(req.Recipients)
+
+	campaign, err := s.repo.Create(userID, sessionID, req, recipients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	campaign, err = s.Get(sessionID, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	campaign.Rejected = rejected
+
+	return campaign, nil
+}
+
+func (s *CampaignService) validate(userID string, req *model.CampaignCreateRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCampaign)
+	}
+
+	if req.Kind == templateKind {
+		if req.TemplateID == "" {
+			return fmt.Errorf("%w: template_id is required for kind template", ErrInvalidCampaign)
+		}
+		if _, err := s.templateService.Get(userID, req.TemplateID); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+		}
+		req.Payload = []byte("{}")
+	} else {
+		req.TemplateID = ""
+		// The phone is set per recipient, so validate the payload as it will be sent.
+		payload, err := renderPayload(req.Payload, "campaign", nil)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+		}
+		if err := validateSendPayload(req.Kind, payload); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+		}
+	}
+
+	if err := validateWindow(req.Window); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+	}
+
+	if req.RatePerMinute == 0 {
+		req.RatePerMinute = s.defaultRate
+	}
+	if req.RatePerMinute < 0 {
+		return fmt.Errorf("%w: rate_per_minute must be positive", ErrInvalidCampaign)
+	}
+	if req.JitterSeconds < 0 || req.JitterSeconds > campaignMaxJitter {
+		return fmt.Errorf("%w: jitter_seconds must be between 0 and %d", ErrInvalidCampaign, campaignMaxJitter)
+	}
+
+	return nil
+}
+
+func (s *CampaignService) List(sessionID string) ([]model.Campaign, error) {
+	return s.repo.ListBySession(sessionID)
+}
+
+func (s *CampaignService) Get(sessionID, id string) (*model.Campaign, error) {
+	campaign, err := s.repo.GetBySession(sessionID, id)
+	if err != nil {
+		return nil, fmt.Errorf("campaign not found: %w", err)
+	}
+
+	campaign.Progress, err = s.repo.Progress(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// AddRecipients adds recipients to a campaign that has not finished yet.
+// Numbers already in the campaign and malformed numbers are skipped.
+func (s *CampaignService) AddRecipients(sessionID, id string, recipients []model.CampaignRecipientInput) (map[string]interface{}, error) {
+	campaign, err := s.repo.GetBySession(sessionID, id)
+	if err != nil {
+		return nil, fmt.Errorf("campaign not found: %w", err)
+	}
+	if campaign.Status == model.CampaignStatusCanceled || campaign.Status == model.CampaignStatusCompleted {
+		return nil, fmt.Errorf("campaign is %s", campaign.Status)
+	}
+
+	valid, rejected := normalizeRecipients(recipients)
+
+	added, err := s.repo.AddRecipients(id, valid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add recipients: %w", err)
+	}
+
+	return map[string]interface{}{
+		"added":      added,
+		"duplicates": len(valid) - added,
+		"rejected":   rejected,
+	}, nil
+}
+
+// AddRecipientsCSV adds recipients from a CSV file with a header row. The
+// phone column is required; every other column becomes a variable.
+func (s *CampaignService) AddRecipientsCSV(sessionID, id string, r io.Reader) (map[string]interface{}, error) {
+	recipients, err := parseRecipientsCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.AddRecipients(sessionID, id, recipients)
+}
+
+func (s *CampaignService) ListRecipients(sessionID, id, status string, offset int) ([]model.CampaignRecipient, error) {
+	if _, err := s.repo.GetBySession(sessionID, id); err != nil {
+		return nil, fmt.Errorf("campaign not found: %w", err)
+	}
+	return s.repo.ListRecipients(id, status, campaignListLimit, offset)
+}
+
+func (s *CampaignService) Start(sessionID, id string) (*model.Campaign, error) {
+	campaign, err := s.Get(sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Progress.Total == 0 {
+		return nil, errors.New("campaign has no recipients")
+	}
+
+	return s.transition(sessionID, id, model.CampaignStatusRunning, model.CampaignStatusDraft)
+}
+
+func (s *CampaignService) Pause(sessionID, id string) (*model.Campaign, error) {
+	return s.transition(sessionID, id, model.CampaignStatusPaused, model.CampaignStatusRunning)
+}
+
+func (s *CampaignService) Resume(sessionID, id string) (*model.Campaign, error) {
+	return s.transition(sessionID, id, model.CampaignStatusRunning, model.CampaignStatusPaused)
+}
+
+func (s *CampaignService) Cancel(sessionID, id string) (*model.Campaign, error) {
+	return s.transition(sessionID, id, model.CampaignStatusCanceled,
+		model.CampaignStatusDraft, model.CampaignStatusRunning, model.CampaignStatusPaused)
+}
+
+func (s *CampaignService) transition(sessionID, id, status string, from ...string) (*model.Campaign, error) {
+	updated, err := s.repo.UpdateStatus(sessionID, id, status, from...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update campaign: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("campaign not found or not %s", strings.Join(from, " or "))
+	}
+	return s.Get(sessionID, id)
+}
+
+func (s *CampaignService) processLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(campaignPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.processRunning()
+		}
+	}
+}
+
+func (s *CampaignService) processRunning() {
+	campaigns, err := s.repo.ListRunning()
+	if err != nil {
+		logger.WithError(err).Str("component", campaignComponent).Msg("failed to list running campaigns")
+		return
+	}
+
+	for i := range campaigns {
+		campaign := campaigns[i]
+
+		s.mu.Lock()
+		if s.active[campaign.ID] {
+			s.mu.Unlock()
+			continue
+		}
+		s.active[campaign.ID] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.run(&campaign)
+	}
+}
+
+// run sends a campaign until it is finished, paused or canceled, or its lease
+// is taken by another instance.
+func (s *CampaignService) run(campaign *model.Campaign) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, campaign.ID)
+		s.mu.Unlock()
+	}()
+	defer func() { _ = s.repo.ReleaseLease(campaign.ID, s.owner) }()
+
+	if err := s.repo.FailStaleRecipients(campaign.ID, campaignStaleAfter); err != nil {
+		logger.WarnComponent(campaignComponent).Str("id", campaign.ID).Err(err).Msg("failed to expire stale recipients")
+	}
+
+	processed := 0
+	for {
+		leased, err := s.repo.AcquireLease(campaign.ID, s.owner, campaignLeaseTTL)
+		if err != nil {
+			logger.WarnComponent(campaignComponent).Str("id", campaign.ID).Err(err).Msg("failed to renew lease")
+			return
+		}
+		if !leased {
+			return
+		}
+
+		if !windowContains(campaign.Window, time.Now()) || !s.online(campaign) {
+			if !s.sleep(campaignIdleWait) {
+				return
+			}
+			continue
+		}
+
+		if !s.sleep(s.reserve(campaign.SessionID)) {
+			return
+		}
+
+		recipient, err := s.repo.ClaimRecipient(campaign.ID)
+		if err != nil {
+			logger.WarnComponent(campaignComponent).Str("id", campaign.ID).Err(err).Msg("failed to claim recipient")
+			return
+		}
+		if recipient == nil {
+			s.complete(campaign)
+			return
+		}
+
+		if !s.sendFn(campaign, recipient) {
+			if !s.sleep(campaignIdleWait) {
+				return
+			}
+			continue
+		}
+
+		processed++
+		if processed%campaignProgressEvery == 0 {
+			s.emit(campaign, "CampaignProgress")
+		}
+
+		wait := time.Minute / time.Duration(campaign.RatePerMinute)
+		if campaign.JitterSeconds > 0 {
+			wait += mathrand.N(time.Duration(campaign.JitterSeconds) * time.Second)
+		}
+		if !s.sleep(wait) {
+			return
+		}
+	}
+}
+
+// reserveSend reserves a send on the session's send queue, so a campaign and
+// queued messages together stay within its rate limit.
+func (s *CampaignService) reserveSend(sessionID string) time.Duration {
+	if queue := s.messageService.sendQueue; queue != nil {
+		return queue.Reserve(sessionID)
+	}
+	return 0
+}
+
+func (s *CampaignService) connected(campaign *model.Campaign) bool {
+	client := s.messageService.sessionService.GetWhatsmeowClient(campaign.UserID, campaign.SessionID)
+	return client != nil && client.IsConnected()
+}
+
+// send checks a recipient with IsOnWhatsApp and sends it the rendered
+// message. It reports false when the recipient was put back because the check
+// could not be made.
+func (s *CampaignService) send(campaign *model.Campaign, recipient *model.CampaignRecipient) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), queueSendTimeout)
+	defer cancel()
+
+	client := s.messageService.sessionService.GetWhatsmeowClient(campaign.UserID, campaign.SessionID)
+	if client == nil {
+		_ = s.repo.ReleaseRecipient(campaign.ID, recipient.Phone)
+		return false
+	}
+
+	resp, err := client.IsOnWhatsApp(ctx, []string{"+" + recipient.Phone})
+	if err != nil {
+		logger.WarnComponent(campaignComponent).Str("id", campaign.ID).Err(err).Msg("failed to check recipient")
+		_ = s.repo.ReleaseRecipient(campaign.ID, recipient.Phone)
+		return false
+	}
+	if len(resp) == 0 || !resp[0].IsIn {
+		_ = s.repo.UpdateRecipient(campaign.ID, recipient.Phone, model.RecipientStatusInvalid, "not on WhatsApp", "")
+		return true
+	}
+
+	vars := map[string]string{}
+	_ = json.Unmarshal(recipient.Variables, &vars)
+	vars["phone"] = recipient.Phone
+
+	result, err := s.sendRendered(ctx, campaign, resp[0].JID.String(), vars)
+	if err == nil {
+		messageID, _ := result["id"].(string)
+		_ = s.repo.UpdateRecipient(campaign.ID, recipient.Phone, model.RecipientStatusSent, "", messageID)
+		return true
+	}
+
+	logger.WarnComponent(campaignComponent).Str("id", campaign.ID).Err(err).Msg("send failed")
+	_ = s.repo.UpdateRecipient(campaign.ID, recipient.Phone, model.RecipientStatusFailed, err.Error(), "")
+	return true
+}
+
+// sendRendered sends the campaign message, or its template, rendered with the
+// variables of one recipient.
+func (s *CampaignService) sendRendered(ctx context.Context, campaign *model.Campaign, chat string, vars map[string]string) (map[string]interface{}, error) {
+	if campaign.Kind == templateKind {
+		return s.templateService.Send(ctx, campaign.UserID, campaign.SessionID, &model.TemplateSendRequest{
+			TemplateID: campaign.TemplateID,
+			Phone:      chat,
+			Variables:  vars,
+		})
+	}
+
+	payload, err := renderPayload(campaign.Payload, chat, vars)
+	if err != nil {
+		return nil, err
+	}
+	return s.messageService.Send(ctx, campaign.UserID, campaign.SessionID, campaign.Kind, payload)
+}
+
+func (s *CampaignService) complete(campaign *model.Campaign) {
+	completed, err := s.repo.Complete(campaign.ID)
+	if err != nil {
+		logger.WarnComponent(campaignComponent).Str("id", campaign.ID).Err(err).Msg("failed to complete campaign")
+		return
+	}
+	if completed {
+		s.emit(campaign, "CampaignCompleted")
+	}
+}
+
+func (s *CampaignService) emit(campaign *model.Campaign, eventType string) {
+	progress, err := s.repo.Progress(campaign.ID)
+	if err != nil {
+		logger.WarnComponent(campaignComponent).Str("id", campaign.ID).Err(err).Msg("failed to get progress")
+		return
+	}
+
+	s.handleEvent(campaign.UserID, campaign.SessionID, eventType, map[string]interface{}{
+		"campaign_id": campaign.ID,
+		"name":        campaign.Name,
+		"progress":    progress,
+	})
+}
+
+// sleep waits for d and reports false if the runner was stopped meanwhile.
+func (s *CampaignService) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-s.stopCh:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// normalizeRecipients keeps the digits of each phone and drops numbers that
+// cannot be valid, returning them separately.
+func normalizeRecipients(recipients []model.CampaignRecipientInput) ([]model.CampaignRecipientInput, []string) {
+	valid := make([]model.CampaignRecipientInput, 0, len(recipients))
+	var rejected []string
+
+	for _, rec := range recipients {
+		phone := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, rec.Phone)
+
+		if len(phone) < 8 || len(phone) > 15 {
+			rejected = append(rejected, rec.Phone)
+			continue
+		}
+
+		rec.Phone = phone
+		valid = append(valid, rec)
+	}
+
+	return valid, rejected
+}
+
+func parseRecipientsCSV(r io.Reader) ([]model.CampaignRecipientInput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	phoneCol := -1
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if strings.EqualFold(header[i], "phone") {
+			phoneCol = i
+		}
+	}
+	if phoneCol < 0 {
+		return nil, errors.New("CSV header must have a phone column")
+	}
+
+	var recipients []model.CampaignRecipientInput
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if phoneCol >= len(record) {
+			continue
+		}
+
+		rec := model.CampaignRecipientInput{Phone: record[phoneCol], Variables: map[string]string{}}
+		for i, value := range record {
+			if i != phoneCol && i < len(header) && header[i] != "" {
+				rec.Variables[header[i]] = value
+			}
+		}
+		recipients = append(recipients, rec)
+	}
+
+	return recipients, nil
+}
+
+// instanceID identifies this process when leasing campaigns.
+func instanceID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+
+	host, _ := os.Hostname()
+	return host + "-" + hex.EncodeToString(b)
+}
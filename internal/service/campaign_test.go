@@ -0,0 +1,299 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"fiozap/internal/model"
+)
+
+// memoryCampaign keeps the recipients and lease of one campaign.
+type memoryCampaign struct {
+	mu         sync.Mutex
+	recipients []*model.CampaignRecipient
+	leases     int
+	leaseErr   error
+	owner      string
+	released   bool
+	completed  bool
+	staleAfter time.Duration
+}
+
+func newMemoryCampaign(recipients int) *memoryCampaign {
+	m := &memoryCampaign{leases: -1}
+	for i := range recipients {
+		m.recipients = append(m.recipients, &model.CampaignRecipient{
+			CampaignID: "c1",
+			Phone:      fmt.Sprintf("55119000000%02d", i),
+			Status:     model.RecipientStatusPending,
+		})
+	}
+	return m
+}
+
+func (m *memoryCampaign) statuses() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	got := map[string]int{}
+	for _, r := range m.recipients {
+		got[r.Status]++
+	}
+	return got
+}
+
+func (m *memoryCampaign) Create(string, string, *model.CampaignCreateRequest, []model.CampaignRecipientInput) (*model.Campaign, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryCampaign) GetBySession(string, string) (*model.Campaign, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryCampaign) ListBySession(string) ([]model.Campaign, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryCampaign) ListRunning() ([]model.Campaign, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryCampaign) UpdateStatus(string, string, string, ...string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *memoryCampaign) AddRecipients(string, []model.CampaignRecipientInput) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *memoryCampaign) ListRecipients(string, string, int, int) ([]model.CampaignRecipient, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryCampaign) Complete(string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.recipients {
+		if r.Status == model.RecipientStatusPending || r.Status == model.RecipientStatusSending {
+			return false, nil
+		}
+	}
+	m.completed = true
+	return true, nil
+}
+
+// AcquireLease grants the lease while leases is not zero, so a test can
+// take it away after a number of renewals. A negative count never runs out.
+func (m *memoryCampaign) AcquireLease(_, owner string, _ time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.leaseErr != nil {
+		return false, m.leaseErr
+	}
+	if m.leases == 0 {
+		return false, nil
+	}
+	m.leases--
+	m.owner = owner
+	return true, nil
+}
+
+func (m *memoryCampaign) ReleaseLease(_, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.released = owner == m.owner
+	return nil
+}
+
+func (m *memoryCampaign) Progress(string) (*model.CampaignProgress, error) {
+	got := m.statuses()
+	return &model.CampaignProgress{
+		Total:   len(m.recipients),
+		Pending: got[model.RecipientStatusPending],
+		Sent:    got[model.RecipientStatusSent],
+	}, nil
+}
+
+func (m *memoryCampaign) ClaimRecipient(string) (*model.CampaignRecipient, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.recipients {
+		if r.Status == model.RecipientStatusPending {
+			r.Status = model.RecipientStatusSending
+			claimed := *r
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryCampaign) setStatus(phone, status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.recipients {
+		if r.Phone == phone && r.Status == model.RecipientStatusSending {
+			r.Status = status
+		}
+	}
+}
+
+func (m *memoryCampaign) UpdateRecipient(_, phone, status, _, _ string) error {
+	m.setStatus(phone, status)
+	return nil
+}
+
+func (m *memoryCampaign) ReleaseRecipient(_, phone string) error {
+	m.setStatus(phone, model.RecipientStatusPending)
+	return nil
+}
+
+func (m *memoryCampaign) FailStaleRecipients(_ string, olderThan time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.staleAfter = olderThan
+	return nil
+}
+
+func TestCampaignRun(t *testing.T) {
+	tests := []struct {
+		name string
+		// recipients, leases and leaseErr set up the store.
+		recipients int
+		leases     int
+		leaseErr   error
+		// sendOK is the result of every send; a false one puts the
+		// recipient back and waits, which the test ends by stopping the
+		// runner.
+		sendOK        bool
+		offline       bool
+		wantSent      []string
+		wantStatuses  map[string]int
+		wantCompleted bool
+		wantEvents    []string
+	}{
+		{
+			name:          "sends every recipient and completes",
+			recipients:    campaignProgressEvery + 1,
+			leases:        -1,
+			sendOK:        true,
+			wantStatuses:  map[string]int{model.RecipientStatusSent: campaignProgressEvery + 1},
+			wantCompleted: true,
+			wantEvents:    []string{"CampaignProgress", "CampaignCompleted"},
+		},
+		{
+			name:         "stops when the lease is lost",
+			recipients:   3,
+			leases:       2,
+			sendOK:       true,
+			wantSent:     []string{"5511900000000", "5511900000001"},
+			wantStatuses: map[string]int{model.RecipientStatusSent: 2, model.RecipientStatusPending: 1},
+		},
+		{
+			name:         "stops when the lease cannot be renewed",
+			recipients:   2,
+			leases:       -1,
+			leaseErr:     errors.New("database is down"),
+			wantStatuses: map[string]int{model.RecipientStatusPending: 2},
+		},
+		{
+			name:         "a recipient that could not be checked is put back",
+			recipients:   2,
+			leases:       -1,
+			wantSent:     []string{"5511900000000"},
+			wantStatuses: map[string]int{model.RecipientStatusPending: 2},
+		},
+		{
+			name:         "waits while the session is offline",
+			recipients:   2,
+			leases:       -1,
+			sendOK:       true,
+			offline:      true,
+			wantStatuses: map[string]int{model.RecipientStatusPending: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryCampaign(tt.recipients)
+			store.leases = tt.leases
+			store.leaseErr = tt.leaseErr
+
+			var events []string
+			s := &CampaignService{
+				repo:   store,
+				owner:  "instance-1",
+				active: map[string]bool{"c1": true},
+				stopCh: make(chan struct{}),
+			}
+			stop := sync.OnceFunc(func() { close(s.stopCh) })
+			s.online = func(*model.Campaign) bool {
+				if tt.offline {
+					stop()
+				}
+				return !tt.offline
+			}
+			s.reserve = func(string) time.Duration { return 0 }
+			s.handleEvent = func(_, _, eventType string, _ interface{}) {
+				events = append(events, eventType)
+			}
+
+			var sent []string
+			s.sendFn = func(campaign *model.Campaign, recipient *model.CampaignRecipient) bool {
+				sent = append(sent, recipient.Phone)
+				if !tt.sendOK {
+					_ = s.repo.ReleaseRecipient(campaign.ID, recipient.Phone)
+					stop()
+					return false
+				}
+				_ = s.repo.UpdateRecipient(campaign.ID, recipient.Phone, model.RecipientStatusSent, "", "id-"+recipient.Phone)
+				return true
+			}
+
+			campaign := &model.Campaign{ID: "c1", UserID: "user", SessionID: "session", RatePerMinute: 600000}
+			done := make(chan struct{})
+			s.wg.Add(1)
+			go func() {
+				s.run(campaign)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				stop()
+				t.Fatal("run() did not return")
+			}
+
+			if tt.wantSent != nil && !slices.Equal(sent, tt.wantSent) {
+				t.Errorf("sent to %v, want %v", sent, tt.wantSent)
+			}
+			if got := store.statuses(); fmt.Sprint(got) != fmt.Sprint(tt.wantStatuses) {
+				t.Errorf("recipient statuses = %v, want %v", got, tt.wantStatuses)
+			}
+			if store.completed != tt.wantCompleted {
+				t.Errorf("completed = %v, want %v", store.completed, tt.wantCompleted)
+			}
+			if !slices.Equal(events, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", events, tt.wantEvents)
+			}
+			if store.staleAfter != campaignStaleAfter {
+				t.Errorf("stale recipients failed after %v, want %v", store.staleAfter, campaignStaleAfter)
+			}
+			if tt.leaseErr == nil && !store.released {
+				t.Error("lease was not released")
+			}
+			if s.active["c1"] {
+				t.Error("campaign is still marked active")
+			}
+		})
+	}
+}
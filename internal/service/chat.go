@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"fiozap/internal/model"
+)
+
+func (s *MessageService) PinChat(ctx context.Context, userID, sessionID string, req *model.PinChatMessage) (map[string]interface{}, error) {
+	client, chatJID, err := s.chatClient(userID, sessionID, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.SendAppState(ctx, appstate.BuildPin(chatJID, req.Pin)); err != nil {
+		return nil, fmt.Errorf("failed to pin chat: %w", err)
+	}
+
+	return map[string]interface{}{
+		"pinned": req.Pin,
+	}, nil
+}
+
+func (s *MessageService) MuteChat(ctx context.Context, userID, sessionID string, req *model.MuteChatMessage) (map[string]interface{}, error) {
+	client, chatJID, err := s.chatClient(userID, sessionID, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(req.DurationSeconds) * time.Second
+	if err := client.SendAppState(ctx, appstate.BuildMute(chatJID, req.Mute, duration)); err != nil {
+		return nil, fmt.Errorf("failed to mute chat: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"muted": req.Mute,
+	}
+	if req.Mute && duration > 0 {
+		result["muteEnd"] = time.Now().Add(duration).Unix()
+	}
+	return result, nil
+}
+
+// MarkChatRead marks a chat as read or unread on the linked devices. Unlike
+// MarkRead it sends no read receipts.
+func (s *MessageService) MarkChatRead(ctx context.Context, userID, sessionID string, req *model.ReadChatMessage) (map[string]interface{}, error) {
+	client, chatJID, err := s.chatClient(userID, sessionID, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.SendAppState(ctx, appstate.BuildMarkChatAsRead(chatJID, req.Read, time.Time{}, nil)); err != nil {
+		return nil, fmt.Errorf("failed to mark chat: %w", err)
+	}
+
+	return map[string]interface{}{
+		"read": req.Read,
+	}, nil
+}
+
+func (s *MessageService) DeleteChat(ctx context.Context, userID, sessionID string, req *model.DeleteChatMessage) (map[string]interface{}, error) {
+	client, chatJID, err := s.chatClient(userID, sessionID, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.SendAppState(ctx, appstate.BuildDeleteChat(chatJID, time.Time{}, nil)); err != nil {
+		return nil, fmt.Errorf("failed to delete chat: %w", err)
+	}
+
+	return map[string]interface{}{
+		"deleted": true,
+	}, nil
+}
+
+func (s *MessageService) ClearChat(ctx context.Context, userID, sessionID string, req *model.ClearChatMessage) (map[string]interface{}, error) {
+	client, chatJID, err := s.chatClient(userID, sessionID, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.SendAppState(ctx, buildClearChat(chatJID, !req.KeepStarred)); err != nil {
+		return nil, fmt.Errorf("failed to clear chat: %w", err)
+	}
+
+	return map[string]interface{}{
+		"cleared": true,
+	}, nil
+}
+
+// ErrSenderPhoneRequired is returned when starring a message received in a
+// group without its sender.
+var ErrSenderPhoneRequired = errors.New("sender_phone is required for messages received in groups")
+
+func (s *MessageService) StarMessage(ctx context.Context, userID, sessionID string, req *model.StarMessage) (map[string]interface{}, error) {
+	client, chatJID, err := s.chatClient(userID, sessionID, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	sender, err := starSender(chatJID, req, client.Store.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := appstate.BuildStar(chatJID, sender, req.MessageID, req.FromMe, req.Star)
+	if err := client.SendAppState(ctx, patch); err != nil {
+		return nil, fmt.Errorf("failed to star message: %w", err)
+	}
+
+	return map[string]interface{}{
+		"id":      req.MessageID,
+		"starred": req.Star,
+	}, nil
+}
+
+// starSender returns the sender of a message to star. Outside groups the
+// sender is left out of the patch, so the chat stands in for it; in groups it
+// is the own JID for own messages and sender_phone otherwise.
+func starSender(chatJID types.JID, req *model.StarMessage, ownID *types.JID) (types.JID, error) {
+	if chatJID.Server != types.GroupServer {
+		return chatJID, nil
+	}
+
+	switch {
+	case req.FromMe:
+		if ownID == nil {
+			return types.EmptyJID, ErrSessionNotLoggedIn
+		}
+		return ownID.ToNonAD(), nil
+	case req.SenderPhone != "":
+		return parseJID(req.SenderPhone)
+	default:
+		return types.EmptyJID, ErrSenderPhoneRequired
+	}
+}
+
+func (s *MessageService) chatClient(userID, sessionID, phone string) (*whatsmeow.Client, types.JID, error) {
+	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
+	if client == nil {
+		return nil, types.EmptyJID, ErrNoSession
+	}
+
+	chatJID, err := parseJID(phone)
+	if err != nil {
+		return nil, types.EmptyJID, err
+	}
+
+	return client, chatJID, nil
+}
+
+// buildClearChat builds the app state patch that clears the messages of a
+// chat, which whatsmeow has no builder for. The index flags whether starred
+// messages and media are deleted as well; media is always kept.
+func buildClearChat(target types.JID, deleteStarred bool) appstate.PatchInfo {
+	starred := "0"
+	if deleteStarred {
+		starred = "1"
+	}
+
+	return appstate.PatchInfo{
+		Type: appstate.WAPatchRegularHigh,
+		Mutations: []appstate.MutationInfo{{
+			Index:   []string{appstate.IndexClearChat, target.String(), starred, "0"},
+			Version: 6,
+			Value: &waSyncAction.SyncActionValue{
+				ClearChatAction: &waSyncAction.ClearChatAction{
+					MessageRange: &waSyncAction.SyncActionMessageRange{
+						LastMessageTimestamp: proto.Int64(time.Now().Unix()),
+					},
+				},
+			},
+		}},
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+
+	"fiozap/internal/model"
+)
+
+func TestBuildClearChat(t *testing.T) {
+	chat := types.NewJID("5511999999999", types.DefaultUserServer)
+
+	tests := []struct {
+		name          string
+		deleteStarred bool
+		wantIndex     []string
+	}{
+		{name: "keeps starred messages", wantIndex: []string{appstate.IndexClearChat, chat.String(), "0", "0"}},
+		{name: "deletes starred messages", deleteStarred: true, wantIndex: []string{appstate.IndexClearChat, chat.String(), "1", "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now().Unix()
+			patch := buildClearChat(chat, tt.deleteStarred)
+
+			if patch.Type != appstate.WAPatchRegularHigh {
+				t.Errorf("patch type = %q, want %q", patch.Type, appstate.WAPatchRegularHigh)
+			}
+			if len(patch.Mutations) != 1 {
+				t.Fatalf("patch has %d mutations, want 1", len(patch.Mutations))
+			}
+
+			m := patch.Mutations[0]
+			if !slices.Equal(m.Index, tt.wantIndex) {
+				t.Errorf("index = %v, want %v", m.Index, tt.wantIndex)
+			}
+			if m.Version != 6 {
+				t.Errorf("version = %d, want 6", m.Version)
+			}
+			ts := m.Value.GetClearChatAction().GetMessageRange().GetLastMessageTimestamp()
+			if ts < before || ts > time.Now().Unix() {
+				t.Errorf("last message timestamp = %d, want the time of the call", ts)
+			}
+		})
+	}
+}
+
+func TestStarSender(t *testing.T) {
+	chat := types.NewJID("5511999999999", types.DefaultUserServer)
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	own := types.NewADJID("5511777777777", 0, 12)
+
+	tests := []struct {
+		name    string
+		chat    types.JID
+		req     model.StarMessage
+		ownID   *types.JID
+		want    types.JID
+		wantErr error
+	}{
+		{name: "private chat stands in for the sender", chat: chat, req: model.StarMessage{SenderPhone: "5511888888888"}, want: chat},
+		{name: "own group message uses own jid without device", chat: group, req: model.StarMessage{FromMe: true}, ownID: &own, want: types.NewJID("5511777777777", types.DefaultUserServer)},
+		{name: "own group message needs a login", chat: group, req: model.StarMessage{FromMe: true}, wantErr: ErrSessionNotLoggedIn},
+		{name: "group message uses sender phone", chat: group, req: model.StarMessage{SenderPhone: "+5511888888888"}, want: types.NewJID("5511888888888", types.DefaultUserServer)},
+		{name: "group message needs sender phone", chat: group, req: model.StarMessage{}, wantErr: ErrSenderPhoneRequired},
+		{name: "invalid sender phone", chat: group, req: model.StarMessage{SenderPhone: "5511888888888:x@s.whatsapp.net"}, wantErr: ErrInvalidJID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := starSender(tt.chat, &tt.req, tt.ownID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("starSender() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("starSender() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("starSender() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
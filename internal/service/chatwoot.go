@@ -0,0 +1,492 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"fiozap/internal/chatwoot"
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+	"fiozap/internal/wameow"
+)
+
+const (
+	chatwootTimeout        = 2 * time.Minute
+	chatwootLockStripes    = 64
+	chatwootStatusResolved = "resolved"
+)
+
+var (
+	ErrChatwootNotFound = errors.New("chatwoot integration not found")
+	ErrChatwootToken    = errors.New("invalid webhook token")
+)
+
+// ChatwootService bridges sessions to Chatwoot inboxes. Incoming messages are
+// posted to a conversation of the contact, which is looked up or created on
+// the first message of a chat, and agent replies from the Chatwoot webhook are
+// sent through MessageService.
+type ChatwootService struct {
+	repo           *repository.ChatwootRepository
+	messageService *MessageService
+	sessionService *SessionService
+	// transport only reaches private addresses on the hosts allowed by the
+	// administrator, since any API user can set the base URL.
+	transport *privateHostTransport
+	// locks serializes the messages of a chat, so its contact and
+	// conversation are only created once.
+	locks [chatwootLockStripes]sync.Mutex
+}
+
+func NewChatwootService(repo *repository.ChatwootRepository, messageService *MessageService, sessionService *SessionService, privateHosts []string) *ChatwootService {
+	return &ChatwootService{
+		repo:           repo,
+		messageService: messageService,
+		sessionService: sessionService,
+		transport:      newPrivateHostTransport(privateHosts, chatwootTimeout),
+	}
+}
+
+func (s *ChatwootService) Get(sessionID string) (*model.ChatwootConfig, error) {
+	cfg, err := s.repo.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chatwoot integration: %w", err)
+	}
+	if cfg == nil {
+		return nil, ErrChatwootNotFound
+	}
+	cfg.WebhookPath = chatwootWebhookPath(cfg)
+	return cfg, nil
+}
+
+func (s *ChatwootService) Set(sessionID string, req *model.ChatwootConfigRequest) (*model.ChatwootConfig, error) {
+	base, err := url.Parse(strings.TrimSpace(req.BaseURL))
+	if err != nil || (base.Scheme != "http" && base.Scheme != schemeHTTPS) || base.Host == "" {
+		return nil, errors.New("base_url must be an http or https URL")
+	}
+	if req.AccountID <= 0 {
+		return nil, errors.New("account_id is required")
+	}
+	if req.InboxID <= 0 {
+		return nil, errors.New("inbox_id is required")
+	}
+
+	existing, err := s.repo.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chatwoot integration: %w", err)
+	}
+	token := req.APIToken
+	if token == "" && existing != nil {
+		token = existing.APIToken
+	}
+	if token == "" {
+		return nil, errors.New("api_token is required")
+	}
+
+	cfg, err := s.repo.Save(&model.ChatwootConfig{
+		SessionID:    sessionID,
+		Enabled:      req.Enabled == nil || *req.Enabled,
+		BaseURL:      strings.TrimRight(base.String(), "/"),
+		AccountID:    req.AccountID,
+		InboxID:      req.InboxID,
+		APIToken:     token,
+		IgnoreGroups: req.IgnoreGroups == nil || *req.IgnoreGroups,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save chatwoot integration: %w", err)
+	}
+	cfg.WebhookPath = chatwootWebhookPath(cfg)
+	return cfg, nil
+}
+
+func (s *ChatwootService) Delete(sessionID string) error {
+	deleted, err := s.repo.Delete(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete chatwoot integration: %w", err)
+	}
+	if !deleted {
+		return ErrChatwootNotFound
+	}
+	return nil
+}
+
+// HandleMessage posts an incoming message to the Chatwoot conversation of its
+// chat. A conversation that was deleted in Chatwoot is replaced by a new one.
+func (s *ChatwootService) HandleMessage(userID, sessionID string, evt *events.Message) {
+	info := evt.Info
+	if info.IsFromMe || info.Chat.Server == types.BroadcastServer || info.Chat.Server == types.NewsletterServer {
+		return
+	}
+	if wameow.ParseMessageChange(evt) != nil {
+		return
+	}
+
+	cfg, err := s.repo.Get(sessionID)
+	if err != nil {
+		logger.WarnComponent("chatwoot").Str("session_id", sessionID).Err(err).Msg("failed to get integration")
+		return
+	}
+	if cfg == nil || !cfg.Enabled || (info.IsGroup && cfg.IgnoreGroups) {
+		return
+	}
+
+	wac := s.sessionService.GetWhatsmeowClient(userID, sessionID)
+	if wac == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), chatwootTimeout)
+	defer cancel()
+
+	content, media := s.inboundContent(ctx, wac, evt)
+	if content == "" && media == nil {
+		return
+	}
+
+	chat := info.Chat.String()
+	lock := s.lock(sessionID, chat)
+	lock.Lock()
+	defer lock.Unlock()
+
+	client := s.client(cfg)
+	conv, err := s.post(ctx, client, cfg, wac, evt, content, media)
+	if chatwoot.IsNotFound(err) {
+		if err = s.repo.DeleteConversation(sessionID, chat); err == nil {
+			conv, err = s.post(ctx, client, cfg, wac, evt, content, media)
+		}
+	}
+	if err != nil {
+		logger.WarnComponent("chatwoot").Str("session_id", sessionID).Str("chat", chat).Err(err).Msg("failed to post message")
+		return
+	}
+
+	logger.Component("chatwoot").Str("session_id", sessionID).Str("chat", chat).
+		Int64("conversation_id", conv.ConversationID).Msg("message posted")
+}
+
+// chatwootMedia is the downloaded media of an incoming message.
+type chatwootMedia struct {
+	data     []byte
+	fileName string
+	mimeType string
+}
+
+// post adds a message to the conversation of its chat.
+func (s *ChatwootService) post(ctx context.Context, client *chatwoot.Client, cfg *model.ChatwootConfig, wac *whatsmeow.Client, evt *events.Message, content string, media *chatwootMedia) (*model.ChatwootConversation, error) {
+	conv, err := s.conversation(ctx, client, cfg, wac, evt)
+	if err != nil {
+		return nil, err
+	}
+
+	var att *chatwoot.Attachment
+	if media != nil {
+		att = &chatwoot.Attachment{Reader: bytes.NewReader(media.data), FileName: media.fileName, MimeType: media.mimeType}
+	}
+	if _, err := client.CreateIncomingMessage(ctx, conv.ConversationID, content, att); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// inboundContent returns the text and media posted to Chatwoot for a
+// message. Media that cannot be downloaded is reported in the text instead.
+func (s *ChatwootService) inboundContent(ctx context.Context, wac *whatsmeow.Client, evt *events.Message) (string, *chatwootMedia) {
+	m := evt.Message
+	content := wameow.MessageText(evt)
+	switch {
+	case m.GetLocationMessage() != nil:
+		loc := m.GetLocationMessage()
+		content = fmt.Sprintf("Location: https://maps.google.com/?q=%f,%f", loc.GetDegreesLatitude(), loc.GetDegreesLongitude())
+	case m.GetContactMessage() != nil:
+		content = "Contact: " + m.GetContactMessage().GetDisplayName()
+	case m.GetPollCreationMessage() != nil:
+		content = "Poll: " + m.GetPollCreationMessage().GetName()
+	case m.GetPollCreationMessageV3() != nil:
+		content = "Poll: " + m.GetPollCreationMessageV3().GetName()
+	}
+	if evt.Info.IsGroup && content != "" {
+		content = evt.Info.PushName + ": " + content
+	}
+
+	media, kind, mimeType, fileName, size := downloadableMedia(evt)
+	if media == nil {
+		return content, nil
+	}
+
+	var data []byte
+	var err error
+	if int64(size) > s.messageService.maxMediaSize {
+		err = errMediaTooLarge
+	} else {
+		data, err = wac.Download(ctx, media)
+	}
+	if err != nil {
+		logger.WarnComponent("chatwoot").Str("message_id", evt.Info.ID).Err(err).Msg("failed to download media")
+		return strings.TrimSpace(content + "\n[" + kind + " could not be downloaded]"), nil
+	}
+
+	if fileName == "" {
+		fileName = kind
+		if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+			fileName += exts[0]
+		}
+	}
+	return content, &chatwootMedia{data: data, fileName: fileName, mimeType: mimeType}
+}
+
+// conversation returns the Chatwoot conversation of the chat of a message,
+// looking up or creating its contact and conversation when the chat has no
+// conversation yet.
+func (s *ChatwootService) conversation(ctx context.Context, client *chatwoot.Client, cfg *model.ChatwootConfig, wac *whatsmeow.Client, evt *events.Message) (*model.ChatwootConversation, error) {
+	chat := evt.Info.Chat
+	conv, err := s.repo.GetConversation(cfg.SessionID, chat.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if conv != nil {
+		return conv, nil
+	}
+
+	contact, err := s.contact(ctx, client, cfg, wac, evt)
+	if err != nil {
+		return nil, err
+	}
+
+	conversations, err := client.ListConversations(ctx, contact.ID)
+	if err != nil {
+		return nil, err
+	}
+	var conversationID int64
+	for _, c := range conversations {
+		if c.InboxID == cfg.InboxID && c.Status != chatwootStatusResolved {
+			conversationID = c.ID
+			break
+		}
+	}
+	if conversationID == 0 {
+		created, err := client.CreateConversation(ctx, cfg.InboxID, contact.ID)
+		if err != nil {
+			return nil, err
+		}
+		conversationID = created.ID
+	}
+
+	conv = &model.ChatwootConversation{
+		SessionID:      cfg.SessionID,
+		Chat:           chat.String(),
+		ContactID:      contact.ID,
+		ConversationID: conversationID,
+	}
+	if err := s.repo.SaveConversation(conv); err != nil {
+		return nil, fmt.Errorf("failed to save conversation: %w", err)
+	}
+	return conv, nil
+}
+
+// contact finds the Chatwoot contact of a chat by its JID identifier or phone
+// number, and creates it when there is none.
+func (s *ChatwootService) contact(ctx context.Context, client *chatwoot.Client, cfg *model.ChatwootConfig, wac *whatsmeow.Client, evt *events.Message) (*chatwoot.Contact, error) {
+	chat := evt.Info.Chat
+	identifier := chat.String()
+
+	var phone, name string
+	if evt.Info.IsGroup {
+		name = chat.User
+		if group, err := wac.GetGroupInfo(ctx, chat); err == nil && group.Name != "" {
+			name = group.Name
+		}
+	} else {
+		phone = senderPhone(evt)
+		name = evt.Info.PushName
+	}
+	if name == "" {
+		name = chat.User
+	}
+
+	query := phone
+	if query == "" {
+		query = chat.User
+	}
+	contacts, err := client.SearchContacts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for i := range contacts {
+		if contacts[i].Identifier == identifier || (phone != "" && contacts[i].PhoneNumber == "+"+phone) {
+			return &contacts[i], nil
+		}
+	}
+
+	return client.CreateContact(ctx, cfg.InboxID, name, phone, identifier)
+}
+
+// HandleWebhook accepts an event of the Chatwoot webhook of a session and
+// sends public agent replies to the chat of their conversation in the
+// background. It reports whether the event was accepted for delivery.
+func (s *ChatwootService) HandleWebhook(sessionID, token string, evt *chatwoot.WebhookEvent) (bool, error) {
+	cfg, err := s.repo.Get(sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chatwoot integration: %w", err)
+	}
+	if cfg == nil {
+		return false, ErrChatwootNotFound
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.WebhookToken)) != 1 {
+		return false, ErrChatwootToken
+	}
+	if !cfg.Enabled || !evt.IsAgentReply() {
+		return false, nil
+	}
+
+	chat, err := s.repo.GetChat(sessionID, evt.Conversation.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if chat == "" {
+		logger.WarnComponent("chatwoot").Str("session_id", sessionID).
+			Int64("conversation_id", evt.Conversation.ID).Msg("reply to unknown conversation ignored")
+		return false, nil
+	}
+
+	session, err := s.sessionService.GetSession(sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	go s.deliver(session.UserID, cfg, chat, evt)
+	return true, nil
+}
+
+// deliver sends an agent reply to a chat. The text is used as the caption of
+// the first attachment when that is an image, video or document, and sent on
+// its own otherwise.
+func (s *ChatwootService) deliver(userID string, cfg *model.ChatwootConfig, chat string, evt *chatwoot.WebhookEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), chatwootTimeout)
+	defer cancel()
+
+	content := strings.TrimSpace(evt.Content)
+	if content != "" && (len(evt.Attachments) == 0 || evt.Attachments[0].FileType == chatwoot.FileTypeAudio) {
+		_, err := s.messageService.SendText(ctx, userID, cfg.SessionID, &model.TextMessage{Phone: chat, Message: content})
+		if err != nil {
+			s.logDeliveryError(cfg.SessionID, chat, evt, err)
+			return
+		}
+		content = ""
+	}
+
+	client := s.client(cfg)
+	for _, att := range evt.Attachments {
+		if err := s.sendAttachment(ctx, client, userID, cfg.SessionID, chat, att, content); err != nil {
+			s.logDeliveryError(cfg.SessionID, chat, evt, err)
+			return
+		}
+		content = ""
+	}
+
+	logger.Component("chatwoot").Str("session_id", cfg.SessionID).Str("chat", chat).
+		Int64("message_id", evt.ID).Msg("reply sent")
+}
+
+func (s *ChatwootService) sendAttachment(ctx context.Context, client *chatwoot.Client, userID, sessionID, chat string, att chatwoot.WebhookAttachment, caption string) error {
+	body, mimeType, fileName, err := client.Download(ctx, att.DataURL, s.messageService.maxMediaSize)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	upload := &model.MediaUpload{Reader: body, FileName: fileName, MimeType: mimeType}
+	switch att.FileType {
+	case chatwoot.FileTypeImage:
+		_, err = s.messageService.SendImage(ctx, userID, sessionID, &model.ImageMessage{Phone: chat, Caption: caption, MimeType: mimeType, Upload: upload})
+	case chatwoot.FileTypeVideo:
+		_, err = s.messageService.SendVideo(ctx, userID, sessionID, &model.VideoMessage{Phone: chat, Caption: caption, MimeType: mimeType, Upload: upload})
+	case chatwoot.FileTypeAudio:
+		_, err = s.messageService.SendAudio(ctx, userID, sessionID, &model.AudioMessage{Phone: chat, MimeType: mimeType, Upload: upload})
+	default:
+		_, err = s.messageService.SendDocument(ctx, userID, sessionID, &model.DocumentMessage{Phone: chat, FileName: fileName, Caption: caption, MimeType: mimeType, Upload: upload})
+	}
+	return err
+}
+
+func (s *ChatwootService) logDeliveryError(sessionID, chat string, evt *chatwoot.WebhookEvent, err error) {
+	logger.WarnComponent("chatwoot").Str("session_id", sessionID).Str("chat", chat).
+		Int64("message_id", evt.ID).Err(err).Msg("failed to send reply")
+}
+
+func (s *ChatwootService) client(cfg *model.ChatwootConfig) *chatwoot.Client {
+	return chatwoot.NewClient(s.httpClient(cfg.BaseURL), cfg.BaseURL, cfg.AccountID, cfg.APIToken)
+}
+
+// httpClient returns the HTTP client of a Chatwoot instance. Redirects may
+// leave its host, e.g. for attachments kept in an object storage, but only
+// over https.
+func (s *ChatwootService) httpClient(baseURL string) *http.Client {
+	host := ""
+	if base, err := url.Parse(baseURL); err == nil {
+		host = base.Host
+	}
+
+	return &http.Client{
+		Transport: s.transport,
+		Timeout:   chatwootTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= fetchMaxRedirects {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Host != host && req.URL.Scheme != schemeHTTPS {
+				return errors.New("redirect to non-https URL")
+			}
+			return nil
+		},
+	}
+}
+
+func (s *ChatwootService) lock(sessionID, chat string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(sessionID + ":" + chat))
+	return &s.locks[h.Sum32()%chatwootLockStripes]
+}
+
+// chatwootWebhookPath is the path, relative to the public URL of the API, to
+// configure as webhook of the Chatwoot inbox.
+func chatwootWebhookPath(cfg *model.ChatwootConfig) string {
+	return "/chatwoot/" + url.PathEscape(cfg.SessionID) + "/webhook?token=" + cfg.WebhookToken
+}
+
+// downloadableMedia returns the media of a message with its kind, mime type,
+// file name and size, or a nil media for messages without one.
+func downloadableMedia(evt *events.Message) (whatsmeow.DownloadableMessage, string, string, string, uint64) {
+	m := evt.Message
+	switch {
+	case m.GetImageMessage() != nil:
+		img := m.GetImageMessage()
+		return img, mediaImage.name, img.GetMimetype(), "", img.GetFileLength()
+	case m.GetVideoMessage() != nil:
+		vid := m.GetVideoMessage()
+		return vid, mediaVideo.name, vid.GetMimetype(), "", vid.GetFileLength()
+	case m.GetAudioMessage() != nil:
+		aud := m.GetAudioMessage()
+		return aud, mediaAudio.name, aud.GetMimetype(), "", aud.GetFileLength()
+	case m.GetDocumentMessage() != nil:
+		doc := m.GetDocumentMessage()
+		return doc, mediaDocument.name, doc.GetMimetype(), doc.GetFileName(), doc.GetFileLength()
+	case m.GetStickerMessage() != nil:
+		st := m.GetStickerMessage()
+		return st, mediaSticker.name, st.GetMimetype(), "", st.GetFileLength()
+	}
+	return nil, "", "", "", 0
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fiozap/internal/chatwoot"
+	"fiozap/internal/model"
+)
+
+const chatwootTestToken = "secret-token"
+
+// newChatwootTestService allows Chatwoot on localhost, so test servers are
+// reached as localhost when trusted and as 127.0.0.1 when not.
+func newChatwootTestService(public http.RoundTripper) *ChatwootService {
+	s := NewChatwootService(nil, nil, nil, []string{"localhost"})
+	if public != nil {
+		s.transport.public = public
+	}
+	return s
+}
+
+func localhostURL(server *httptest.Server) string {
+	return strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+}
+
+func TestChatwootCreateIncomingMessage(t *testing.T) {
+	var gotPath, gotToken, gotContent, gotType, gotFile, gotData string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotToken = r.Header.Get("api_access_token")
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("invalid multipart form: %v", err)
+			return
+		}
+		gotContent = r.FormValue("content")
+		gotType = r.FormValue("message_type")
+		if files := r.MultipartForm.File["attachments[]"]; len(files) == 1 {
+			gotFile = files[0].Filename
+			f, _ := files[0].Open()
+			data, _ := io.ReadAll(f)
+			gotData = string(data)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int64{"id": 99})
+	}))
+	defer server.Close()
+
+	s := newChatwootTestService(nil)
+	client := s.client(&model.ChatwootConfig{BaseURL: localhostURL(server), AccountID: 7, APIToken: chatwootTestToken})
+
+	msg, err := client.CreateIncomingMessage(context.Background(), 42, "hello", &chatwoot.Attachment{
+		Reader:   strings.NewReader("image bytes"),
+		FileName: "image.jpg",
+		MimeType: "image/jpeg",
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomingMessage() error = %v", err)
+	}
+	if msg.ID != 99 {
+		t.Errorf("message id = %d, want 99", msg.ID)
+	}
+	if gotPath != "/api/v1/accounts/7/conversations/42/messages" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotToken != chatwootTestToken {
+		t.Errorf("token = %q, want %q", gotToken, chatwootTestToken)
+	}
+	if gotContent != "hello" || gotType != chatwoot.MessageTypeIncoming {
+		t.Errorf("content = %q, message_type = %q", gotContent, gotType)
+	}
+	if gotFile != "image.jpg" || gotData != "image bytes" {
+		t.Errorf("attachment = %q with %q", gotFile, gotData)
+	}
+}
+
+func TestChatwootWebhookReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    bool
+	}{
+		{
+			name:    "agent reply",
+			payload: `{"event":"message_created","id":1,"content":"hi","message_type":"outgoing","private":false,"conversation":{"id":42}}`,
+			want:    true,
+		},
+		{
+			name:    "private note",
+			payload: `{"event":"message_created","id":1,"content":"hi","message_type":"outgoing","private":true,"conversation":{"id":42}}`,
+		},
+		{
+			name:    "incoming message",
+			payload: `{"event":"message_created","id":1,"content":"hi","message_type":"incoming","conversation":{"id":42}}`,
+		},
+		{
+			name:    "other event",
+			payload: `{"event":"conversation_status_changed","conversation":{"id":42}}`,
+		},
+		{
+			name:    "missing conversation",
+			payload: `{"event":"message_created","id":1,"content":"hi","message_type":"outgoing"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evt chatwoot.WebhookEvent
+			if err := json.Unmarshal([]byte(tt.payload), &evt); err != nil {
+				t.Fatalf("invalid payload: %v", err)
+			}
+			if got := evt.IsAgentReply(); got != tt.want {
+				t.Errorf("IsAgentReply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var evt chatwoot.WebhookEvent
+	payload := `{"event":"message_created","message_type":"outgoing","conversation":{"id":42},"attachments":[{"file_type":"image","data_url":"https://chatwoot.example/file.jpg"}]}`
+	if err := json.Unmarshal([]byte(payload), &evt); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if len(evt.Attachments) != 1 || evt.Attachments[0].FileType != chatwoot.FileTypeImage || evt.Attachments[0].DataURL != "https://chatwoot.example/file.jpg" {
+		t.Errorf("attachments = %+v", evt.Attachments)
+	}
+}
+
+func TestChatwootAttachmentRedirect(t *testing.T) {
+	var storageToken string
+	storage := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storageToken = r.Header.Get("api_access_token")
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = io.WriteString(w, "image bytes")
+	}))
+	defer storage.Close()
+
+	plainStorage := httptest.NewServer(storage.Config.Handler)
+	defer plainStorage.Close()
+
+	var chatwootToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/local.jpg":
+			chatwootToken = r.Header.Get("api_access_token")
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = io.WriteString(w, "image bytes")
+		case "/same-host":
+			http.Redirect(w, r, "/local.jpg", http.StatusFound)
+		case "/storage":
+			http.Redirect(w, r, storage.URL+"/file.jpg", http.StatusFound)
+		case "/plain-storage":
+			http.Redirect(w, r, plainStorage.URL+"/file.jpg", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		public       http.RoundTripper
+		wantErr      error
+		wantFailure  bool
+		wantChatwoot string
+	}{
+		{name: "same host keeps token", path: "/same-host", wantChatwoot: chatwootTestToken},
+		{name: "storage host drops token", path: "/storage", public: storage.Client().Transport},
+		{name: "private storage host is blocked", path: "/storage", wantErr: errBlockedAddress},
+		{name: "plain http storage host is rejected", path: "/plain-storage", public: http.DefaultTransport, wantFailure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatwootToken, storageToken = "", "unset"
+			s := newChatwootTestService(tt.public)
+			client := s.client(&model.ChatwootConfig{BaseURL: localhostURL(server), AccountID: 7, APIToken: chatwootTestToken})
+
+			body, mimeType, _, err := client.Download(context.Background(), localhostURL(server)+tt.path, 1<<20)
+			if tt.wantErr != nil || tt.wantFailure {
+				if err == nil {
+					_ = body.Close()
+					t.Fatal("Download() succeeded, want error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("Download() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			data, err := io.ReadAll(body)
+			_ = body.Close()
+			if err != nil || string(data) != "image bytes" || mimeType != "image/jpeg" {
+				t.Errorf("Download() = %q (%s), %v", data, mimeType, err)
+			}
+			if chatwootToken != tt.wantChatwoot {
+				t.Errorf("chatwoot token = %q, want %q", chatwootToken, tt.wantChatwoot)
+			}
+			if tt.path == "/storage" && storageToken != "" {
+				t.Errorf("storage received token %q", storageToken)
+			}
+		})
+	}
+
+	s := newChatwootTestService(nil)
+	client := s.client(&model.ChatwootConfig{BaseURL: localhostURL(server), AccountID: 7, APIToken: chatwootTestToken})
+	if body, _, _, err := client.Download(context.Background(), storage.URL+"/file.jpg", 1<<20); err == nil {
+		_ = body.Close()
+		t.Error("Download() fetched a URL outside the chatwoot host")
+	}
+}
+
+func TestChatwootPrivateHosts(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"payload": []chatwoot.Contact{}})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		allowed []string
+		baseURL string
+		wantErr bool
+	}{
+		{name: "allowed host", allowed: []string{"localhost"}, baseURL: localhostURL(server)},
+		{name: "allowed host ignores case", allowed: []string{"LocalHost"}, baseURL: localhostURL(server)},
+		{name: "private host is blocked", baseURL: localhostURL(server), wantErr: true},
+		{name: "other private host is blocked", allowed: []string{"localhost"}, baseURL: server.URL, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			s := NewChatwootService(nil, nil, nil, tt.allowed)
+			client := s.client(&model.ChatwootConfig{BaseURL: tt.baseURL, AccountID: 7, APIToken: chatwootTestToken})
+
+			_, err := client.SearchContacts(context.Background(), "5511999999999")
+			if tt.wantErr {
+				if !errors.Is(err, errBlockedAddress) {
+					t.Errorf("SearchContacts() error = %v, want %v", err, errBlockedAddress)
+				}
+				if calls != 0 {
+					t.Errorf("server received %d requests", calls)
+				}
+				return
+			}
+			if err != nil || calls != 1 {
+				t.Errorf("SearchContacts() error = %v after %d requests", err, calls)
+			}
+		})
+	}
+}
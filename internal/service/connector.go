@@ -0,0 +1,519 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+	"fiozap/internal/wameow"
+)
+
+const (
+	connectorDefaultTimeout  = 30
+	connectorMaxTimeout      = 120
+	connectorDefaultDebounce = 1500
+	connectorMaxDebounce     = 60000
+	connectorMaxBatch        = 20
+	connectorMaxResponse     = 1 << 20
+	connectorMaxTyping       = 20 * time.Second
+	connectorActionTimeout   = 2 * time.Minute
+	connectorLockStripes     = 64
+	connectorUserAgent       = "FioZap-Connector/1.0"
+)
+
+var ErrConnectorNotFound = errors.New("connector not found")
+
+// ConnectorService calls the HTTP connector of a session for its incoming
+// messages and runs the actions of the answers. Messages of a chat are
+// collected until the debounce period passes without a new one, and the calls
+// of a chat never overlap.
+type ConnectorService struct {
+	repo           *repository.ConnectorRepository
+	messageService *MessageService
+	sessionService *SessionService
+	transport      *privateHostTransport
+	http           *http.Client
+	// dispatch calls the connector with a batch that is complete.
+	dispatch func(b *connectorBatch)
+
+	mu      sync.Mutex
+	pending map[string]*connectorBatch
+	// locks serializes the calls of a chat, so answers are delivered in the
+	// order the messages arrived.
+	locks [connectorLockStripes]sync.Mutex
+}
+
+// connectorBatch holds the messages of a chat waiting for the debounce timer.
+type connectorBatch struct {
+	key       string
+	userID    string
+	sessionID string
+	messages  []*events.Message
+	timer     *time.Timer
+}
+
+// NewConnectorService returns the connector service. Connectors are called
+// like media URLs are fetched: over https, on public addresses and with at
+// most fetchMaxRedirects https redirects. privateHosts are exempt from the
+// address check and may use http.
+func NewConnectorService(repo *repository.ConnectorRepository, messageService *MessageService, sessionService *SessionService, privateHosts []string) *ConnectorService {
+	transport := newPrivateHostTransport(privateHosts, time.Duration(connectorMaxTimeout)*time.Second)
+	s := &ConnectorService{
+		repo:           repo,
+		messageService: messageService,
+		sessionService: sessionService,
+		transport:      transport,
+		http: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= fetchMaxRedirects {
+					return errors.New("too many redirects")
+				}
+				if req.URL.Scheme != schemeHTTPS {
+					return errors.New("redirect to non-https URL")
+				}
+				return nil
+			},
+		},
+		pending: make(map[string]*connectorBatch),
+	}
+	s.dispatch = s.run
+	return s
+}
+
+func (s *ConnectorService) Get(sessionID string) (*model.Connector, error) {
+	c, err := s.repo.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connector: %w", err)
+	}
+	if c == nil {
+		return nil, ErrConnectorNotFound
+	}
+	return c, nil
+}
+
+func (s *ConnectorService) Set(sessionID string, req *model.ConnectorRequest) (*model.Connector, error) {
+	endpoint, err := url.Parse(strings.TrimSpace(req.URL))
+	if err != nil || endpoint.Host == "" {
+		return nil, errors.New("url must be an https URL")
+	}
+	if err := s.checkURL(endpoint); err != nil {
+		return nil, err
+	}
+
+	timeout := req.TimeoutSeconds
+	switch {
+	case timeout == 0:
+		timeout = connectorDefaultTimeout
+	case timeout < 0 || timeout > connectorMaxTimeout:
+		return nil, fmt.Errorf("timeout_seconds must be between 1 and %d", connectorMaxTimeout)
+	}
+
+	debounce := connectorDefaultDebounce
+	if req.DebounceMs != nil {
+		debounce = *req.DebounceMs
+	}
+	if debounce < 0 || debounce > connectorMaxDebounce {
+		return nil, fmt.Errorf("debounce_ms must be between 0 and %d", connectorMaxDebounce)
+	}
+
+	var token string
+	if req.AuthToken != nil {
+		token = *req.AuthToken
+	} else {
+		existing, err := s.repo.Get(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get connector: %w", err)
+		}
+		if existing != nil {
+			token = existing.AuthToken
+		}
+	}
+
+	c, err := s.repo.Save(&model.Connector{
+		SessionID:       sessionID,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+		URL:             endpoint.String(),
+		AuthToken:       token,
+		TimeoutSeconds:  timeout,
+		DebounceMs:      debounce,
+		FallbackMessage: req.FallbackMessage,
+		IgnoreGroups:    req.IgnoreGroups == nil || *req.IgnoreGroups,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save connector: %w", err)
+	}
+	return c, nil
+}
+
+func (s *ConnectorService) Delete(sessionID string) error {
+	deleted, err := s.repo.Delete(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete connector: %w", err)
+	}
+	if !deleted {
+		return ErrConnectorNotFound
+	}
+	return nil
+}
+
+// HandleMessage adds an incoming message to the pending batch of its chat.
+// The batch is sent when the debounce period of the connector passes without
+// another message, or at once when it is full.
+func (s *ConnectorService) HandleMessage(userID, sessionID string, evt *events.Message) {
+	if !answerable(evt) {
+		return
+	}
+
+	c, err := s.repo.Get(sessionID)
+	if err != nil {
+		logger.WarnComponent("connector").Str("session_id", sessionID).Err(err).Msg("failed to get connector")
+		return
+	}
+	if c == nil || !c.Enabled || (evt.Info.IsGroup && c.IgnoreGroups) {
+		return
+	}
+	if s.sessionService.BotPaused(sessionID, evt.Info.Chat.String()) {
+		return
+	}
+
+	s.enqueue(userID, sessionID, evt, time.Duration(c.DebounceMs)*time.Millisecond)
+}
+
+// enqueue adds a message to the pending batch of its chat and restarts the
+// debounce timer of the batch.
+func (s *ConnectorService) enqueue(userID, sessionID string, evt *events.Message, debounce time.Duration) {
+	key := sessionID + ":" + evt.Info.Chat.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.pending[key]
+	if b == nil {
+		b = &connectorBatch{key: key, userID: userID, sessionID: sessionID}
+		s.pending[key] = b
+		b.timer = time.AfterFunc(debounce, func() { s.flush(b) })
+	} else {
+		b.timer.Reset(debounce)
+	}
+	b.messages = append(b.messages, evt)
+
+	if len(b.messages) >= connectorMaxBatch {
+		b.timer.Stop()
+		delete(s.pending, key)
+		go s.dispatch(b)
+	}
+}
+
+// flush sends a batch whose debounce timer fired. A timer that fires for a
+// batch that was already sent is ignored.
+func (s *ConnectorService) flush(b *connectorBatch) {
+	s.mu.Lock()
+	if s.pending[b.key] != b {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.pending, b.key)
+	s.mu.Unlock()
+
+	s.dispatch(b)
+}
+
+// run calls the connector with a batch and runs the actions of its answer.
+// When the call fails the fallback message is sent instead.
+func (s *ConnectorService) run(b *connectorBatch) {
+	lock := s.lock(b.key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	c, err := s.repo.Get(b.sessionID)
+	if err != nil || c == nil || !c.Enabled {
+		return
+	}
+
+	last := b.messages[len(b.messages)-1]
+	chat := last.Info.Chat.String()
+
+	res, err := s.call(c, connectorCall(b))
+	if err == nil {
+		err = validateConnectorActions(res.Actions)
+	}
+	if err != nil {
+		logger.WarnComponent("connector").Str("session_id", b.sessionID).Str("chat", chat).Err(err).Msg("call failed")
+		s.sendFallback(b, c, chat)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectorActionTimeout)
+	defer cancel()
+
+	for i := range res.Actions {
+		if err := s.runAction(ctx, b, &res.Actions[i]); err != nil {
+			logger.WarnComponent("connector").Str("session_id", b.sessionID).Str("chat", chat).
+				Str("action", res.Actions[i].Type).Err(err).Msg("action failed")
+			return
+		}
+	}
+
+	logger.Component("connector").Str("session_id", b.sessionID).Str("chat", chat).
+		Int("messages", len(b.messages)).Int("actions", len(res.Actions)).Msg("call handled")
+}
+
+// checkURL only lets hosts allowed on private addresses use http.
+func (s *ConnectorService) checkURL(endpoint *url.URL) error {
+	if endpoint.Scheme == schemeHTTPS || (endpoint.Scheme == "http" && s.transport.allows(endpoint)) {
+		return nil
+	}
+	return errors.New("url must be an https URL, or http on a host in ALLOWED_PRIVATE_HOSTS")
+}
+
+// call posts a batch to the connector endpoint. An empty answer has no
+// actions.
+func (s *ConnectorService) call(c *model.Connector, body *model.ConnectorCall) (*model.ConnectorResponse, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal call: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	endpoint, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connector url: %w", err)
+	}
+	if err := s.checkURL(endpoint); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", connectorUserAgent)
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call connector: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("connector returned status %d", resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, connectorMaxResponse+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read connector response: %w", err)
+	}
+	if len(raw) > connectorMaxResponse {
+		return nil, errors.New("connector response is too large")
+	}
+
+	var res model.ConnectorResponse
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return &res, nil
+	}
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, fmt.Errorf("invalid connector response: %w", err)
+	}
+	return &res, nil
+}
+
+func (s *ConnectorService) runAction(ctx context.Context, b *connectorBatch, action *model.ConnectorAction) error {
+	last := b.messages[len(b.messages)-1]
+	chat := last.Info.Chat.String()
+
+	switch action.Type {
+	case model.ConnectorActionText:
+		req := &model.TextMessage{Phone: chat, Message: action.Text}
+		if action.Quote {
+			req.ReplyTo = &model.ReplyTo{
+				MessageID:   last.Info.ID,
+				Participant: last.Info.Sender.ToNonAD().String(),
+				Text:        wameow.MessageText(last),
+			}
+		}
+		_, err := s.messageService.SendText(ctx, b.userID, b.sessionID, req)
+		return err
+
+	case model.ConnectorActionMedia:
+		payload, err := json.Marshal(map[string]string{
+			"phone":          chat,
+			action.MediaType: action.URL,
+			"caption":        action.Caption,
+			"filename":       action.FileName,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = s.messageService.Send(ctx, b.userID, b.sessionID, action.MediaType, payload)
+		return err
+
+	case model.ConnectorActionReact:
+		messageID := action.MessageID
+		if messageID == "" {
+			messageID = last.Info.ID
+		}
+		_, err := s.messageService.React(ctx, b.userID, b.sessionID, &model.ReactionMessage{
+			Phone:     chat,
+			MessageID: messageID,
+			Emoji:     action.Emoji,
+		})
+		return err
+
+	case model.ConnectorActionRead:
+		return s.markRead(ctx, b)
+
+	case model.ConnectorActionTyping:
+		return s.typing(ctx, b.userID, b.sessionID, last.Info.Chat, time.Duration(action.DurationMs)*time.Millisecond)
+	}
+	return fmt.Errorf("unsupported action %q", action.Type)
+}
+
+// markRead marks the messages of a batch as read, grouped by sender as
+// required in group chats.
+func (s *ConnectorService) markRead(ctx context.Context, b *connectorBatch) error {
+	client := s.sessionService.GetWhatsmeowClient(b.userID, b.sessionID)
+	if client == nil {
+		return ErrNoSession
+	}
+
+	ids := make(map[types.JID][]types.MessageID)
+	var senders []types.JID
+	for _, evt := range b.messages {
+		sender := evt.Info.Sender.ToNonAD()
+		if _, ok := ids[sender]; !ok {
+			senders = append(senders, sender)
+		}
+		ids[sender] = append(ids[sender], evt.Info.ID)
+	}
+
+	chat := b.messages[0].Info.Chat
+	for _, sender := range senders {
+		if err := client.MarkRead(ctx, ids[sender], time.Now(), chat, sender); err != nil {
+			return fmt.Errorf("failed to mark as read: %w", err)
+		}
+	}
+	return nil
+}
+
+// typing shows the typing indicator in a chat for a while.
+func (s *ConnectorService) typing(ctx context.Context, userID, sessionID string, chat types.JID, d time.Duration) error {
+	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
+	if client == nil {
+		return ErrNoSession
+	}
+
+	if err := client.SendChatPresence(ctx, chat, types.ChatPresenceComposing, types.ChatPresenceMediaText); err != nil {
+		return fmt.Errorf("failed to send typing: %w", err)
+	}
+
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return client.SendChatPresence(ctx, chat, types.ChatPresencePaused, types.ChatPresenceMediaText)
+}
+
+func (s *ConnectorService) sendFallback(b *connectorBatch, c *model.Connector, chat string) {
+	if c.FallbackMessage == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectorActionTimeout)
+	defer cancel()
+
+	_, err := s.messageService.SendText(ctx, b.userID, b.sessionID, &model.TextMessage{Phone: chat, Message: c.FallbackMessage})
+	if err != nil {
+		logger.WarnComponent("connector").Str("session_id", b.sessionID).Str("chat", chat).Err(err).Msg("failed to send fallback")
+	}
+}
+
+func (s *ConnectorService) lock(key string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &s.locks[h.Sum32()%connectorLockStripes]
+}
+
+// connectorCall builds the body of a call for the messages of a batch.
+func connectorCall(b *connectorBatch) *model.ConnectorCall {
+	last := b.messages[len(b.messages)-1]
+	call := &model.ConnectorCall{
+		SessionID: b.sessionID,
+		Chat:      last.Info.Chat.String(),
+		IsGroup:   last.Info.IsGroup,
+		Messages:  make([]model.ConnectorMessage, 0, len(b.messages)),
+	}
+	if !last.Info.IsGroup {
+		call.Phone = senderPhone(last)
+		call.Name = last.Info.PushName
+	}
+
+	for _, evt := range b.messages {
+		call.Messages = append(call.Messages, model.ConnectorMessage{
+			ID:        evt.Info.ID,
+			Sender:    evt.Info.Sender.ToNonAD().String(),
+			Type:      wameow.MessageType(evt),
+			Text:      wameow.MessageText(evt),
+			ReplyID:   wameow.ReplyID(evt),
+			Timestamp: evt.Info.Timestamp.Unix(),
+		})
+	}
+	return call
+}
+
+// validateConnectorActions checks a whole answer before any of its actions
+// runs, so an invalid answer falls back instead of being half delivered.
+func validateConnectorActions(actions []model.ConnectorAction) error {
+	for i, action := range actions {
+		switch action.Type {
+		case model.ConnectorActionText:
+			if action.Text == "" {
+				return fmt.Errorf("actions[%d]: text is required", i)
+			}
+		case model.ConnectorActionMedia:
+			switch action.MediaType {
+			case SendKindImage, SendKindVideo, SendKindAudio, SendKindDocument, SendKindSticker:
+			default:
+				return fmt.Errorf("actions[%d]: invalid media_type %q", i, action.MediaType)
+			}
+			if action.URL == "" {
+				return fmt.Errorf("actions[%d]: url is required", i)
+			}
+		case model.ConnectorActionReact:
+			if action.Emoji == "" {
+				return fmt.Errorf("actions[%d]: emoji is required", i)
+			}
+		case model.ConnectorActionRead:
+		case model.ConnectorActionTyping:
+			if action.DurationMs < 0 || time.Duration(action.DurationMs)*time.Millisecond > connectorMaxTyping {
+				return fmt.Errorf("actions[%d]: duration_ms must be between 0 and %d", i, connectorMaxTyping.Milliseconds())
+			}
+		default:
+			return fmt.Errorf("actions[%d]: unsupported type %q", i, action.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+
+	"fiozap/internal/model"
+)
+
+func connectorTestMessage(chat types.JID, id, text string) *events.Message {
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+			ID:            id,
+			PushName:      "Maria",
+			Timestamp:     time.Unix(1700000000, 0),
+		},
+		Message: &waE2E.Message{Conversation: proto.String(text)},
+	}
+}
+
+func TestConnectorBatching(t *testing.T) {
+	alice := types.NewJID("5511999999999", types.DefaultUserServer)
+	bob := types.NewJID("5511888888888", types.DefaultUserServer)
+	const debounce = 30 * time.Millisecond
+
+	batches := make(chan *connectorBatch, 10)
+	s := &ConnectorService{pending: make(map[string]*connectorBatch)}
+	s.dispatch = func(b *connectorBatch) { batches <- b }
+
+	receive := func() *connectorBatch {
+		t.Helper()
+		select {
+		case b := <-batches:
+			return b
+		case <-time.After(time.Second):
+			t.Fatal("no batch was dispatched")
+			return nil
+		}
+	}
+	ids := func(b *connectorBatch) []string {
+		var ids []string
+		for _, evt := range b.messages {
+			ids = append(ids, evt.Info.ID)
+		}
+		return ids
+	}
+
+	t.Run("messages within the debounce are batched per chat", func(t *testing.T) {
+		s.enqueue("user", "session", connectorTestMessage(alice, "a1", "hi"), debounce)
+		s.enqueue("user", "session", connectorTestMessage(bob, "b1", "hello"), debounce)
+		time.Sleep(debounce / 2)
+		s.enqueue("user", "session", connectorTestMessage(alice, "a2", "are you there?"), debounce)
+
+		got := map[string][]string{}
+		for range 2 {
+			b := receive()
+			got[b.key] = ids(b)
+		}
+		if want := []string{"a1", "a2"}; !slices.Equal(got["session:"+alice.String()], want) {
+			t.Errorf("alice batch = %v, want %v", got["session:"+alice.String()], want)
+		}
+		if want := []string{"b1"}; !slices.Equal(got["session:"+bob.String()], want) {
+			t.Errorf("bob batch = %v, want %v", got["session:"+bob.String()], want)
+		}
+	})
+
+	t.Run("a full batch is sent at once", func(t *testing.T) {
+		for i := range connectorMaxBatch + 1 {
+			s.enqueue("user", "session", connectorTestMessage(alice, fmt.Sprintf("m%d", i), "hi"), time.Hour)
+		}
+
+		full := receive()
+		if len(full.messages) != connectorMaxBatch || full.messages[0].Info.ID != "m0" {
+			t.Fatalf("full batch = %v", ids(full))
+		}
+
+		s.mu.Lock()
+		rest := s.pending["session:"+alice.String()]
+		s.mu.Unlock()
+		if rest == nil || !slices.Equal(ids(rest), []string{fmt.Sprintf("m%d", connectorMaxBatch)}) {
+			t.Fatalf("pending batch after a full one = %v", rest)
+		}
+
+		// The timer of the batch that was sent must not send it again.
+		full.timer.Reset(0)
+		rest.timer.Reset(0)
+		if b := receive(); b != rest {
+			t.Errorf("dispatched %v, want the pending batch", ids(b))
+		}
+		select {
+		case b := <-batches:
+			t.Errorf("batch %v was dispatched twice", ids(b))
+		case <-time.After(2 * debounce):
+		}
+	})
+}
+
+func TestConnectorCall(t *testing.T) {
+	alice := types.NewJID("5511999999999", types.DefaultUserServer)
+	group := types.NewJID("120363000000000000", types.GroupServer)
+
+	first := connectorTestMessage(alice, "a1", "hi")
+	second := connectorTestMessage(alice, "a2", "menu")
+	call := connectorCall(&connectorBatch{sessionID: "session", messages: []*events.Message{first, second}})
+
+	if call.SessionID != "session" || call.Chat != alice.String() || call.IsGroup {
+		t.Errorf("call = %+v", call)
+	}
+	if call.Phone != "5511999999999" || call.Name != "Maria" {
+		t.Errorf("call phone = %q, name = %q", call.Phone, call.Name)
+	}
+	if len(call.Messages) != 2 || call.Messages[1].ID != "a2" || call.Messages[1].Text != "menu" || call.Messages[1].Timestamp != 1700000000 {
+		t.Errorf("call messages = %+v", call.Messages)
+	}
+
+	inGroup := connectorTestMessage(group, "g1", "hi")
+	inGroup.Info.IsGroup = true
+	inGroup.Info.Sender = alice
+	call = connectorCall(&connectorBatch{sessionID: "session", messages: []*events.Message{inGroup}})
+	if !call.IsGroup || call.Phone != "" || call.Name != "" || call.Messages[0].Sender != alice.String() {
+		t.Errorf("group call = %+v", call)
+	}
+}
+
+func TestConnectorCallGuard(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/bot", http.StatusTemporaryRedirect)
+			return
+		}
+		calls++
+		_, _ = w.Write([]byte(`{"actions":[{"type":"read"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		allowed     []string
+		url         string
+		wantErr     error
+		wantFailure bool
+	}{
+		{name: "allowed private host over http", allowed: []string{"localhost"}, url: localhostURL(server) + "/bot"},
+		{name: "private address is blocked", url: "https://" + server.Listener.Addr().String() + "/bot", wantErr: errBlockedAddress},
+		{name: "http is rejected", url: server.URL + "/bot", wantFailure: true},
+		{name: "redirect to http is rejected", allowed: []string{"localhost"}, url: localhostURL(server) + "/redirect", wantFailure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			s := NewConnectorService(nil, nil, nil, tt.allowed)
+			c := &model.Connector{URL: tt.url, TimeoutSeconds: 5}
+
+			res, err := s.call(c, &model.ConnectorCall{SessionID: "session"})
+			if tt.wantErr != nil || tt.wantFailure {
+				if err == nil {
+					t.Fatal("call() succeeded, want error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("call() error = %v, want %v", err, tt.wantErr)
+				}
+				if calls != 0 {
+					t.Errorf("connector received %d calls", calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("call() error = %v", err)
+			}
+			if calls != 1 || len(res.Actions) != 1 || res.Actions[0].Type != model.ConnectorActionRead {
+				t.Errorf("call() = %+v after %d calls", res, calls)
+			}
+		})
+	}
+}
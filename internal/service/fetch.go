@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	fetchDialTimeout  = 10 * time.Second
+	fetchMaxRedirects = 3
+	fetchUserAgent    = "FioZap/1.0"
+	schemeHTTPS       = "https"
+)
+
+var (
+	errBlockedAddress = errors.New("destination address is not allowed")
+	errMediaTooLarge  = errors.New("media exceeds maximum allowed size")
+)
+
+// blockedNetworks lists ranges that are not covered by the net.IP helpers but
+// must never be reachable from user-supplied URLs.
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+	"fc00::/7",
+)
+
+// fetcher downloads remote resources on behalf of API callers. Addresses are
+// checked after DNS resolution, so redirects and rebinding cannot reach
+// internal hosts.
+type fetcher struct {
+	client *http.Client
+}
+
+type fetchedResource struct {
+	Body     io.ReadCloser
+	MimeType string
+	FileName string
+	Size     int64
+}
+
+func newFetcher(timeout time.Duration) *fetcher {
+	return &fetcher{
+		client: &http.Client{
+			Transport: newPublicTransport(timeout),
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= fetchMaxRedirects {
+					return errors.New("too many redirects")
+				}
+				if req.URL.Scheme != schemeHTTPS {
+					return errors.New("redirect to non-https URL")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// newPublicTransport returns a transport that refuses to connect to private
+// addresses.
+func newPublicTransport(timeout time.Duration) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: fetchDialTimeout,
+		Control: blockPrivateAddress,
+	}
+
+	return &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   fetchDialTimeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+}
+
+// privateHostTransport sends requests to the hosts an administrator allowed
+// on private addresses through trusted, and all other requests through
+// public, which refuses private addresses.
+type privateHostTransport struct {
+	allowed map[string]bool
+	trusted http.RoundTripper
+	public  http.RoundTripper
+}
+
+func newPrivateHostTransport(allowedHosts []string, timeout time.Duration) *privateHostTransport {
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		allowed[strings.ToLower(host)] = true
+	}
+	return &privateHostTransport{
+		allowed: allowed,
+		trusted: http.DefaultTransport,
+		public:  newPublicTransport(timeout),
+	}
+}
+
+// allows reports whether u is on a host allowed on private addresses.
+func (t *privateHostTransport) allows(u *url.URL) bool {
+	return t.allowed[strings.ToLower(u.Hostname())]
+}
+
+func (t *privateHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.allows(req.URL) {
+		return t.trusted.RoundTrip(req)
+	}
+	return t.public.RoundTrip(req)
+}
+
+// Fetch opens rawURL and validates the response against the accepted
+// content-type prefixes and maxSize. The caller must close the returned body,
+// which fails with errMediaTooLarge once maxSize bytes have been read.
+func (f *fetcher) Fetch(ctx context.Context, rawURL string, accept []string, maxSize int64) (*fetchedResource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != schemeHTTPS || u.Host == "" {
+		return nil, errors.New("only https URLs are allowed")
+	}
+	if u.User != nil {
+		return nil, errors.New("URLs with credentials are not allowed")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", fetchUserAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u.Host, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("fetch returned status %d", resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxSize {
+		_ = resp.Body.Close()
+		return nil, errMediaTooLarge
+	}
+
+	mimeType := ""
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		if parsed, _, err := mime.ParseMediaType(ct); err == nil {
+			mimeType = parsed
+		}
+	}
+
+	if !acceptsContentType(mimeType, accept) {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unsupported content type %q", mimeType)
+	}
+
+	return &fetchedResource{
+		Body:     &limitedBody{ReadCloser: resp.Body, remaining: maxSize},
+		MimeType: mimeType,
+		FileName: path.Base(resp.Request.URL.Path),
+		Size:     resp.ContentLength,
+	}, nil
+}
+
+func acceptsContentType(mimeType string, accept []string) bool {
+	if len(accept) == 0 {
+		return true
+	}
+	for _, prefix := range accept {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedBody is an io.LimitReader that reports an error instead of a silent
+// EOF when the limit is exceeded.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		var probe [1]byte
+		if n, _ := l.ReadCloser.Read(probe[:]); n > 0 {
+			return 0, errMediaTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+func blockPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", errBlockedAddress, host)
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
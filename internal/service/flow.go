@@ -0,0 +1,518 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+	"go.yaml.in/yaml/v3"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+	"fiozap/internal/wameow"
+)
+
+const (
+	flowDefaultTimeout = 1800
+	flowMaxSteps       = 20
+	flowSendTimeout    = 60 * time.Second
+	flowLockStripes    = 64
+)
+
+var (
+	ErrFlowNotFound      = errors.New("flow not found")
+	ErrFlowStateNotFound = errors.New("conversation not found")
+)
+
+// FlowService runs the conversational flow of each session on its incoming
+// messages and keeps the state of every conversation.
+type FlowService struct {
+	repo           *repository.FlowRepository
+	messageService *MessageService
+	sessionService *SessionService
+	assignments    *AssignmentService
+	definitions    flowDefinitions
+	// locks serializes the messages of a chat, so quick replies do not race
+	// on its state.
+	locks [flowLockStripes]sync.Mutex
+}
+
+func NewFlowService(repo *repository.FlowRepository, messageService *MessageService, sessionService *SessionService) *FlowService {
+	return &FlowService{
+		repo:           repo,
+		messageService: messageService,
+		sessionService: sessionService,
+		definitions:    flowDefinitions{bySession: make(map[string]*compiledFlow)},
+	}
+}
+
+// SetAssignments makes handoffs queue the chat for an agent.
+func (s *FlowService) SetAssignments(assignments *AssignmentService) {
+	s.assignments = assignments
+}
+
+func (s *FlowService) Get(sessionID string) (*model.Flow, error) {
+	flow, err := s.repo.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow: %w", err)
+	}
+	if flow == nil {
+		return nil, ErrFlowNotFound
+	}
+	return flow, nil
+}
+
+// Save replaces the flow of a session with a definition given as JSON or
+// YAML. Running conversations continue in the new definition.
+func (s *FlowService) Save(sessionID string, body []byte, isYAML bool) (*model.Flow, error) {
+	def, err := parseFlowDefinition(body, isYAML)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFlow(def); err != nil {
+		return nil, err
+	}
+
+	enabled := def.Enabled == nil || *def.Enabled
+	def.Enabled = nil
+
+	definition, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+
+	flow, err := s.repo.Save(sessionID, definition, enabled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save flow: %w", err)
+	}
+	return flow, nil
+}
+
+func (s *FlowService) Delete(sessionID string) error {
+	deleted, err := s.repo.Delete(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete flow: %w", err)
+	}
+	if !deleted {
+		return ErrFlowNotFound
+	}
+	s.definitions.forget(sessionID)
+	return nil
+}
+
+func (s *FlowService) ListStates(sessionID, status string) ([]model.FlowState, error) {
+	return s.repo.ListStates(sessionID, status)
+}
+
+func (s *FlowService) GetState(sessionID, chat string) (*model.FlowState, error) {
+	jid, err := parseJID(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.repo.GetState(sessionID, jid.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if state == nil || stateExpired(state, time.Now()) {
+		return nil, ErrFlowStateNotFound
+	}
+	return state, nil
+}
+
+// Paused reports whether the flow handed a chat to a human, which pauses
+// every automated answer in it.
+func (s *FlowService) Paused(sessionID, chat string) bool {
+	state, err := s.repo.GetState(sessionID, chat)
+	if err != nil {
+		logger.WarnComponent("flow").Str("session_id", sessionID).Err(err).Msg("failed to get conversation")
+		return false
+	}
+	return state != nil && state.Status == model.FlowStatusHandoff && !stateExpired(state, time.Now())
+}
+
+// ResetState ends the conversation of a chat, which also hands it back from a
+// human to the flow. The next message of the contact starts over.
+func (s *FlowService) ResetState(sessionID, chat string) error {
+	jid, err := parseJID(chat)
+	if err != nil {
+		return err
+	}
+
+	lock := s.lock(sessionID, jid.String())
+	lock.Lock()
+	defer lock.Unlock()
+
+	deleted, err := s.repo.DeleteState(sessionID, jid.String())
+	if err != nil {
+		return fmt.Errorf("failed to reset conversation: %w", err)
+	}
+	if !deleted {
+		return ErrFlowStateNotFound
+	}
+	return nil
+}
+
+// HandleMessage moves the conversation of the chat of an incoming message
+// through the flow of the session.
+func (s *FlowService) HandleMessage(userID, sessionID string, evt *events.Message) {
+	if !answerable(evt) {
+		return
+	}
+
+	text := strings.TrimSpace(wameow.MessageText(evt))
+	replyID := wameow.ReplyID(evt)
+	if text == "" && replyID == "" {
+		return
+	}
+
+	flow, err := s.repo.Get(sessionID)
+	if err != nil {
+		logger.WarnComponent("flow").Str("session_id", sessionID).Err(err).Msg("failed to get flow")
+		return
+	}
+	if flow == nil || !flow.Enabled {
+		return
+	}
+
+	compiled, err := s.definitions.get(flow)
+	if err != nil {
+		logger.WarnComponent("flow").Str("session_id", sessionID).Err(err).Msg("invalid flow")
+		return
+	}
+	def := compiled.def
+
+	chat := evt.Info.Chat.String()
+	lock := s.lock(sessionID, chat)
+	lock.Lock()
+	defer lock.Unlock()
+
+	state, err := s.repo.GetState(sessionID, chat)
+	if err != nil {
+		logger.WarnComponent("flow").Str("session_id", sessionID).Err(err).Msg("failed to get conversation")
+		return
+	}
+	if state != nil && stateExpired(state, time.Now()) {
+		_, _ = s.repo.DeleteState(sessionID, chat)
+		state = nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flowSendTimeout)
+	defer cancel()
+
+	conv := &flowConversation{
+		service:   s,
+		ctx:       ctx,
+		userID:    userID,
+		sessionID: sessionID,
+		chat:      chat,
+		def:       def,
+		evt:       evt,
+		vars:      messageVars(evt, text),
+	}
+
+	switch {
+	case state == nil:
+		if flowTriggered(def, text, replyID) {
+			conv.enter(def.Start)
+		}
+	case state.Status == model.FlowStatusHandoff:
+		// A human is handling the chat.
+	default:
+		node, ok := def.States[state.State]
+		if !ok {
+			conv.enter(def.Start)
+			return
+		}
+		next := matchTransition(&node, text, replyID, compiled.regexps)
+		if next == "" {
+			conv.send(node.Invalid)
+			conv.save(state.State, model.FlowStatusActive, def.TimeoutSeconds)
+			return
+		}
+		conv.enter(next)
+	}
+}
+
+func (s *FlowService) lock(sessionID, chat string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(sessionID + ":" + chat))
+	return &s.locks[h.Sum32()%flowLockStripes]
+}
+
+// flowConversation moves one chat through a flow for an incoming message.
+type flowConversation struct {
+	service   *FlowService
+	ctx       context.Context
+	userID    string
+	sessionID string
+	chat      string
+	def       *model.FlowDefinition
+	evt       *events.Message
+	vars      map[string]string
+}
+
+// enter moves the conversation to a state, sending its message and following
+// its next states.
+func (c *flowConversation) enter(name string) {
+	for step := 0; step < flowMaxSteps; step++ {
+		node, ok := c.def.States[name]
+		if !ok {
+			logger.WarnComponent("flow").Str("session_id", c.sessionID).Str("state", name).Msg("unknown state")
+			return
+		}
+
+		c.send(node.Message)
+
+		switch {
+		case node.Handoff:
+			c.save(name, model.FlowStatusHandoff, c.def.HandoffTimeoutSeconds)
+			if c.service.assignments != nil {
+				c.service.assignments.queue(c.sessionID, c.chat)
+			}
+			c.service.sessionService.handleEvent(c.userID, c.sessionID, "FlowHandoff", map[string]interface{}{
+				"chat":      c.chat,
+				"state":     name,
+				"from":      c.evt.Info.Sender.String(),
+				"name":      c.evt.Info.PushName,
+				"text":      c.vars["text"],
+				"timestamp": c.evt.Info.Timestamp.Unix(),
+			})
+			return
+		case node.End:
+			if _, err := c.service.repo.DeleteState(c.sessionID, c.chat); err != nil {
+				logger.WarnComponent("flow").Str("session_id", c.sessionID).Err(err).Msg("failed to end conversation")
+			}
+			return
+		case node.Next != "":
+			name = node.Next
+		default:
+			c.save(name, model.FlowStatusActive, c.def.TimeoutSeconds)
+			return
+		}
+	}
+
+	logger.WarnComponent("flow").Str("session_id", c.sessionID).Str("state", name).Msg("too many steps")
+}
+
+func (c *flowConversation) send(msg *model.FlowMessage) {
+	if msg == nil {
+		return
+	}
+
+	payload, err := renderPayload(msg.Payload, c.chat, c.vars)
+	if err == nil {
+		_, err = c.service.messageService.Send(c.ctx, c.userID, c.sessionID, msg.Kind, payload)
+	}
+	if err != nil {
+		logger.WarnComponent("flow").Str("session_id", c.sessionID).Str("chat", c.chat).Err(err).Msg("send failed")
+	}
+}
+
+func (c *flowConversation) save(name, status string, timeoutSeconds int) {
+	var expiresAt *time.Time
+	if timeoutSeconds > 0 {
+		t := time.Now().Add(time.Duration(timeoutSeconds) * time.Second)
+		expiresAt = &t
+	}
+
+	if err := c.service.repo.SaveState(c.sessionID, c.chat, name, status, expiresAt); err != nil {
+		logger.WarnComponent("flow").Str("session_id", c.sessionID).Err(err).Msg("failed to save conversation")
+	}
+}
+
+func stateExpired(state *model.FlowState, now time.Time) bool {
+	return state.ExpiresAt != nil && now.After(*state.ExpiresAt)
+}
+
+func flowTriggered(def *model.FlowDefinition, text, replyID string) bool {
+	if len(def.Triggers) == 0 {
+		return true
+	}
+	for _, trigger := range def.Triggers {
+		if strings.EqualFold(trigger, text) || (replyID != "" && trigger == replyID) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTransition returns the state a message moves the conversation to, or
+// "" when it matches nothing. Regexes are looked up in the compiled regexes
+// of the flow.
+func matchTransition(node *model.FlowNode, text, replyID string, regexps map[string]*regexp.Regexp) string {
+	for _, t := range node.Transitions {
+		for _, m := range t.Match {
+			if strings.EqualFold(m, text) || (replyID != "" && m == replyID) {
+				return t.Next
+			}
+		}
+		if t.Regex != "" {
+			if re := regexps[t.Regex]; re != nil && re.MatchString(text) {
+				return t.Next
+			}
+		}
+	}
+	return node.Default
+}
+
+// flowDefinitions caches the decoded flow of each session with the regexes of
+// its transitions compiled, so incoming messages do not decode the flow and
+// compile its regexes again. An entry is replaced when the flow is updated.
+type flowDefinitions struct {
+	mu        sync.Mutex
+	bySession map[string]*compiledFlow
+}
+
+type compiledFlow struct {
+	updatedAt time.Time
+	def       *model.FlowDefinition
+	regexps   map[string]*regexp.Regexp
+}
+
+func (c *flowDefinitions) get(flow *model.Flow) (*compiledFlow, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.bySession[flow.SessionID]; ok && cached.updatedAt.Equal(flow.UpdatedAt) {
+		return cached, nil
+	}
+
+	var def model.FlowDefinition
+	if err := json.Unmarshal(flow.Definition, &def); err != nil {
+		return nil, err
+	}
+	regexps, err := compileFlowRegexps(&def)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := &compiledFlow{updatedAt: flow.UpdatedAt, def: &def, regexps: regexps}
+	c.bySession[flow.SessionID] = compiled
+	return compiled, nil
+}
+
+func (c *flowDefinitions) forget(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.bySession, sessionID)
+}
+
+// compileFlowRegexps compiles the regexes of the transitions of a flow, keyed
+// by their pattern.
+func compileFlowRegexps(def *model.FlowDefinition) (map[string]*regexp.Regexp, error) {
+	regexps := make(map[string]*regexp.Regexp)
+	for _, node := range def.States {
+		for _, t := range node.Transitions {
+			if t.Regex == "" || regexps[t.Regex] != nil {
+				continue
+			}
+			re, err := compileRule(t.Regex, false)
+			if err != nil {
+				return nil, err
+			}
+			regexps[t.Regex] = re
+		}
+	}
+	return regexps, nil
+}
+
+// parseFlowDefinition decodes a flow given as JSON or YAML. YAML is decoded
+// straight into the definition, so state names and match values written as
+// numbers are read as text.
+func parseFlowDefinition(body []byte, isYAML bool) (*model.FlowDefinition, error) {
+	var def model.FlowDefinition
+	if isYAML {
+		if err := yaml.Unmarshal(body, &def); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		return &def, nil
+	}
+
+	if err := json.Unmarshal(body, &def); err != nil {
+		return nil, fmt.Errorf("invalid flow: %w", err)
+	}
+	return &def, nil
+}
+
+// validateFlow checks that every state a flow refers to exists, that its
+// messages can be sent and that next states do not loop. It fills in the
+// default timeout.
+func validateFlow(def *model.FlowDefinition) error {
+	if len(def.States) == 0 {
+		return errors.New("states is required")
+	}
+	if _, ok := def.States[def.Start]; !ok {
+		return fmt.Errorf("start state %q does not exist", def.Start)
+	}
+	if def.TimeoutSeconds < 0 || def.HandoffTimeoutSeconds < 0 {
+		return errors.New("timeouts must not be negative")
+	}
+	if def.TimeoutSeconds == 0 {
+		def.TimeoutSeconds = flowDefaultTimeout
+	}
+
+	for name, node := range def.States {
+		targets := []string{node.Next, node.Default}
+		for i, t := range node.Transitions {
+			if len(t.Match) == 0 && t.Regex == "" {
+				return fmt.Errorf("state %q: transition %d needs match or regex", name, i)
+			}
+			if t.Regex != "" {
+				if _, err := compileRule(t.Regex, false); err != nil {
+					return fmt.Errorf("state %q: invalid regex: %w", name, err)
+				}
+			}
+			if t.Next == "" {
+				return fmt.Errorf("state %q: transition %d needs next", name, i)
+			}
+			targets = append(targets, t.Next)
+		}
+		for _, target := range targets {
+			if _, ok := def.States[target]; target != "" && !ok {
+				return fmt.Errorf("state %q: state %q does not exist", name, target)
+			}
+		}
+
+		if node.Next != "" && (node.Handoff || node.End) {
+			return fmt.Errorf("state %q: next cannot be combined with handoff or end", name)
+		}
+		if err := validateFlowMessage(node.Message); err != nil {
+			return fmt.Errorf("state %q: %w", name, err)
+		}
+		if err := validateFlowMessage(node.Invalid); err != nil {
+			return fmt.Errorf("state %q: invalid: %w", name, err)
+		}
+	}
+
+	for name := range def.States {
+		seen := map[string]bool{}
+		for current := name; current != ""; current = def.States[current].Next {
+			if seen[current] {
+				return fmt.Errorf("state %q: next states loop", name)
+			}
+			seen[current] = true
+		}
+	}
+
+	return nil
+}
+
+func validateFlowMessage(msg *model.FlowMessage) error {
+	if msg == nil {
+		return nil
+	}
+	payload, err := renderPayload(msg.Payload, "flow", nil)
+	if err != nil {
+		return err
+	}
+	return validateSendPayload(msg.Kind, payload)
+}
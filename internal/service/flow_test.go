@@ -0,0 +1,292 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"fiozap/internal/model"
+)
+
+const supportFlowYAML = `
+start: menu
+triggers: [hi, 0]
+states:
+  menu:
+    message:
+      kind: text
+      payload:
+        message: "1 - Billing\n2 - Human"
+    transitions:
+      - match: [1, billing]
+        next: 1
+      - match: 2
+        next: human
+    invalid:
+      kind: text
+      payload:
+        message: Choose 1 or 2
+  1:
+    message:
+      kind: location
+      payload:
+        latitude: -23.55
+        longitude: -46.63
+    end: true
+  human:
+    handoff: true
+`
+
+func TestParseFlowDefinition(t *testing.T) {
+	def, err := parseFlowDefinition([]byte(supportFlowYAML), true)
+	if err != nil {
+		t.Fatalf("parseFlowDefinition() error = %v", err)
+	}
+
+	if def.Start != "menu" {
+		t.Errorf("start = %q, want menu", def.Start)
+	}
+	if want := (model.FlowMatch{"hi", "0"}); !reflect.DeepEqual(def.Triggers, want) {
+		t.Errorf("triggers = %v, want %v", def.Triggers, want)
+	}
+
+	menu := def.States["menu"]
+	if len(menu.Transitions) != 2 {
+		t.Fatalf("menu has %d transitions, want 2", len(menu.Transitions))
+	}
+	if want := (model.FlowMatch{"1", "billing"}); !reflect.DeepEqual(menu.Transitions[0].Match, want) {
+		t.Errorf("match = %v, want %v", menu.Transitions[0].Match, want)
+	}
+	if menu.Transitions[0].Next != "1" {
+		t.Errorf("next = %q, want 1", menu.Transitions[0].Next)
+	}
+	if want := (model.FlowMatch{"2"}); !reflect.DeepEqual(menu.Transitions[1].Match, want) {
+		t.Errorf("scalar match = %v, want %v", menu.Transitions[1].Match, want)
+	}
+
+	billing, ok := def.States["1"]
+	if !ok {
+		t.Fatal("numeric state key 1 is missing")
+	}
+	var payload struct {
+		Latitude float64 `json:"latitude"`
+	}
+	if err := json.Unmarshal(billing.Message.Payload, &payload); err != nil || payload.Latitude != -23.55 {
+		t.Errorf("payload numbers must stay numbers, got %s", billing.Message.Payload)
+	}
+
+	if err := validateFlow(def); err != nil {
+		t.Errorf("validateFlow() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		yaml  bool
+		error string
+	}{
+		{"json with numeric match", `{"start":"a","states":{"a":{"transitions":[{"match":[1,true],"next":"a"}]}}}`, false, ""},
+		{"invalid yaml", "start: [", true, "invalid YAML"},
+		{"mapping in yaml match", "start: a\nstates:\n  a:\n    transitions:\n      - match: [{x: 1}]\n        next: a\n", true, "invalid YAML"},
+		{"invalid json", `{"start":`, false, "invalid flow"},
+		{"object in match", `{"start":"a","states":{"a":{"transitions":[{"match":[{}],"next":"a"}]}}}`, false, "invalid flow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFlowDefinition([]byte(tt.body), tt.yaml)
+			if tt.error == "" {
+				if err != nil {
+					t.Errorf("parseFlowDefinition() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("parseFlowDefinition() error = %v, want %q", err, tt.error)
+			}
+		})
+	}
+}
+
+func TestMatchTransition(t *testing.T) {
+	node := &model.FlowNode{
+		Transitions: []model.FlowTransition{
+			{Match: model.FlowMatch{"1", "Billing"}, Next: "billing"},
+			{Match: model.FlowMatch{"row-support"}, Next: "support"},
+			{Regex: `^cancel(ar)?$`, Next: "cancel"},
+		},
+		Default: "fallback",
+	}
+	strict := &model.FlowNode{Transitions: node.Transitions}
+
+	tests := []struct {
+		name    string
+		node    *model.FlowNode
+		text    string
+		replyID string
+		want    string
+	}{
+		{"exact match", node, "1", "", "billing"},
+		{"match ignores case", node, "BILLING", "", "billing"},
+		{"reply id", node, "Support", "row-support", "support"},
+		{"reply id is case sensitive", node, "", "ROW-SUPPORT", "fallback"},
+		{"regex ignores case", node, "Cancelar", "", "cancel"},
+		{"first transition wins", node, "1", "row-support", "billing"},
+		{"partial text does not match", node, "billing please", "", "fallback"},
+		{"no match uses default", node, "hello", "", "fallback"},
+		{"no match without default", strict, "hello", "", ""},
+	}
+
+	regexps, err := compileFlowRegexps(&model.FlowDefinition{States: map[string]model.FlowNode{"menu": *node}})
+	if err != nil {
+		t.Fatalf("compileFlowRegexps() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchTransition(tt.node, tt.text, tt.replyID, regexps); got != tt.want {
+				t.Errorf("matchTransition(%q, %q) = %q, want %q", tt.text, tt.replyID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateFlow(t *testing.T) {
+	text := &model.FlowMessage{Kind: "text", Payload: json.RawMessage(`{"message":"hi {{name}}"}`)}
+
+	tests := []struct {
+		name  string
+		def   model.FlowDefinition
+		error string
+	}{
+		{
+			name: "valid",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Message: text, Transitions: []model.FlowTransition{{Match: model.FlowMatch{"1"}, Next: "b"}}},
+				"b": {Message: text, Next: "c"},
+				"c": {End: true},
+			}},
+		},
+		{name: "no states", def: model.FlowDefinition{Start: "a"}, error: "states is required"},
+		{
+			name:  "missing start",
+			def:   model.FlowDefinition{Start: "x", States: map[string]model.FlowNode{"a": {}}},
+			error: `start state "x" does not exist`,
+		},
+		{
+			name:  "negative timeout",
+			def:   model.FlowDefinition{Start: "a", TimeoutSeconds: -1, States: map[string]model.FlowNode{"a": {}}},
+			error: "timeouts must not be negative",
+		},
+		{
+			name: "transition without match",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Transitions: []model.FlowTransition{{Next: "a"}}},
+			}},
+			error: "needs match or regex",
+		},
+		{
+			name: "invalid regex",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Transitions: []model.FlowTransition{{Regex: "(", Next: "a"}}},
+			}},
+			error: "invalid regex",
+		},
+		{
+			name: "transition without next",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Transitions: []model.FlowTransition{{Match: model.FlowMatch{"1"}}}},
+			}},
+			error: "needs next",
+		},
+		{
+			name: "unknown target",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Default: "missing"},
+			}},
+			error: `state "missing" does not exist`,
+		},
+		{
+			name: "next with end",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Next: "b", End: true}, "b": {},
+			}},
+			error: "next cannot be combined",
+		},
+		{
+			name: "invalid message kind",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Message: &model.FlowMessage{Kind: "fax", Payload: json.RawMessage(`{}`)}},
+			}},
+			error: "unsupported send kind",
+		},
+		{
+			name: "next states loop",
+			def: model.FlowDefinition{Start: "a", States: map[string]model.FlowNode{
+				"a": {Next: "b"}, "b": {Next: "a"},
+			}},
+			error: "next states loop",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFlow(&tt.def)
+			if tt.error == "" {
+				if err != nil {
+					t.Fatalf("validateFlow() error = %v", err)
+				}
+				if tt.def.TimeoutSeconds != flowDefaultTimeout {
+					t.Errorf("timeout = %d, want default %d", tt.def.TimeoutSeconds, flowDefaultTimeout)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("validateFlow() error = %v, want %q", err, tt.error)
+			}
+		})
+	}
+}
+
+func TestFlowDefinitionsCache(t *testing.T) {
+	updated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	flow := &model.Flow{
+		SessionID:  "session",
+		Definition: json.RawMessage(`{"start":"a","states":{"a":{"transitions":[{"regex":"^sim$","next":"a"}]}}}`),
+		UpdatedAt:  updated,
+	}
+	cache := flowDefinitions{bySession: make(map[string]*compiledFlow)}
+
+	first, err := cache.get(flow)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if re := first.regexps["^sim$"]; re == nil || !re.MatchString("SIM") {
+		t.Fatalf("regexps = %v, want a case-insensitive ^sim$", first.regexps)
+	}
+	if again, _ := cache.get(flow); again != first {
+		t.Error("get() decoded an unchanged flow again")
+	}
+
+	flow.Definition = json.RawMessage(`{"start":"b","states":{"b":{}}}`)
+	flow.UpdatedAt = updated.Add(time.Second)
+	changed, err := cache.get(flow)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if changed == first || changed.def.Start != "b" {
+		t.Errorf("get() kept the flow from before the update")
+	}
+
+	cache.forget("session")
+	if _, ok := cache.bySession["session"]; ok {
+		t.Error("forget() kept the flow")
+	}
+
+	flow.Definition = json.RawMessage(`{"start":"a","states":{"a":{"transitions":[{"regex":"(","next":"a"}]}}}`)
+	flow.UpdatedAt = updated.Add(2 * time.Second)
+	if _, err := cache.get(flow); err == nil {
+		t.Error("get() accepted an invalid regex")
+	}
+}
@@ -22,7 +22,7 @@ func NewGroupService(sessionService *SessionService) *GroupService {
 func (s *GroupService) Create(ctx context.Context, userID, sessionID string, name string, participants []string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	var jids []types.JID
@@ -53,7 +53,7 @@ func (s *GroupService) Create(ctx context.Context, userID, sessionID string, nam
 func (s *GroupService) List(ctx context.Context, userID, sessionID string) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	groups, err := client.GetJoinedGroups(ctx)
@@ -79,7 +79,7 @@ func (s *GroupService) List(ctx context.Context, userID, sessionID string) ([]ma
 func (s *GroupService) GetInfo(ctx context.Context, userID, sessionID string, groupJID string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -116,7 +116,7 @@ func (s *GroupService) GetInfo(ctx context.Context, userID, sessionID string, gr
 func (s *GroupService) GetInviteLink(ctx context.Context, userID, sessionID string, groupJID string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -137,7 +137,7 @@ func (s *GroupService) GetInviteLink(ctx context.Context, userID, sessionID stri
 func (s *GroupService) Leave(ctx context.Context, userID, sessionID string, groupJID string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -151,7 +151,7 @@ func (s *GroupService) Leave(ctx context.Context, userID, sessionID string, grou
 func (s *GroupService) UpdateParticipants(ctx context.Context, userID, sessionID string, groupJID string, participants []string, action string) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -201,7 +201,7 @@ func (s *GroupService) UpdateParticipants(ctx context.Context, userID, sessionID
 func (s *GroupService) SetName(ctx context.Context, userID, sessionID string, groupJID, name string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -215,7 +215,7 @@ func (s *GroupService) SetName(ctx context.Context, userID, sessionID string, gr
 func (s *GroupService) SetTopic(ctx context.Context, userID, sessionID string, groupJID, topic string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -229,7 +229,7 @@ func (s *GroupService) SetTopic(ctx context.Context, userID, sessionID string, g
 func (s *GroupService) SetPhoto(ctx context.Context, userID, sessionID string, groupJID string, imageData []byte) (string, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return "", errors.New("no session")
+		return "", ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -248,7 +248,7 @@ func (s *GroupService) SetPhoto(ctx context.Context, userID, sessionID string, g
 func (s *GroupService) RemovePhoto(ctx context.Context, userID, sessionID string, groupJID string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -267,7 +267,7 @@ func (s *GroupService) RemovePhoto(ctx context.Context, userID, sessionID string
 func (s *GroupService) SetAnnounce(ctx context.Context, userID, sessionID string, groupJID string, announce bool) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -281,7 +281,7 @@ func (s *GroupService) SetAnnounce(ctx context.Context, userID, sessionID string
 func (s *GroupService) SetLocked(ctx context.Context, userID, sessionID string, groupJID string, locked bool) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := types.ParseJID(groupJID)
@@ -295,7 +295,7 @@ func (s *GroupService) SetLocked(ctx context.Context, userID, sessionID string,
 func (s *GroupService) SetEphemeral(ctx context.Context, userID, sessionID string, groupJID string, duration string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	_, err := types.ParseJID(groupJID)
@@ -323,7 +323,7 @@ func (s *GroupService) SetEphemeral(ctx context.Context, userID, sessionID strin
 func (s *GroupService) Join(ctx context.Context, userID, sessionID string, code string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	groupJID, err := client.JoinGroupWithLink(ctx, code)
@@ -339,7 +339,7 @@ func (s *GroupService) Join(ctx context.Context, userID, sessionID string, code
 func (s *GroupService) GetInviteInfo(ctx context.Context, userID, sessionID string, code string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	info, err := client.GetGroupInfoFromLink(ctx, code)
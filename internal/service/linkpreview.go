@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vincent-petithory/dataurl"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"google.golang.org/protobuf/proto"
+
+	"fiozap/internal/model"
+)
+
+const (
+	linkPreviewTimeout   = 10 * time.Second
+	linkPreviewMaxHTML   = 512 << 10
+	linkPreviewMaxImage  = 5 << 20
+	linkPreviewMaxPixels = 25_000_000
+	linkPreviewThumbSize = 256
+	linkPreviewQuality   = 80
+)
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// linkPreview is the preview card attached to an extended text message.
+type linkPreview struct {
+	matchedText string
+	title       string
+	description string
+	thumbnail   []byte
+}
+
+func (p *linkPreview) apply(msg *waE2E.ExtendedTextMessage) {
+	msg.MatchedText = proto.String(p.matchedText)
+	msg.Title = proto.String(p.title)
+	msg.Description = proto.String(p.description)
+	msg.PreviewType = waE2E.ExtendedTextMessage_NONE.Enum()
+	if len(p.thumbnail) > 0 {
+		msg.JPEGThumbnail = p.thumbnail
+	}
+}
+
+// buildLinkPreview returns the preview for the first URL of text. Explicit
+// preview fields are used as given; otherwise the page is fetched and its
+// OpenGraph tags are read. It returns nil when the text has no URL.
+func (s *MessageService) buildLinkPreview(ctx context.Context, text string, explicit *model.LinkPreview) (*linkPreview, error) {
+	matched := urlPattern.FindString(text)
+
+	if explicit != nil {
+		preview := &linkPreview{
+			matchedText: explicit.URL,
+			title:       explicit.Title,
+			description: explicit.Description,
+		}
+		if preview.matchedText == "" {
+			preview.matchedText = matched
+		}
+		if explicit.Thumbnail != "" {
+			data, err := dataurl.DecodeString(explicit.Thumbnail)
+			if err != nil {
+				return nil, errors.New("invalid base64 preview thumbnail")
+			}
+			thumb, err := makeThumbnail(data.Data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid preview thumbnail: %w", err)
+			}
+			preview.thumbnail = thumb
+		}
+		return preview, nil
+	}
+
+	if matched == "" {
+		return nil, nil
+	}
+
+	pageURL, err := url.Parse(matched)
+	if err != nil || pageURL.Scheme != schemeHTTPS {
+		return nil, errors.New("only https links can be previewed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, linkPreviewTimeout)
+	defer cancel()
+
+	res, err := s.fetcher.Fetch(ctx, matched, []string{"text/html", "application/xhtml+xml"}, s.maxMediaSize)
+	if err != nil {
+		return nil, err
+	}
+	meta := parseOpenGraph(io.LimitReader(res.Body, linkPreviewMaxHTML))
+	_ = res.Body.Close()
+
+	preview := &linkPreview{
+		matchedText: matched,
+		title:       meta.title,
+		description: meta.description,
+	}
+
+	if meta.image != "" {
+		if imageURL, err := pageURL.Parse(meta.image); err == nil && imageURL.Scheme == schemeHTTPS {
+			preview.thumbnail, _ = s.fetchThumbnail(ctx, imageURL.String())
+		}
+	}
+
+	return preview, nil
+}
+
+func (s *MessageService) fetchThumbnail(ctx context.Context, imageURL string) ([]byte, error) {
+	res, err := s.fetcher.Fetch(ctx, imageURL, []string{"image/"}, linkPreviewMaxImage)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeThumbnail(data)
+}
+
+// makeThumbnail decodes an image and re-encodes it as a small JPEG. Images
+// with absurd dimensions are rejected before decoding the pixel data.
+func makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > linkPreviewMaxPixels {
+		return nil, errors.New("image is too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleImage(img, linkPreviewThumbSize), &jpeg.Options{Quality: linkPreviewQuality}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// scaleImage shrinks img with nearest-neighbour sampling so that neither side
+// exceeds maxSide.
+func scaleImage(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	if w >= h {
+		w, h = maxSide, max(1, h*maxSide/w)
+	} else {
+		w, h = max(1, w*maxSide/h), maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dst.Set(x, y, img.At(b.Min.X+x*b.Dx()/w, b.Min.Y+y*b.Dy()/h))
+		}
+	}
+
+	return dst
+}
+
+type pageMeta struct {
+	title       string
+	description string
+	image       string
+}
+
+// parseOpenGraph reads the OpenGraph tags of a page head, falling back to the
+// title element and the description meta tag.
+func parseOpenGraph(r io.Reader) pageMeta {
+	var (
+		meta      pageMeta
+		fallback  pageMeta
+		inTitle   bool
+		tokenizer = html.NewTokenizer(r)
+	)
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return meta.withFallback(fallback)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.DataAtom {
+			case atom.Body:
+				return meta.withFallback(fallback)
+			case atom.Title:
+				inTitle = true
+			case atom.Meta:
+				key, content := metaAttrs(token)
+				switch key {
+				case "og:title":
+					meta.title = content
+				case "og:description":
+					meta.description = content
+				case "og:image", "og:image:secure_url":
+					if meta.image == "" {
+						meta.image = content
+					}
+				case "twitter:image":
+					fallback.image = content
+				case "description":
+					fallback.description = content
+				}
+			}
+
+		case html.TextToken:
+			if inTitle && fallback.title == "" {
+				fallback.title = strings.TrimSpace(string(tokenizer.Text()))
+			}
+
+		case html.EndTagToken:
+			if tokenizer.Token().DataAtom == atom.Head {
+				return meta.withFallback(fallback)
+			}
+			inTitle = false
+		}
+	}
+}
+
+func (m pageMeta) withFallback(f pageMeta) pageMeta {
+	if m.title == "" {
+		m.title = f.title
+	}
+	if m.description == "" {
+		m.description = f.description
+	}
+	if m.image == "" {
+		m.image = f.image
+	}
+	return m
+}
+
+func metaAttrs(token html.Token) (key, content string) {
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(attr.Val)
+			}
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+	return key, content
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOpenGraph(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want pageMeta
+	}{
+		{
+			name: "opengraph tags",
+			html: `<html><head>
+				<meta property="og:title" content=" FioZap ">
+				<meta property="og:description" content="WhatsApp API">
+				<meta property="og:image" content="https://example.com/a.png">
+				</head><body></body></html>`,
+			want: pageMeta{title: "FioZap", description: "WhatsApp API", image: "https://example.com/a.png"},
+		},
+		{
+			name: "fallback to title and description",
+			html: `<html><head><title> Page title </title>
+				<meta name="description" content="Plain description">
+				<meta name="twitter:image" content="https://example.com/t.png">
+				</head></html>`,
+			want: pageMeta{title: "Page title", description: "Plain description", image: "https://example.com/t.png"},
+		},
+		{
+			name: "opengraph wins over fallback",
+			html: `<head><title>Fallback</title>
+				<meta name="description" content="Fallback description">
+				<meta property="og:title" content="OG title">
+				<meta property="og:description" content="OG description">
+				</head>`,
+			want: pageMeta{title: "OG title", description: "OG description"},
+		},
+		{
+			name: "first image is kept",
+			html: `<head>
+				<meta property="og:image" content="https://example.com/1.png">
+				<meta property="og:image:secure_url" content="https://example.com/2.png">
+				</head>`,
+			want: pageMeta{image: "https://example.com/1.png"},
+		},
+		{
+			name: "case insensitive keys",
+			html: `<head><meta property="OG:TITLE" content="Upper"></head>`,
+			want: pageMeta{title: "Upper"},
+		},
+		{
+			name: "body is not read",
+			html: `<head></head><body><meta property="og:title" content="In body"></body>`,
+			want: pageMeta{},
+		},
+		{
+			name: "empty document",
+			html: ``,
+			want: pageMeta{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseOpenGraph(strings.NewReader(tt.html)); got != tt.want {
+				t.Errorf("parseOpenGraph() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
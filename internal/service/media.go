@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/vincent-petithory/dataurl"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+
+	"fiozap/internal/model"
+)
+
+const sniffLen = 512
+
+var (
+	ErrMediaNotFound = errors.New("message not found")
+	ErrInvalidMedia  = errors.New("invalid media request")
+)
+
+type mediaKind struct {
+	name       string
+	dataPrefix string
+	accept     []string
+	mediaType  whatsmeow.MediaType
+}
+
+var (
+	mediaImage    = mediaKind{"image", "data:image", []string{"image/"}, whatsmeow.MediaImage}
+	mediaAudio    = mediaKind{"audio", "data:audio", []string{"audio/", "application/ogg"}, whatsmeow.MediaAudio}
+	mediaVideo    = mediaKind{"video", "data:video", []string{"video/"}, whatsmeow.MediaVideo}
+	mediaDocument = mediaKind{"document", "data:", nil, whatsmeow.MediaDocument}
+	mediaSticker  = mediaKind{"sticker", "data:image", []string{"image/webp"}, whatsmeow.MediaImage}
+)
+
+// AcceptsMediaType reports whether mimeType is accepted for media of the
+// named kind: image, audio, video, document or sticker.
+func AcceptsMediaType(kind, mimeType string) bool {
+	for _, k := range []mediaKind{mediaImage, mediaAudio, mediaVideo, mediaDocument, mediaSticker} {
+		if k.name == kind {
+			return acceptsContentType(mimeType, k.accept)
+		}
+	}
+	return false
+}
+
+// MaxMediaSize returns the largest media file accepted for sending, in bytes.
+func (s *MessageService) MaxMediaSize() int64 {
+	return s.maxMediaSize
+}
+
+// mediaInput is the media payload of a send request, either fully decoded in
+// memory (data URLs) or streamed from a URL fetch or multipart upload.
+type mediaInput struct {
+	data     []byte
+	reader   io.Reader
+	mimeType string
+	fileName string
+	closer   io.Closer
+}
+
+func (m *mediaInput) Close() {
+	if m.closer != nil {
+		_ = m.closer.Close()
+	}
+}
+
+// detectMimeType returns the declared mime type, falling back to sniffing the
+// first bytes of the content.
+func (m *mediaInput) detectMimeType() string {
+	if m.mimeType != "" {
+		return m.mimeType
+	}
+	if m.data != nil {
+		return http.DetectContentType(m.data)
+	}
+
+	br := bufio.NewReaderSize(m.reader, sniffLen)
+	head, _ := br.Peek(sniffLen)
+	m.reader = br
+	return http.DetectContentType(head)
+}
+
+// openMedia resolves the media of a send request from a multipart upload, a
+// data URL or an https URL, in that order.
+func (s *MessageService) openMedia(ctx context.Context, value string, upload *model.MediaUpload, kind mediaKind) (*mediaInput, error) {
+	if upload != nil {
+		return &mediaInput{
+			reader:   &limitedBody{ReadCloser: io.NopCloser(upload.Reader), remaining: s.maxMediaSize},
+			mimeType: upload.MimeType,
+			fileName: upload.FileName,
+		}, nil
+	}
+
+	switch {
+	case strings.HasPrefix(value, kind.dataPrefix):
+		dataURL, err := dataurl.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 %s data", kind.name)
+		}
+		return &mediaInput{data: dataURL.Data}, nil
+
+	case strings.HasPrefix(value, schemeHTTPS+"://"):
+		res, err := s.fetcher.Fetch(ctx, value, kind.accept, s.maxMediaSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", kind.name, err)
+		}
+		return &mediaInput{
+			reader:   res.Body,
+			mimeType: res.MimeType,
+			fileName: res.FileName,
+			closer:   res.Body,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%s must be a base64 data URL (%s...) or an https URL", kind.name, kind.dataPrefix)
+	}
+}
+
+// uploadMedia encrypts and uploads the media to WhatsApp. Streamed input is
+// passed to UploadReader so it never has to be held in memory.
+func (s *MessageService) uploadMedia(ctx context.Context, client *whatsmeow.Client, in *mediaInput, kind mediaKind) (whatsmeow.UploadResponse, error) {
+	var (
+		uploaded whatsmeow.UploadResponse
+		err      error
+	)
+
+	if in.data != nil {
+		uploaded, err = client.Upload(ctx, in.data, kind.mediaType)
+	} else {
+		uploaded, err = client.UploadReader(ctx, in.reader, nil, kind.mediaType)
+	}
+	if err != nil {
+		if errors.Is(err, errMediaTooLarge) {
+			return uploaded, errMediaTooLarge
+		}
+		return uploaded, fmt.Errorf("failed to upload %s: %w", kind.name, err)
+	}
+
+	return uploaded, nil
+}
+
+// MediaFile is a decrypted media file buffered on disk so it can be streamed
+// with a known length. Close removes the underlying temporary file.
+type MediaFile struct {
+	*os.File
+	MimeType string
+	FileName string
+	Size     int64
+}
+
+func (f *MediaFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.File.Name())
+	return err
+}
+
+// downloadTarget is a media message resolved from a download request.
+type downloadTarget struct {
+	message  whatsmeow.DownloadableMessage
+	kind     string
+	mimeType string
+	fileName string
+}
+
+// resolveDownload builds the downloadable message either from a stored
+// message id or from the media descriptor fields of the request.
+func (s *MessageService) resolveDownload(sessionID string, req *model.DownloadMediaMessage) (*downloadTarget, error) {
+	if req.MessageID != "" {
+		return s.resolveStoredDownload(sessionID, req.MessageID)
+	}
+
+	if req.DirectPath == "" && req.URL == "" {
+		return nil, fmt.Errorf("%w: url or direct_path is required", ErrInvalidMedia)
+	}
+
+	target := &downloadTarget{kind: req.Type, mimeType: req.MimeType}
+	switch req.Type {
+	case mediaImage.name:
+		target.message = &waE2E.ImageMessage{
+			URL: proto.String(req.URL), DirectPath: proto.String(req.DirectPath), MediaKey: req.MediaKey,
+			Mimetype: proto.String(req.MimeType), FileEncSHA256: req.FileEncSHA256, FileSHA256: req.FileSHA256, FileLength: &req.FileLength,
+		}
+	case mediaVideo.name:
+		target.message = &waE2E.VideoMessage{
+			URL: proto.String(req.URL), DirectPath: proto.String(req.DirectPath), MediaKey: req.MediaKey,
+			Mimetype: proto.String(req.MimeType), FileEncSHA256: req.FileEncSHA256, FileSHA256: req.FileSHA256, FileLength: &req.FileLength,
+		}
+	case mediaAudio.name:
+		target.message = &waE2E.AudioMessage{
+			URL: proto.String(req.URL), DirectPath: proto.String(req.DirectPath), MediaKey: req.MediaKey,
+			Mimetype: proto.String(req.MimeType), FileEncSHA256: req.FileEncSHA256, FileSHA256: req.FileSHA256, FileLength: &req.FileLength,
+		}
+	case mediaDocument.name:
+		target.message = &waE2E.DocumentMessage{
+			URL: proto.String(req.URL), DirectPath: proto.String(req.DirectPath), MediaKey: req.MediaKey,
+			Mimetype: proto.String(req.MimeType), FileEncSHA256: req.FileEncSHA256, FileSHA256: req.FileSHA256, FileLength: &req.FileLength,
+		}
+	case mediaSticker.name:
+		target.message = &waE2E.StickerMessage{
+			URL: proto.String(req.URL), DirectPath: proto.String(req.DirectPath), MediaKey: req.MediaKey,
+			Mimetype: proto.String(req.MimeType), FileEncSHA256: req.FileEncSHA256, FileSHA256: req.FileSHA256, FileLength: &req.FileLength,
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported media type %q", ErrInvalidMedia, req.Type)
+	}
+
+	return target, nil
+}
+
+func (s *MessageService) resolveStoredDownload(sessionID, messageID string) (*downloadTarget, error) {
+	if s.messageRepo == nil {
+		return nil, fmt.Errorf("%w: message store is disabled", ErrInvalidMedia)
+	}
+
+	stored, err := s.messageRepo.GetByID(sessionID, messageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	var msg waE2E.Message
+	if err := proto.Unmarshal(stored.Raw, &msg); err != nil {
+		return nil, fmt.Errorf("failed to decode stored message: %w", err)
+	}
+
+	switch {
+	case msg.ImageMessage != nil:
+		return &downloadTarget{msg.ImageMessage, mediaImage.name, msg.ImageMessage.GetMimetype(), ""}, nil
+	case msg.VideoMessage != nil:
+		return &downloadTarget{msg.VideoMessage, mediaVideo.name, msg.VideoMessage.GetMimetype(), ""}, nil
+	case msg.AudioMessage != nil:
+		return &downloadTarget{msg.AudioMessage, mediaAudio.name, msg.AudioMessage.GetMimetype(), ""}, nil
+	case msg.DocumentMessage != nil:
+		return &downloadTarget{msg.DocumentMessage, mediaDocument.name, msg.DocumentMessage.GetMimetype(), msg.DocumentMessage.GetFileName()}, nil
+	case msg.StickerMessage != nil:
+		return &downloadTarget{msg.StickerMessage, mediaSticker.name, msg.StickerMessage.GetMimetype(), ""}, nil
+	default:
+		return nil, fmt.Errorf("%w: message has no downloadable media", ErrInvalidMedia)
+	}
+}
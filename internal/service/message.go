@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -16,22 +17,44 @@ import (
 	"go.mau.fi/whatsmeow/types"
 	"google.golang.org/protobuf/proto"
 
+	"fiozap/internal/config"
+	"fiozap/internal/database/repository"
 	"fiozap/internal/logger"
 	"fiozap/internal/model"
 )
 
 type MessageService struct {
 	sessionService *SessionService
+	fetcher        *fetcher
+	maxMediaSize   int64
+	messageRepo    *repository.MessageRepository
+	sendQueue      *SendQueue
 }
 
-func NewMessageService(sessionService *SessionService) *MessageService {
-	return &MessageService{sessionService: sessionService}
+func NewMessageService(sessionService *SessionService, cfg *config.Config) *MessageService {
+	return &MessageService{
+		sessionService: sessionService,
+		fetcher:        newFetcher(cfg.MediaFetchTimeout),
+		maxMediaSize:   cfg.MediaMaxSize,
+	}
+}
+
+func (s *MessageService) SetMessageRepo(repo *repository.MessageRepository) {
+	s.messageRepo = repo
+}
+
+func (s *MessageService) SetSendQueue(q *SendQueue) {
+	s.sendQueue = q
 }
 
 func (s *MessageService) SendText(ctx context.Context, userID, sessionID string, req *model.TextMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		return s.enqueue(userID, sessionID, SendKindText, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -44,10 +67,42 @@ func (s *MessageService) SendText(ctx context.Context, userID, sessionID string,
 		msgID = client.GenerateMessageID()
 	}
 
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var preview *linkPreview
+	if req.LinkPreview || req.Preview != nil {
+		preview, err = s.buildLinkPreview(ctx, req.Message, req.Preview)
+		if err != nil {
+			if req.Preview != nil {
+				return nil, err
+			}
+			logger.Warnf("Link preview skipped for %s: %v", msgID, err)
+		}
+	}
+
 	msg := &waE2E.Message{
 		Conversation: proto.String(req.Message),
 	}
 
+	// Quotes, mentions and previews are only carried by extended text messages.
+	if contextInfo != nil || preview != nil {
+		extended := &waE2E.ExtendedTextMessage{
+			Text:        proto.String(req.Message),
+			ContextInfo: contextInfo,
+		}
+		if preview != nil {
+			preview.apply(extended)
+		}
+		msg = &waE2E.Message{ExtendedTextMessage: extended}
+	}
+
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Message, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send message: %w", err)
@@ -62,9 +117,16 @@ func (s *MessageService) SendText(ctx context.Context, userID, sessionID string,
 }
 
 func (s *MessageService) SendImage(ctx context.Context, userID, sessionID string, req *model.ImageMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		if req.Upload != nil {
+			return nil, errUploadNotQueueable
+		}
+		return s.enqueue(userID, sessionID, SendKindImage, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -77,25 +139,25 @@ func (s *MessageService) SendImage(ctx context.Context, userID, sessionID string
 		msgID = client.GenerateMessageID()
 	}
 
-	var filedata []byte
-	if strings.HasPrefix(req.Image, "data:image") {
-		dataURL, err := dataurl.DecodeString(req.Image)
-		if err != nil {
-			return nil, errors.New("invalid base64 image data")
-		}
-		filedata = dataURL.Data
-	} else {
-		return nil, errors.New("image must be base64 encoded (data:image/...)")
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, filedata, whatsmeow.MediaImage)
+	media, err := s.openMedia(ctx, req.Image, req.Upload, mediaImage)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload image: %w", err)
+		return nil, err
 	}
+	defer media.Close()
 
 	mimeType := req.MimeType
 	if mimeType == "" {
-		mimeType = http.DetectContentType(filedata)
+		mimeType = media.detectMimeType()
+	}
+
+	uploaded, err := s.uploadMedia(ctx, client, media, mediaImage)
+	if err != nil {
+		return nil, err
 	}
 
 	msg := &waE2E.Message{
@@ -107,10 +169,15 @@ func (s *MessageService) SendImage(ctx context.Context, userID, sessionID string
 			Mimetype:      proto.String(mimeType),
 			FileEncSHA256: uploaded.FileEncSHA256,
 			FileSHA256:    uploaded.FileSHA256,
-			FileLength:    proto.Uint64(uint64(len(filedata))),
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			ContextInfo:   contextInfo,
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Caption, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send image: %w", err)
@@ -123,9 +190,16 @@ func (s *MessageService) SendImage(ctx context.Context, userID, sessionID string
 }
 
 func (s *MessageService) SendAudio(ctx context.Context, userID, sessionID string, req *model.AudioMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		if req.Upload != nil {
+			return nil, errUploadNotQueueable
+		}
+		return s.enqueue(userID, sessionID, SendKindAudio, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -138,21 +212,16 @@ func (s *MessageService) SendAudio(ctx context.Context, userID, sessionID string
 		msgID = client.GenerateMessageID()
 	}
 
-	var filedata []byte
-	if strings.HasPrefix(req.Audio, "data:audio") {
-		dataURL, err := dataurl.DecodeString(req.Audio)
-		if err != nil {
-			return nil, errors.New("invalid base64 audio data")
-		}
-		filedata = dataURL.Data
-	} else {
-		return nil, errors.New("audio must be base64 encoded (data:audio/...)")
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, filedata, whatsmeow.MediaAudio)
+	media, err := s.openMedia(ctx, req.Audio, req.Upload, mediaAudio)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload audio: %w", err)
+		return nil, err
 	}
+	defer media.Close()
 
 	ptt := true
 	if req.PTT != nil {
@@ -161,13 +230,21 @@ func (s *MessageService) SendAudio(ctx context.Context, userID, sessionID string
 
 	mimeType := req.MimeType
 	if mimeType == "" {
-		if ptt {
+		switch {
+		case ptt:
 			mimeType = "audio/ogg; codecs=opus"
-		} else {
+		case media.mimeType != "":
+			mimeType = media.mimeType
+		default:
 			mimeType = "audio/mpeg"
 		}
 	}
 
+	uploaded, err := s.uploadMedia(ctx, client, media, mediaAudio)
+	if err != nil {
+		return nil, err
+	}
+
 	msg := &waE2E.Message{
 		AudioMessage: &waE2E.AudioMessage{
 			URL:           proto.String(uploaded.URL),
@@ -176,11 +253,20 @@ func (s *MessageService) SendAudio(ctx context.Context, userID, sessionID string
 			Mimetype:      proto.String(mimeType),
 			FileEncSHA256: uploaded.FileEncSHA256,
 			FileSHA256:    uploaded.FileSHA256,
-			FileLength:    proto.Uint64(uint64(len(filedata))),
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			ContextInfo:   contextInfo,
 			PTT:           proto.Bool(ptt),
 		},
 	}
 
+	presence := types.ChatPresenceMediaText
+	if ptt {
+		presence = types.ChatPresenceMediaAudio
+	}
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, "", presence); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send audio: %w", err)
@@ -193,9 +279,16 @@ func (s *MessageService) SendAudio(ctx context.Context, userID, sessionID string
 }
 
 func (s *MessageService) SendVideo(ctx context.Context, userID, sessionID string, req *model.VideoMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		if req.Upload != nil {
+			return nil, errUploadNotQueueable
+		}
+		return s.enqueue(userID, sessionID, SendKindVideo, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -208,25 +301,25 @@ func (s *MessageService) SendVideo(ctx context.Context, userID, sessionID string
 		msgID = client.GenerateMessageID()
 	}
 
-	var filedata []byte
-	if strings.HasPrefix(req.Video, "data:video") {
-		dataURL, err := dataurl.DecodeString(req.Video)
-		if err != nil {
-			return nil, errors.New("invalid base64 video data")
-		}
-		filedata = dataURL.Data
-	} else {
-		return nil, errors.New("video must be base64 encoded (data:video/...)")
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, filedata, whatsmeow.MediaVideo)
+	media, err := s.openMedia(ctx, req.Video, req.Upload, mediaVideo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload video: %w", err)
+		return nil, err
 	}
+	defer media.Close()
 
 	mimeType := req.MimeType
 	if mimeType == "" {
-		mimeType = http.DetectContentType(filedata)
+		mimeType = media.detectMimeType()
+	}
+
+	uploaded, err := s.uploadMedia(ctx, client, media, mediaVideo)
+	if err != nil {
+		return nil, err
 	}
 
 	msg := &waE2E.Message{
@@ -238,10 +331,15 @@ func (s *MessageService) SendVideo(ctx context.Context, userID, sessionID string
 			Mimetype:      proto.String(mimeType),
 			FileEncSHA256: uploaded.FileEncSHA256,
 			FileSHA256:    uploaded.FileSHA256,
-			FileLength:    proto.Uint64(uint64(len(filedata))),
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			ContextInfo:   contextInfo,
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Caption, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send video: %w", err)
@@ -254,9 +352,16 @@ func (s *MessageService) SendVideo(ctx context.Context, userID, sessionID string
 }
 
 func (s *MessageService) SendDocument(ctx context.Context, userID, sessionID string, req *model.DocumentMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		if req.Upload != nil {
+			return nil, errUploadNotQueueable
+		}
+		return s.enqueue(userID, sessionID, SendKindDocument, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -269,25 +374,30 @@ func (s *MessageService) SendDocument(ctx context.Context, userID, sessionID str
 		msgID = client.GenerateMessageID()
 	}
 
-	var filedata []byte
-	if strings.HasPrefix(req.Document, "data:") {
-		dataURL, err := dataurl.DecodeString(req.Document)
-		if err != nil {
-			return nil, errors.New("invalid base64 document data")
-		}
-		filedata = dataURL.Data
-	} else {
-		return nil, errors.New("document must be base64 encoded")
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, filedata, whatsmeow.MediaDocument)
+	media, err := s.openMedia(ctx, req.Document, req.Upload, mediaDocument)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload document: %w", err)
+		return nil, err
 	}
+	defer media.Close()
 
 	mimeType := req.MimeType
 	if mimeType == "" {
-		mimeType = http.DetectContentType(filedata)
+		mimeType = media.detectMimeType()
+	}
+
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = media.fileName
+	}
+
+	uploaded, err := s.uploadMedia(ctx, client, media, mediaDocument)
+	if err != nil {
+		return nil, err
 	}
 
 	msg := &waE2E.Message{
@@ -299,11 +409,16 @@ func (s *MessageService) SendDocument(ctx context.Context, userID, sessionID str
 			Mimetype:      proto.String(mimeType),
 			FileEncSHA256: uploaded.FileEncSHA256,
 			FileSHA256:    uploaded.FileSHA256,
-			FileLength:    proto.Uint64(uint64(len(filedata))),
-			FileName:      proto.String(req.FileName),
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			ContextInfo:   contextInfo,
+			FileName:      proto.String(fileName),
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Caption, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send document: %w", err)
@@ -316,9 +431,13 @@ func (s *MessageService) SendDocument(ctx context.Context, userID, sessionID str
 }
 
 func (s *MessageService) SendLocation(ctx context.Context, userID, sessionID string, req *model.LocationMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		return s.enqueue(userID, sessionID, SendKindLocation, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -331,15 +450,25 @@ func (s *MessageService) SendLocation(ctx context.Context, userID, sessionID str
 		msgID = client.GenerateMessageID()
 	}
 
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	msg := &waE2E.Message{
 		LocationMessage: &waE2E.LocationMessage{
 			DegreesLatitude:  proto.Float64(req.Latitude),
 			DegreesLongitude: proto.Float64(req.Longitude),
 			Name:             proto.String(req.Name),
 			Address:          proto.String(req.Address),
+			ContextInfo:      contextInfo,
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Name, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send location: %w", err)
@@ -352,9 +481,13 @@ func (s *MessageService) SendLocation(ctx context.Context, userID, sessionID str
 }
 
 func (s *MessageService) SendContact(ctx context.Context, userID, sessionID string, req *model.ContactMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		return s.enqueue(userID, sessionID, SendKindContact, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -367,13 +500,23 @@ func (s *MessageService) SendContact(ctx context.Context, userID, sessionID stri
 		msgID = client.GenerateMessageID()
 	}
 
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	msg := &waE2E.Message{
 		ContactMessage: &waE2E.ContactMessage{
 			DisplayName: proto.String(req.ContactName),
 			Vcard:       proto.String(req.ContactVCard),
+			ContextInfo: contextInfo,
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, "", types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send contact: %w", err)
@@ -388,7 +531,7 @@ func (s *MessageService) SendContact(ctx context.Context, userID, sessionID stri
 func (s *MessageService) React(ctx context.Context, userID, sessionID string, req *model.ReactionMessage) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -420,7 +563,7 @@ func (s *MessageService) React(ctx context.Context, userID, sessionID string, re
 func (s *MessageService) Delete(ctx context.Context, userID, sessionID string, req *model.DeleteMessage) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -440,9 +583,16 @@ func (s *MessageService) Delete(ctx context.Context, userID, sessionID string, r
 }
 
 func (s *MessageService) SendSticker(ctx context.Context, userID, sessionID string, req *model.StickerMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		if req.Upload != nil {
+			return nil, errUploadNotQueueable
+		}
+		return s.enqueue(userID, sessionID, SendKindSticker, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -455,20 +605,20 @@ func (s *MessageService) SendSticker(ctx context.Context, userID, sessionID stri
 		msgID = client.GenerateMessageID()
 	}
 
-	var filedata []byte
-	if strings.HasPrefix(req.Sticker, "data:image") {
-		dataURL, err := dataurl.DecodeString(req.Sticker)
-		if err != nil {
-			return nil, errors.New("invalid base64 sticker data")
-		}
-		filedata = dataURL.Data
-	} else {
-		return nil, errors.New("sticker must be base64 encoded (data:image/webp;base64,...)")
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, filedata, whatsmeow.MediaImage)
+	media, err := s.openMedia(ctx, req.Sticker, req.Upload, mediaSticker)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload sticker: %w", err)
+		return nil, err
+	}
+	defer media.Close()
+
+	uploaded, err := s.uploadMedia(ctx, client, media, mediaSticker)
+	if err != nil {
+		return nil, err
 	}
 
 	mimeType := req.MimeType
@@ -484,11 +634,16 @@ func (s *MessageService) SendSticker(ctx context.Context, userID, sessionID stri
 			Mimetype:      proto.String(mimeType),
 			FileEncSHA256: uploaded.FileEncSHA256,
 			FileSHA256:    uploaded.FileSHA256,
-			FileLength:    proto.Uint64(uint64(len(filedata))),
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			ContextInfo:   contextInfo,
 			PngThumbnail:  req.PngThumbnail,
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, "", types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send sticker: %w", err)
@@ -501,9 +656,13 @@ func (s *MessageService) SendSticker(ctx context.Context, userID, sessionID stri
 }
 
 func (s *MessageService) SendPoll(ctx context.Context, userID, sessionID string, req *model.PollMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		return s.enqueue(userID, sessionID, SendKindPoll, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -516,12 +675,26 @@ func (s *MessageService) SendPoll(ctx context.Context, userID, sessionID string,
 		msgID = client.GenerateMessageID()
 	}
 
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	pollMessage := client.BuildPollCreation(req.Header, req.Options, 1)
+	pollMessage.PollCreationMessage.ContextInfo = contextInfo
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Header, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, pollMessage, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send poll: %w", err)
 	}
 
+	if s.sessionService.pollRepo != nil {
+		s.sessionService.savePoll(sessionID, msgID, recipient.String(), client.Store.GetJID().String(), pollMessage.PollCreationMessage)
+	}
+
 	return map[string]interface{}{
 		"timestamp": resp.Timestamp.Unix(),
 		"id":        msgID,
@@ -529,9 +702,13 @@ func (s *MessageService) SendPoll(ctx context.Context, userID, sessionID string,
 }
 
 func (s *MessageService) SendList(ctx context.Context, userID, sessionID string, req *model.ListMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		return s.enqueue(userID, sessionID, SendKindList, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -544,6 +721,11 @@ func (s *MessageService) SendList(ctx context.Context, userID, sessionID string,
 		msgID = client.GenerateMessageID()
 	}
 
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	var sections []*waE2E.ListMessage_Section
 	for _, sec := range req.Sections {
 		var rows []*waE2E.ListMessage_Row
@@ -570,6 +752,7 @@ func (s *MessageService) SendList(ctx context.Context, userID, sessionID string,
 		ButtonText:  proto.String(req.ButtonText),
 		ListType:    waE2E.ListMessage_SINGLE_SELECT.Enum(),
 		Sections:    sections,
+		ContextInfo: contextInfo,
 	}
 
 	if req.FooterText != "" {
@@ -584,6 +767,10 @@ func (s *MessageService) SendList(ctx context.Context, userID, sessionID string,
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Desc, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send list: %w", err)
@@ -596,9 +783,13 @@ func (s *MessageService) SendList(ctx context.Context, userID, sessionID string,
 }
 
 func (s *MessageService) SendButtons(ctx context.Context, userID, sessionID string, req *model.ButtonsMessage) (map[string]interface{}, error) {
+	if req.Queue {
+		return s.enqueue(userID, sessionID, SendKindButtons, req)
+	}
+
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -611,6 +802,11 @@ func (s *MessageService) SendButtons(ctx context.Context, userID, sessionID stri
 		msgID = client.GenerateMessageID()
 	}
 
+	contextInfo, err := s.buildContextInfo(sessionID, recipient, &req.SendOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	var buttons []*waE2E.ButtonsMessage_Button
 	for _, item := range req.Buttons {
 		buttons = append(buttons, &waE2E.ButtonsMessage_Button{
@@ -625,6 +821,7 @@ func (s *MessageService) SendButtons(ctx context.Context, userID, sessionID stri
 		ContentText: proto.String(req.Title),
 		HeaderType:  waE2E.ButtonsMessage_EMPTY.Enum(),
 		Buttons:     buttons,
+		ContextInfo: contextInfo,
 	}
 
 	msg := &waE2E.Message{
@@ -635,6 +832,10 @@ func (s *MessageService) SendButtons(ctx context.Context, userID, sessionID stri
 		},
 	}
 
+	if err := simulateTyping(ctx, client, recipient, &req.SendOptions, req.Title, types.ChatPresenceMediaText); err != nil {
+		return nil, err
+	}
+
 	resp, err := client.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send buttons: %w", err)
@@ -649,7 +850,7 @@ func (s *MessageService) SendButtons(ctx context.Context, userID, sessionID stri
 func (s *MessageService) EditMessage(ctx context.Context, userID, sessionID string, req *model.EditMessage) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	recipient, err := parseJID(req.Phone)
@@ -678,7 +879,7 @@ func (s *MessageService) EditMessage(ctx context.Context, userID, sessionID stri
 func (s *MessageService) MarkRead(ctx context.Context, userID, sessionID string, req *model.MarkReadMessage) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	chatJID, err := parseJID(req.ChatPhone)
@@ -706,7 +907,7 @@ func (s *MessageService) MarkRead(ctx context.Context, userID, sessionID string,
 func (s *MessageService) SetStatusText(ctx context.Context, userID, sessionID string, req *model.StatusTextMessage) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	err := client.SetStatusMessage(ctx, req.Body)
@@ -718,150 +919,74 @@ func (s *MessageService) SetStatusText(ctx context.Context, userID, sessionID st
 	}, nil
 }
 
-func (s *MessageService) DownloadImage(ctx context.Context, userID, sessionID string, req *model.DownloadMediaMessage) (map[string]interface{}, error) {
+// DownloadMedia downloads and decrypts a media message and returns it as a
+// base64 data URL. Prefer DownloadMediaFile for large files.
+func (s *MessageService) DownloadMedia(ctx context.Context, userID, sessionID string, req *model.DownloadMediaMessage) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
-	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
-		URL:           proto.String(req.URL),
-		DirectPath:    proto.String(req.DirectPath),
-		MediaKey:      req.MediaKey,
-		Mimetype:      proto.String(req.MimeType),
-		FileEncSHA256: req.FileEncSHA256,
-		FileSHA256:    req.FileSHA256,
-		FileLength:    &req.FileLength,
-	}}
-
-	data, err := client.Download(ctx, msg.GetImageMessage())
+	media, err := s.resolveDownload(sessionID, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download image: %w", err)
-	}
-
-	dataURL := dataurl.New(data, req.MimeType)
-	return map[string]interface{}{
-		"mimetype": req.MimeType,
-		"data":     dataURL.String(),
-	}, nil
-}
-
-func (s *MessageService) DownloadVideo(ctx context.Context, userID, sessionID string, req *model.DownloadMediaMessage) (map[string]interface{}, error) {
-	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
-	if client == nil {
-		return nil, errors.New("no session")
+		return nil, err
 	}
 
-	msg := &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
-		URL:           proto.String(req.URL),
-		DirectPath:    proto.String(req.DirectPath),
-		MediaKey:      req.MediaKey,
-		Mimetype:      proto.String(req.MimeType),
-		FileEncSHA256: req.FileEncSHA256,
-		FileSHA256:    req.FileSHA256,
-		FileLength:    &req.FileLength,
-	}}
-
-	data, err := client.Download(ctx, msg.GetVideoMessage())
+	data, err := client.Download(ctx, media.message)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download video: %w", err)
+		return nil, fmt.Errorf("failed to download %s: %w", media.kind, err)
 	}
 
-	dataURL := dataurl.New(data, req.MimeType)
+	dataURL := dataurl.New(data, media.mimeType)
 	return map[string]interface{}{
-		"mimetype": req.MimeType,
+		"mimetype": media.mimeType,
 		"data":     dataURL.String(),
 	}, nil
 }
 
-func (s *MessageService) DownloadAudio(ctx context.Context, userID, sessionID string, req *model.DownloadMediaMessage) (map[string]interface{}, error) {
+// DownloadMediaFile downloads and decrypts a media message into a temporary
+// file. The caller must Close the returned file, which also removes it.
+func (s *MessageService) DownloadMediaFile(ctx context.Context, userID, sessionID string, req *model.DownloadMediaMessage) (*MediaFile, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
-	msg := &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
-		URL:           proto.String(req.URL),
-		DirectPath:    proto.String(req.DirectPath),
-		MediaKey:      req.MediaKey,
-		Mimetype:      proto.String(req.MimeType),
-		FileEncSHA256: req.FileEncSHA256,
-		FileSHA256:    req.FileSHA256,
-		FileLength:    &req.FileLength,
-	}}
-
-	data, err := client.Download(ctx, msg.GetAudioMessage())
+	media, err := s.resolveDownload(sessionID, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download audio: %w", err)
-	}
-
-	dataURL := dataurl.New(data, req.MimeType)
-	return map[string]interface{}{
-		"mimetype": req.MimeType,
-		"data":     dataURL.String(),
-	}, nil
-}
-
-func (s *MessageService) DownloadDocument(ctx context.Context, userID, sessionID string, req *model.DownloadMediaMessage) (map[string]interface{}, error) {
-	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
-	if client == nil {
-		return nil, errors.New("no session")
+		return nil, err
 	}
 
-	msg := &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
-		URL:           proto.String(req.URL),
-		DirectPath:    proto.String(req.DirectPath),
-		MediaKey:      req.MediaKey,
-		Mimetype:      proto.String(req.MimeType),
-		FileEncSHA256: req.FileEncSHA256,
-		FileSHA256:    req.FileSHA256,
-		FileLength:    &req.FileLength,
-	}}
-
-	data, err := client.Download(ctx, msg.GetDocumentMessage())
+	file, err := os.CreateTemp("", "fiozap-media-*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to download document: %w", err)
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
 	}
+	out := &MediaFile{File: file, MimeType: media.mimeType, FileName: media.fileName}
 
-	dataURL := dataurl.New(data, req.MimeType)
-	return map[string]interface{}{
-		"mimetype": req.MimeType,
-		"data":     dataURL.String(),
-	}, nil
-}
-
-func (s *MessageService) DownloadSticker(ctx context.Context, userID, sessionID string, req *model.DownloadMediaMessage) (map[string]interface{}, error) {
-	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
-	if client == nil {
-		return nil, errors.New("no session")
+	if err := client.DownloadToFile(ctx, media.message, file); err != nil {
+		_ = out.Close()
+		return nil, fmt.Errorf("failed to download %s: %w", media.kind, err)
 	}
 
-	msg := &waE2E.Message{StickerMessage: &waE2E.StickerMessage{
-		URL:           proto.String(req.URL),
-		DirectPath:    proto.String(req.DirectPath),
-		MediaKey:      req.MediaKey,
-		Mimetype:      proto.String(req.MimeType),
-		FileEncSHA256: req.FileEncSHA256,
-		FileSHA256:    req.FileSHA256,
-		FileLength:    &req.FileLength,
-	}}
-
-	data, err := client.Download(ctx, msg.GetStickerMessage())
+	info, err := file.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to download sticker: %w", err)
+		_ = out.Close()
+		return nil, fmt.Errorf("failed to stat media: %w", err)
 	}
+	out.Size = info.Size()
 
-	dataURL := dataurl.New(data, req.MimeType)
-	return map[string]interface{}{
-		"mimetype": req.MimeType,
-		"data":     dataURL.String(),
-	}, nil
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = out.Close()
+		return nil, fmt.Errorf("failed to rewind media: %w", err)
+	}
+
+	return out, nil
 }
 
 func (s *MessageService) ArchiveChat(ctx context.Context, userID, sessionID string, req *model.ArchiveChatMessage) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	chatJID, err := parseJID(req.Phone)
@@ -880,9 +1005,12 @@ func (s *MessageService) ArchiveChat(ctx context.Context, userID, sessionID stri
 	}, nil
 }
 
+// ErrInvalidJID is returned for phones and JIDs that cannot be parsed.
+var ErrInvalidJID = errors.New("invalid JID")
+
 func parseJID(phone string) (types.JID, error) {
 	if phone == "" {
-		return types.JID{}, errors.New("phone is required")
+		return types.JID{}, fmt.Errorf("%w: phone is required", ErrInvalidJID)
 	}
 
 	if phone[0] == '+' {
@@ -895,7 +1023,7 @@ func parseJID(phone string) (types.JID, error) {
 
 	jid, err := types.ParseJID(phone)
 	if err != nil {
-		return types.JID{}, fmt.Errorf("invalid JID: %w", err)
+		return types.JID{}, fmt.Errorf("%w: %v", ErrInvalidJID, err)
 	}
 
 	return jid, nil
@@ -23,7 +23,7 @@ func NewNewsletterService(sessionService *SessionService) *NewsletterService {
 func (s *NewsletterService) List(ctx context.Context, userID, sessionID string) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	newsletters, err := client.GetSubscribedNewsletters(ctx)
@@ -54,7 +54,7 @@ func (s *NewsletterService) List(ctx context.Context, userID, sessionID string)
 func (s *NewsletterService) GetInfo(ctx context.Context, userID, sessionID, jid string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -73,7 +73,7 @@ func (s *NewsletterService) GetInfo(ctx context.Context, userID, sessionID, jid
 func (s *NewsletterService) GetInfoWithInvite(ctx context.Context, userID, sessionID, inviteKey string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	info, err := client.GetNewsletterInfoWithInvite(ctx, inviteKey)
@@ -87,7 +87,7 @@ func (s *NewsletterService) GetInfoWithInvite(ctx context.Context, userID, sessi
 func (s *NewsletterService) GetMessages(ctx context.Context, userID, sessionID, jid string, count int, before int) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -126,7 +126,7 @@ func (s *NewsletterService) GetMessages(ctx context.Context, userID, sessionID,
 func (s *NewsletterService) Follow(ctx context.Context, userID, sessionID, jid string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -140,7 +140,7 @@ func (s *NewsletterService) Follow(ctx context.Context, userID, sessionID, jid s
 func (s *NewsletterService) Unfollow(ctx context.Context, userID, sessionID, jid string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -154,7 +154,7 @@ func (s *NewsletterService) Unfollow(ctx context.Context, userID, sessionID, jid
 func (s *NewsletterService) ToggleMute(ctx context.Context, userID, sessionID, jid string, mute bool) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -168,7 +168,7 @@ func (s *NewsletterService) ToggleMute(ctx context.Context, userID, sessionID, j
 func (s *NewsletterService) MarkViewed(ctx context.Context, userID, sessionID, jid string, serverIDs []int) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -187,7 +187,7 @@ func (s *NewsletterService) MarkViewed(ctx context.Context, userID, sessionID, j
 func (s *NewsletterService) SendReaction(ctx context.Context, userID, sessionID, jid string, serverID int, reaction string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -201,7 +201,7 @@ func (s *NewsletterService) SendReaction(ctx context.Context, userID, sessionID,
 func (s *NewsletterService) SubscribeLiveUpdates(ctx context.Context, userID, sessionID, jid string) (time.Duration, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return 0, errors.New("no session")
+		return 0, ErrNoSession
 	}
 
 	newsletterJID, err := parseNewsletterJID(jid)
@@ -215,7 +215,7 @@ func (s *NewsletterService) SubscribeLiveUpdates(ctx context.Context, userID, se
 func (s *NewsletterService) Create(ctx context.Context, userID, sessionID, name, description, picture string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	params := whatsmeow.CreateNewsletterParams{
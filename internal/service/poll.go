@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+)
+
+const pollDecryptTimeout = 10 * time.Second
+
+type PollService struct {
+	repo *repository.PollRepository
+}
+
+func NewPollService(repo *repository.PollRepository) *PollService {
+	return &PollService{repo: repo}
+}
+
+// Results counts the current votes of a poll per option.
+func (s *PollService) Results(sessionID, messageID string) (*model.PollResults, error) {
+	poll, err := s.repo.GetByID(sessionID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("poll not found: %w", err)
+	}
+
+	votes, err := s.repo.ListVotes(sessionID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+
+	results := &model.PollResults{Poll: poll, Options: make([]model.PollOptionResult, len(poll.Options))}
+	index := make(map[string]int, len(poll.Options))
+	for i, name := range poll.Options {
+		results.Options[i] = model.PollOptionResult{Name: name, Voters: []string{}}
+		index[name] = i
+	}
+
+	for _, vote := range votes {
+		if len(vote.Options) == 0 {
+			continue
+		}
+		results.Voters++
+		for _, name := range vote.Options {
+			if i, ok := index[name]; ok {
+				results.Options[i].Votes++
+				results.Options[i].Voters = append(results.Options[i].Voters, vote.Voter)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// savePoll records a poll so votes on it can be matched to its options.
+func (s *SessionService) savePoll(sessionID, messageID, chat, sender string, poll *waE2E.PollCreationMessage) {
+	options := make([]string, 0, len(poll.GetOptions()))
+	for _, opt := range poll.GetOptions() {
+		options = append(options, opt.GetOptionName())
+	}
+
+	err := s.pollRepo.Save(&model.Poll{
+		ID:              messageID,
+		SessionID:       sessionID,
+		Chat:            chat,
+		Sender:          sender,
+		Name:            poll.GetName(),
+		Options:         options,
+		SelectableCount: int(poll.GetSelectableOptionsCount()),
+	})
+	if err != nil {
+		logger.Warnf("Failed to store poll %s: %v", messageID, err)
+	}
+}
+
+// handlePollMessage records polls and decrypts votes on them, which are
+// stored and emitted as PollVote events.
+func (s *SessionService) handlePollMessage(userID, sessionID string, client *whatsmeow.Client, evt *events.Message) {
+	m := evt.Message
+	switch {
+	case m.GetPollCreationMessage() != nil:
+		s.savePoll(sessionID, evt.Info.ID, evt.Info.Chat.String(), evt.Info.Sender.String(), m.GetPollCreationMessage())
+	case m.GetPollCreationMessageV2() != nil:
+		s.savePoll(sessionID, evt.Info.ID, evt.Info.Chat.String(), evt.Info.Sender.String(), m.GetPollCreationMessageV2())
+	case m.GetPollCreationMessageV3() != nil:
+		s.savePoll(sessionID, evt.Info.ID, evt.Info.Chat.String(), evt.Info.Sender.String(), m.GetPollCreationMessageV3())
+	case m.GetPollUpdateMessage() != nil:
+		s.handlePollVote(userID, sessionID, client, evt)
+	}
+}
+
+func (s *SessionService) handlePollVote(userID, sessionID string, client *whatsmeow.Client, evt *events.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), pollDecryptTimeout)
+	defer cancel()
+
+	pollID := evt.Message.GetPollUpdateMessage().GetPollCreationMessageKey().GetID()
+	vote, err := client.DecryptPollVote(ctx, evt)
+	if err != nil {
+		logger.Warnf("Failed to decrypt vote on poll %s: %v", pollID, err)
+		return
+	}
+
+	names := make(map[string]string)
+	pollName := ""
+	if poll, err := s.pollRepo.GetByID(sessionID, pollID); err == nil {
+		pollName = poll.Name
+		for i, hash := range whatsmeow.HashPollOptions(poll.Options) {
+			names[string(hash)] = poll.Options[i]
+		}
+	}
+
+	// Options of polls that were not recorded are reported by their hash.
+	selected := make([]string, 0, len(vote.GetSelectedOptions()))
+	for _, hash := range vote.GetSelectedOptions() {
+		if name, ok := names[string(hash)]; ok {
+			selected = append(selected, name)
+		} else {
+			selected = append(selected, hex.EncodeToString(hash))
+		}
+	}
+
+	voter := evt.Info.Sender.String()
+	if err := s.pollRepo.SaveVote(sessionID, pollID, voter, selected, evt.Info.Timestamp); err != nil {
+		logger.Warnf("Failed to store vote on poll %s: %v", pollID, err)
+	}
+
+	s.handleEvent(userID, sessionID, "PollVote", map[string]interface{}{
+		"poll_id":   pollID,
+		"poll_name": pollName,
+		"chat":      evt.Info.Chat.String(),
+		"voter":     voter,
+		"options":   selected,
+		"timestamp": evt.Info.Timestamp.Unix(),
+	})
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.mau.fi/whatsmeow/types"
+
+	"fiozap/internal/model"
+)
+
+var ErrInvalidPrivacy = errors.New("invalid privacy setting")
+
+var (
+	privacyAudience = []types.PrivacySetting{
+		types.PrivacySettingAll,
+		types.PrivacySettingContacts,
+		types.PrivacySettingContactBlacklist,
+		types.PrivacySettingNone,
+	}
+	privacyOnline       = []types.PrivacySetting{types.PrivacySettingAll, types.PrivacySettingMatchLastSeen}
+	privacyReadReceipts = []types.PrivacySetting{types.PrivacySettingAll, types.PrivacySettingNone}
+	privacyCallAdd      = []types.PrivacySetting{types.PrivacySettingAll, types.PrivacySettingKnown}
+)
+
+// PrivacyService reads and changes the privacy settings of the account of a
+// session.
+type PrivacyService struct {
+	sessionService *SessionService
+}
+
+func NewPrivacyService(sessionService *SessionService) *PrivacyService {
+	return &PrivacyService{sessionService: sessionService}
+}
+
+// Get fetches the current privacy settings from the server.
+func (s *PrivacyService) Get(ctx context.Context, userID, sessionID string) (*model.PrivacySettings, error) {
+	client, _, err := s.sessionService.ownClient(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := client.TryFetchPrivacySettings(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+	return privacySettingsModel(*settings), nil
+}
+
+// Set changes the settings present in the request, in a fixed order, and
+// returns the resulting settings. All values are validated before anything
+// is changed.
+func (s *PrivacyService) Set(ctx context.Context, userID, sessionID string, req *model.PrivacySettingsRequest) (*model.PrivacySettings, error) {
+	changes := []struct {
+		name    types.PrivacySettingType
+		field   string
+		value   *string
+		allowed []types.PrivacySetting
+	}{
+		{types.PrivacySettingTypeLastSeen, "last_seen", req.LastSeen, privacyAudience},
+		{types.PrivacySettingTypeOnline, "online", req.Online, privacyOnline},
+		{types.PrivacySettingTypeProfile, "profile", req.Profile, privacyAudience},
+		{types.PrivacySettingTypeStatus, "about", req.About, privacyAudience},
+		{types.PrivacySettingTypeGroupAdd, "group_add", req.GroupAdd, privacyAudience},
+		{types.PrivacySettingTypeReadReceipts, "read_receipts", req.ReadReceipts, privacyReadReceipts},
+		{types.PrivacySettingTypeCallAdd, "call_add", req.CallAdd, privacyCallAdd},
+	}
+
+	empty := true
+	for _, c := range changes {
+		if c.value == nil {
+			continue
+		}
+		empty = false
+		if !slices.Contains(c.allowed, types.PrivacySetting(*c.value)) {
+			return nil, fmt.Errorf("%w: %s must be one of %v", ErrInvalidPrivacy, c.field, c.allowed)
+		}
+	}
+	if empty {
+		return nil, fmt.Errorf("%w: no setting given", ErrInvalidPrivacy)
+	}
+
+	client, _, err := s.sessionService.ownClient(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings types.PrivacySettings
+	for _, c := range changes {
+		if c.value == nil {
+			continue
+		}
+		settings, err = client.SetPrivacySetting(ctx, c.name, types.PrivacySetting(*c.value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", c.field, err)
+		}
+	}
+	return privacySettingsModel(settings), nil
+}
+
+// privacySettingsModel converts whatsmeow privacy settings to the API model.
+func privacySettingsModel(settings types.PrivacySettings) *model.PrivacySettings {
+	return &model.PrivacySettings{
+		LastSeen:     string(settings.LastSeen),
+		Online:       string(settings.Online),
+		Profile:      string(settings.Profile),
+		About:        string(settings.Status),
+		GroupAdd:     string(settings.GroupAdd),
+		ReadReceipts: string(settings.ReadReceipts),
+		CallAdd:      string(settings.CallAdd),
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+
+	"fiozap/internal/model"
+)
+
+const (
+	profilePhotoSize      = 640
+	profilePhotoQuality   = 90
+	profilePhotoMaxPixels = 50_000_000
+	profileMaxNameLength  = 25
+	profileMaxAboutLength = 139
+)
+
+var (
+	ErrInvalidProfile = errors.New("invalid profile")
+)
+
+// ProfileService manages the profile of the account of a session.
+type ProfileService struct {
+	sessionService *SessionService
+	messageService *MessageService
+}
+
+func NewProfileService(sessionService *SessionService, messageService *MessageService) *ProfileService {
+	return &ProfileService{
+		sessionService: sessionService,
+		messageService: messageService,
+	}
+}
+
+// Get returns the current profile of the session account.
+func (s *ProfileService) Get(ctx context.Context, userID, sessionID string) (*model.Profile, error) {
+	client, own, err := s.sessionService.ownClient(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &model.Profile{
+		JID:      own.String(),
+		PushName: client.Store.PushName,
+	}
+	if !client.Store.LID.IsEmpty() {
+		profile.LID = client.Store.LID.ToNonAD().String()
+	}
+
+	infos, err := client.GetUserInfo(ctx, []types.JID{own})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	if info, ok := infos[own]; ok {
+		profile.About = info.Status
+		profile.PictureID = info.PictureID
+	}
+
+	pic, err := client.GetProfilePictureInfo(ctx, own, &whatsmeow.GetProfilePictureParams{})
+	switch {
+	case errors.Is(err, whatsmeow.ErrProfilePictureNotSet):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get profile picture: %w", err)
+	case pic != nil:
+		profile.PictureID = pic.ID
+		profile.PictureURL = pic.URL
+	}
+
+	return profile, nil
+}
+
+// SetName changes the push name shown to contacts that have not saved the
+// number.
+func (s *ProfileService) SetName(ctx context.Context, userID, sessionID, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidProfile)
+	}
+	if len([]rune(name)) > profileMaxNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidProfile, profileMaxNameLength)
+	}
+
+	client, _, err := s.sessionService.ownClient(userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := client.SendAppState(ctx, appstate.BuildSettingPushName(name)); err != nil {
+		return fmt.Errorf("failed to set name: %w", err)
+	}
+
+	client.Store.PushName = name
+	if err := client.Store.Save(ctx); err != nil {
+		return fmt.Errorf("failed to store name: %w", err)
+	}
+	return nil
+}
+
+// SetAbout changes the about text of the profile.
+func (s *ProfileService) SetAbout(ctx context.Context, userID, sessionID, about string) error {
+	if len([]rune(about)) > profileMaxAboutLength {
+		return fmt.Errorf("%w: about must be at most %d characters", ErrInvalidProfile, profileMaxAboutLength)
+	}
+
+	client, _, err := s.sessionService.ownClient(userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := client.SetStatusMessage(ctx, about); err != nil {
+		return fmt.Errorf("failed to set about: %w", err)
+	}
+	return nil
+}
+
+// SetPhoto replaces the profile photo and returns the id of the new picture.
+func (s *ProfileService) SetPhoto(ctx context.Context, userID, sessionID, image string) (string, error) {
+	if image == "" {
+		return "", fmt.Errorf("%w: image is required", ErrInvalidProfile)
+	}
+
+	client, _, err := s.sessionService.ownClient(userID, sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	media, err := s.messageService.openMedia(ctx, image, nil, mediaImage)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidProfile, err)
+	}
+	defer media.Close()
+
+	data := media.data
+	if data == nil {
+		if data, err = io.ReadAll(media.reader); err != nil {
+			return "", fmt.Errorf("failed to read image: %w", err)
+		}
+	}
+
+	photo, err := profilePhoto(data)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid image: %v", ErrInvalidProfile, err)
+	}
+
+	// Without a target the picture of the own account is changed.
+	pictureID, err := client.SetGroupPhoto(ctx, types.EmptyJID, photo)
+	if err != nil {
+		return "", fmt.Errorf("failed to set profile photo: %w", err)
+	}
+	return pictureID, nil
+}
+
+func (s *ProfileService) RemovePhoto(ctx context.Context, userID, sessionID string) error {
+	client, _, err := s.sessionService.ownClient(userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.SetGroupPhoto(ctx, types.EmptyJID, nil); err != nil {
+		return fmt.Errorf("failed to remove profile photo: %w", err)
+	}
+	return nil
+}
+
+// profilePhoto re-encodes an image as a JPEG that fits the profile photo
+// size. Images with absurd dimensions are rejected before decoding.
+func profilePhoto(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > profilePhotoMaxPixels {
+		return nil, errors.New("image is too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleImage(img, profilePhotoSize), &jpeg.Options{Quality: profilePhotoQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
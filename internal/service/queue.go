@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"fiozap/internal/config"
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+)
+
+const (
+	queuePollInterval = time.Second
+	queueSendTimeout  = 60 * time.Second
+	queueRetryDelay   = 30 * time.Second
+	queueStaleAfter   = 10 * time.Minute
+	queueMaxAttempts  = 3
+	queueListLimit    = 100
+	queueComponent    = "queue"
+)
+
+// SendQueue drains queued send requests. Each session is drained by its own
+// goroutine, paced by a token bucket plus a random jitter between sends.
+type SendQueue struct {
+	repo           *repository.QueueRepository
+	messageService *MessageService
+	rate           float64
+	burst          float64
+	jitter         time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	active  map[string]bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewSendQueue(repo *repository.QueueRepository, messageService *MessageService, cfg *config.Config) *SendQueue {
+	return &SendQueue{
+		repo:           repo,
+		messageService: messageService,
+		rate:           float64(cfg.QueueRate) / 60,
+		burst:          float64(cfg.QueueBurst),
+		jitter:         cfg.QueueJitter,
+		buckets:        make(map[string]*tokenBucket),
+		active:         make(map[string]bool),
+		stopCh:         make(chan struct{}),
+	}
+}
+
+func (q *SendQueue) Start() {
+	q.releaseStale()
+
+	q.wg.Add(1)
+	go q.processLoop()
+	logger.Component(queueComponent).Str("status", "running").Msg("send queue started")
+}
+
+func (q *SendQueue) Stop() {
+	close(q.stopCh)
+	q.wg.Wait()
+	logger.Component(queueComponent).Str("status", "stopped").Msg("send queue stopped")
+}
+
+// Enqueue stores a send request. The WhatsApp message id is fixed here and
+// kept in the payload, so a retry after a send that timed out but reached
+// WhatsApp is deduplicated instead of delivered twice.
+func (q *SendQueue) Enqueue(userID, sessionID, kind string, req interface{}) (*model.QueueItem, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	client := q.messageService.sessionService.GetWhatsmeowClient(userID, sessionID)
+	payload, messageID, err := withMessageID(payload, client.GenerateMessageID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	item, err := q.repo.Create(userID, sessionID, kind, payload, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue message: %w", err)
+	}
+
+	return item, nil
+}
+
+// withMessageID sets the "id" field of a send request payload to id unless
+// the caller already chose one, and returns the id that will be used.
+func withMessageID(payload json.RawMessage, id string) (json.RawMessage, string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, "", err
+	}
+
+	var existing string
+	if raw, ok := fields["id"]; ok {
+		_ = json.Unmarshal(raw, &existing)
+	}
+	if existing != "" {
+		return payload, existing, nil
+	}
+
+	encoded, err := json.Marshal(id)
+	if err != nil {
+		return nil, "", err
+	}
+	fields["id"] = encoded
+
+	payload, err = json.Marshal(fields)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, id, nil
+}
+
+func (q *SendQueue) List(sessionID, status string) ([]model.QueueItem, error) {
+	return q.repo.ListBySession(sessionID, status, queueListLimit)
+}
+
+func (q *SendQueue) Get(sessionID, id string) (*model.QueueItem, error) {
+	item, err := q.repo.GetByID(sessionID, id)
+	if err != nil {
+		return nil, fmt.Errorf("queue item not found: %w", err)
+	}
+	return item, nil
+}
+
+func (q *SendQueue) Cancel(sessionID, id string) error {
+	canceled, err := q.repo.Cancel(sessionID, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel queue item: %w", err)
+	}
+	if !canceled {
+		return errors.New("queue item not found or no longer pending")
+	}
+	return nil
+}
+
+func (q *SendQueue) processLoop() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	staleTicker := time.NewTicker(queueStaleAfter)
+	defer staleTicker.Stop()
+
+	for {
+		select {
+		case <-q.stopCh:
+			return
+		case <-ticker.C:
+			q.processPending()
+		case <-staleTicker.C:
+			q.releaseStale()
+		}
+	}
+}
+
+func (q *SendQueue) releaseStale() {
+	if err := q.repo.ReleaseStale(queueStaleAfter); err != nil {
+		logger.WarnComponent(queueComponent).Err(err).Msg("failed to release stale items")
+	}
+}
+
+func (q *SendQueue) processPending() {
+	sessions, err := q.repo.PendingSessions()
+	if err != nil {
+		logger.WithError(err).Str("component", queueComponent).Msg("failed to get pending sessions")
+		return
+	}
+
+	for _, sessionID := range sessions {
+		q.mu.Lock()
+		if q.active[sessionID] {
+			q.mu.Unlock()
+			continue
+		}
+		q.active[sessionID] = true
+		q.mu.Unlock()
+
+		q.wg.Add(1)
+		go q.drainSession(sessionID)
+	}
+}
+
+func (q *SendQueue) drainSession(sessionID string) {
+	defer q.wg.Done()
+	defer func() {
+		q.mu.Lock()
+		delete(q.active, sessionID)
+		q.mu.Unlock()
+	}()
+
+	for {
+		item, err := q.repo.ClaimNext(sessionID)
+		if err != nil {
+			logger.WarnComponent(queueComponent).Str("session", sessionID).Err(err).Msg("failed to claim item")
+			return
+		}
+		if item == nil {
+			return
+		}
+
+		wait := q.Reserve(sessionID)
+		if q.jitter > 0 {
+			wait += rand.N(q.jitter)
+		}
+
+		if !q.sleep(wait) {
+			_ = q.repo.Release(item.ID)
+			return
+		}
+
+		q.send(item)
+	}
+}
+
+func (q *SendQueue) send(item *model.QueueItem) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueSendTimeout)
+	defer cancel()
+
+	result, err := q.messageService.Send(ctx, item.UserID, item.SessionID, item.Kind, item.Payload)
+	if err != nil {
+		if item.Attempts < queueMaxAttempts {
+			logger.WarnComponent(queueComponent).Str("id", item.ID).Int("attempt", item.Attempts).Err(err).Msg("send failed, retrying")
+			_ = q.repo.Retry(item.ID, err.Error(), time.Duration(item.Attempts)*queueRetryDelay)
+			return
+		}
+
+		logger.WarnComponent(queueComponent).Str("id", item.ID).Err(err).Msg("send failed")
+		_ = q.repo.MarkFailed(item.ID, err.Error())
+		q.emit(item, "QueueFailed", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	messageID, _ := result["id"].(string)
+	_ = q.repo.MarkSent(item.ID, messageID)
+	q.emit(item, "QueueSent", map[string]interface{}{
+		"id":        messageID,
+		"timestamp": result["timestamp"],
+	})
+}
+
+func (q *SendQueue) emit(item *model.QueueItem, eventType string, data map[string]interface{}) {
+	data["queue_id"] = item.ID
+	data["kind"] = item.Kind
+	data["phone"] = payloadPhone(item.Payload)
+	q.messageService.sessionService.handleEvent(item.UserID, item.SessionID, eventType, data)
+}
+
+// Reserve takes a send token of the session and returns how long to wait
+// before sending. Senders outside the queue use it to share the session's
+// rate limit with the queue.
+func (q *SendQueue) Reserve(sessionID string) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	b, ok := q.buckets[sessionID]
+	if !ok {
+		b = &tokenBucket{tokens: q.burst, capacity: q.burst, rate: q.rate, last: time.Now()}
+		q.buckets[sessionID] = b
+	}
+	return b.reserve(time.Now())
+}
+
+// sleep waits for d and reports false if the queue was stopped meanwhile.
+func (q *SendQueue) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-q.stopCh:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// tokenBucket allows bursts of capacity sends, refilled at rate tokens per
+// second. It is guarded by the mutex of its queue.
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64
+	last     time.Time
+}
+
+// reserve takes a token and returns how long to wait before it may be used.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		tokens   float64
+		capacity float64
+		rate     float64
+		elapsed  []time.Duration
+		want     []time.Duration
+	}{
+		{
+			name:     "burst is free",
+			tokens:   3,
+			capacity: 3,
+			rate:     1,
+			elapsed:  []time.Duration{0, 0, 0},
+			want:     []time.Duration{0, 0, 0},
+		},
+		{
+			name:     "waits once empty",
+			tokens:   1,
+			capacity: 1,
+			rate:     2,
+			elapsed:  []time.Duration{0, 0, 0},
+			want:     []time.Duration{0, 500 * time.Millisecond, time.Second},
+		},
+		{
+			name:     "refills over time",
+			tokens:   0,
+			capacity: 1,
+			rate:     1,
+			elapsed:  []time.Duration{time.Second, 2 * time.Second},
+			want:     []time.Duration{0, 0},
+		},
+		{
+			name:     "refill is capped at capacity",
+			tokens:   0,
+			capacity: 2,
+			rate:     1,
+			elapsed:  []time.Duration{time.Hour, 0, 0},
+			want:     []time.Duration{0, 0, time.Second},
+		},
+		{
+			name:     "partial refill shortens the wait",
+			tokens:   0,
+			capacity: 1,
+			rate:     1,
+			elapsed:  []time.Duration{250 * time.Millisecond},
+			want:     []time.Duration{750 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &tokenBucket{tokens: tt.tokens, capacity: tt.capacity, rate: tt.rate, last: start}
+			now := start
+			for i, elapsed := range tt.elapsed {
+				now = now.Add(elapsed)
+				if got := b.reserve(now); got != tt.want[i] {
+					t.Errorf("reserve #%d = %v, want %v", i+1, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithMessageID(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		wantID  string
+	}{
+		{name: "sets missing id", payload: `{"phone":"5511999999999"}`, wantID: "GENERATED"},
+		{name: "replaces empty id", payload: `{"phone":"5511999999999","id":""}`, wantID: "GENERATED"},
+		{name: "keeps caller id", payload: `{"phone":"5511999999999","id":"CUSTOM"}`, wantID: "CUSTOM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, id, err := withMessageID(json.RawMessage(tt.payload), "GENERATED")
+			if err != nil {
+				t.Fatalf("withMessageID() error = %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("withMessageID() id = %q, want %q", id, tt.wantID)
+			}
+
+			var decoded struct {
+				Phone string `json:"phone"`
+				ID    string `json:"id"`
+			}
+			if err := json.Unmarshal(payload, &decoded); err != nil {
+				t.Fatalf("invalid payload %s: %v", payload, err)
+			}
+			if decoded.ID != tt.wantID || decoded.Phone != "5511999999999" {
+				t.Errorf("withMessageID() payload = %s", payload)
+			}
+		})
+	}
+
+	if _, _, err := withMessageID(json.RawMessage(`[]`), "GENERATED"); err == nil {
+		t.Error("withMessageID() accepted a non-object payload")
+	}
+}
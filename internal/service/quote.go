@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"fiozap/internal/model"
+)
+
+// buildContextInfo maps the reply and mention options of a send request to a
+// ContextInfo. It returns nil when the request neither quotes nor mentions.
+func (s *MessageService) buildContextInfo(sessionID string, recipient types.JID, opts *model.SendOptions) (*waE2E.ContextInfo, error) {
+	if opts.ReplyTo == nil && len(opts.Mentions) == 0 {
+		return nil, nil
+	}
+
+	info := &waE2E.ContextInfo{}
+
+	for _, mention := range opts.Mentions {
+		jid, err := parseJID(mention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mention %q: %w", mention, err)
+		}
+		info.MentionedJID = append(info.MentionedJID, jid.String())
+	}
+
+	if opts.ReplyTo != nil {
+		if err := s.setQuoted(info, sessionID, recipient, opts.ReplyTo); err != nil {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+// setQuoted fills the quoted message fields. Missing participant and text are
+// taken from the message store; without it, the quoted message is assumed to
+// come from the recipient of a direct chat.
+func (s *MessageService) setQuoted(info *waE2E.ContextInfo, sessionID string, recipient types.JID, reply *model.ReplyTo) error {
+	if reply.MessageID == "" {
+		return errors.New("reply_to.message_id is required")
+	}
+
+	info.StanzaID = proto.String(reply.MessageID)
+	quoted := &waE2E.Message{Conversation: proto.String(reply.Text)}
+
+	participant := ""
+	if reply.Participant != "" {
+		jid, err := parseJID(reply.Participant)
+		if err != nil {
+			return fmt.Errorf("invalid reply_to.participant: %w", err)
+		}
+		participant = jid.String()
+	}
+
+	if s.messageRepo != nil && (participant == "" || reply.Text == "") {
+		if stored, err := s.messageRepo.GetByID(sessionID, reply.MessageID); err == nil {
+			if participant == "" {
+				participant = stored.Sender
+			}
+			if reply.Text == "" {
+				var raw waE2E.Message
+				if err := proto.Unmarshal(stored.Raw, &raw); err == nil {
+					quoted = &raw
+				}
+			}
+		}
+	}
+
+	if participant == "" {
+		if recipient.Server == types.GroupServer {
+			return errors.New("reply_to.participant is required for group messages")
+		}
+		participant = recipient.ToNonAD().String()
+	}
+
+	info.Participant = proto.String(participant)
+	info.QuotedMessage = quoted
+	return nil
+}
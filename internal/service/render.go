@@ -0,0 +1,72 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// renderText replaces {{name}} placeholders with their variable values.
+// Unknown placeholders are replaced with an empty string.
+func renderText(text string, vars map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		return vars[name]
+	})
+}
+
+// renderPayload renders the placeholders of every string in a send payload
+// and addresses it to phone.
+func renderPayload(payload json.RawMessage, phone string, vars map[string]string) (json.RawMessage, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	rendered := renderValue(doc, vars).(map[string]interface{})
+	rendered["phone"] = phone
+
+	return json.Marshal(rendered)
+}
+
+func renderValue(v interface{}, vars map[string]string) interface{} {
+	switch val := v.(type) {
+	case string:
+		if strings.Contains(val, "{{") {
+			return renderText(val, vars)
+		}
+		return val
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = renderValue(item, vars)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = renderValue(item, vars)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// placeholderNames returns the sorted, distinct placeholder names used in a
+// payload.
+func placeholderNames(payload json.RawMessage) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, match := range placeholderPattern.FindAllSubmatch(payload, -1) {
+		name := string(match[1])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,37 @@
+package service
+
+import "testing"
+
+func TestRenderText(t *testing.T) {
+	vars := map[string]string{
+		"name":       "Ana",
+		"order.id":   "42",
+		"first-name": "Ana Maria",
+		"amount_due": "R$ 10,00",
+		"recursive":  "{{name}}",
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"no placeholders", "Hello there", "Hello there"},
+		{"single placeholder", "Hello {{name}}", "Hello Ana"},
+		{"spaces inside braces", "Hello {{ name }}!", "Hello Ana!"},
+		{"repeated placeholder", "{{name}} {{name}}", "Ana Ana"},
+		{"dots dashes and underscores", "#{{order.id}} {{first-name}} {{amount_due}}", "#42 Ana Maria R$ 10,00"},
+		{"unknown placeholder is emptied", "Hi {{missing}}.", "Hi ."},
+		{"values are not rendered again", "{{recursive}}", "{{name}}"},
+		{"invalid names are kept", "{{not valid}} {{}}", "{{not valid}} {{}}"},
+		{"single braces are kept", "{name}", "{name}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderText(tt.text, vars); got != tt.want {
+				t.Errorf("renderText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
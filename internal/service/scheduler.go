@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	_ "time/tzdata" // time zones for send_at, even on hosts without zoneinfo
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/logger"
+	"fiozap/internal/model"
+)
+
+const (
+	schedulerPollInterval = 5 * time.Second
+	schedulerBatchSize    = 20
+	schedulerStaleAfter   = 10 * time.Minute
+	schedulerWorkers      = 8
+	schedulerComponent    = "scheduler"
+)
+
+var (
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+	ErrScheduleNotFound = errors.New("scheduled message not found")
+)
+
+// localTimeLayouts are accepted for send_at values without a UTC offset.
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// scheduledStore is the ScheduledRepository as used by the scheduler.
+type scheduledStore interface {
+	Create(userID, sessionID, kind string, payload []byte, sendAt time.Time, timezone string) (*model.ScheduledMessage, error)
+	GetByID(sessionID, id string) (*model.ScheduledMessage, error)
+	ListBySession(sessionID, status string) ([]model.ScheduledMessage, error)
+	ClaimDue(limit int) ([]model.ScheduledMessage, error)
+	Touch(id string) (bool, error)
+	MarkSent(id, messageID string) (bool, error)
+	MarkFailed(id, reason string) (bool, error)
+	FailStale(olderThan time.Duration) error
+	Reschedule(sessionID, id string, sendAt time.Time, timezone string) (bool, error)
+	Cancel(sessionID, id string) (bool, error)
+}
+
+// Scheduler sends scheduled messages once they are due. Messages are claimed
+// in the database before sending, so several instances can run side by side.
+type Scheduler struct {
+	repo   scheduledStore
+	sendFn func(ctx context.Context, userID, sessionID, kind string, payload json.RawMessage) (map[string]interface{}, error)
+	emit   func(userID, sessionID, eventType string, data interface{})
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(repo *repository.ScheduledRepository, messageService *MessageService) *Scheduler {
+	return &Scheduler{
+		repo:   repo,
+		sendFn: messageService.Send,
+		emit:   messageService.sessionService.handleEvent,
+		stopCh: make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go s.processLoop()
+	logger.Component(schedulerComponent).Str("status", "running").Msg("scheduler started")
+}
+
+func (s *Scheduler) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+	logger.Component(schedulerComponent).Str("status", "stopped").Msg("scheduler stopped")
+}
+
+func (s *Scheduler) Create(userID, sessionID string, req *model.ScheduleRequest) (*model.ScheduledMessage, error) {
+	if err := validateSendPayload(req.Kind, req.Payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+
+	sendAt, timezone, err := parseSendAt(req.SendAt, req.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+
+	msg, err := s.repo.Create(userID, sessionID, req.Kind, req.Payload, sendAt, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	return msg, nil
+}
+
+func (s *Scheduler) List(sessionID, status string) ([]model.ScheduledMessage, error) {
+	return s.repo.ListBySession(sessionID, status)
+}
+
+func (s *Scheduler) Get(sessionID, id string) (*model.ScheduledMessage, error) {
+	msg, err := s.repo.GetByID(sessionID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled message: %w", err)
+	}
+	return msg, nil
+}
+
+func (s *Scheduler) Reschedule(sessionID, id string, req *model.RescheduleRequest) (*model.ScheduledMessage, error) {
+	sendAt, timezone, err := parseSendAt(req.SendAt, req.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+
+	updated, err := s.repo.Reschedule(sessionID, id, sendAt, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reschedule message: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w or no longer scheduled", ErrScheduleNotFound)
+	}
+
+	return s.Get(sessionID, id)
+}
+
+func (s *Scheduler) Cancel(sessionID, id string) error {
+	canceled, err := s.repo.Cancel(sessionID, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+	if !canceled {
+		return fmt.Errorf("%w or no longer scheduled", ErrScheduleNotFound)
+	}
+	return nil
+}
+
+func (s *Scheduler) processLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(schedulerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.processDue()
+		}
+	}
+}
+
+func (s *Scheduler) processDue() {
+	if err := s.repo.FailStale(schedulerStaleAfter); err != nil {
+		logger.WarnComponent(schedulerComponent).Err(err).Msg("failed to expire stale messages")
+	}
+
+	msgs, err := s.repo.ClaimDue(schedulerBatchSize)
+	if err != nil {
+		logger.WithError(err).Str("component", schedulerComponent).Msg("failed to claim due messages")
+		return
+	}
+
+	// Sessions are sent in parallel by a bounded number of workers, so one
+	// slow session cannot hold up the others. Messages of a session are sent
+	// one after the other, in the order they were due.
+	var sessions []string
+	bySession := make(map[string][]*model.ScheduledMessage)
+	for i := range msgs {
+		sessionID := msgs[i].SessionID
+		if _, ok := bySession[sessionID]; !ok {
+			sessions = append(sessions, sessionID)
+		}
+		bySession[sessionID] = append(bySession[sessionID], &msgs[i])
+	}
+
+	sem := make(chan struct{}, schedulerWorkers)
+	var wg sync.WaitGroup
+	for _, sessionID := range sessions {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(batch []*model.ScheduledMessage) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			for _, msg := range batch {
+				s.send(msg)
+			}
+		}(bySession[sessionID])
+	}
+	wg.Wait()
+}
+
+// send sends a claimed message. The claim is refreshed first, so the messages
+// queued behind it in a batch do not go stale, and a message that was failed
+// as stale in the meantime is not sent at all.
+func (s *Scheduler) send(msg *model.ScheduledMessage) {
+	claimed, err := s.repo.Touch(msg.ID)
+	if err != nil {
+		logger.WarnComponent(schedulerComponent).Str("id", msg.ID).Err(err).Msg("failed to refresh claim")
+		return
+	}
+	if !claimed {
+		logger.WarnComponent(schedulerComponent).Str("id", msg.ID).Msg("claim expired, message skipped")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queueSendTimeout)
+	defer cancel()
+
+	data := map[string]interface{}{
+		"schedule_id": msg.ID,
+		"kind":        msg.Kind,
+		"phone":       payloadPhone(msg.Payload),
+	}
+
+	result, err := s.sendFn(ctx, msg.UserID, msg.SessionID, msg.Kind, msg.Payload)
+	if err != nil {
+		logger.WarnComponent(schedulerComponent).Str("id", msg.ID).Err(err).Msg("send failed")
+		s.mark(msg.ID, s.repo.MarkFailed, err.Error())
+		data["error"] = err.Error()
+		s.emit(msg.UserID, msg.SessionID, "ScheduledMessageFailed", data)
+		return
+	}
+
+	messageID, _ := result["id"].(string)
+	s.mark(msg.ID, s.repo.MarkSent, messageID)
+	data["id"] = messageID
+	data["timestamp"] = result["timestamp"]
+	s.emit(msg.UserID, msg.SessionID, "ScheduledMessageSent", data)
+}
+
+// mark records the outcome of a send, warning when the message was failed as
+// stale while it was being sent.
+func (s *Scheduler) mark(id string, update func(id, value string) (bool, error), value string) {
+	updated, err := update(id, value)
+	switch {
+	case err != nil:
+		logger.WarnComponent(schedulerComponent).Str("id", id).Err(err).Msg("failed to update status")
+	case !updated:
+		logger.WarnComponent(schedulerComponent).Str("id", id).Msg("claim expired while sending")
+	}
+}
+
+// parseSendAt parses a send_at value and returns it with the time zone name
+// it was given in. Times must lie in the future.
+func parseSendAt(value, timezone string) (time.Time, string, error) {
+	if value == "" {
+		return time.Time{}, "", errors.New("send_at is required")
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid timezone %q", timezone)
+	}
+
+	sendAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		for _, layout := range localTimeLayouts {
+			if sendAt, err = time.ParseInLocation(layout, value, loc); err == nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return time.Time{}, "", errors.New("invalid send_at, use RFC 3339 or 2006-01-02 15:04")
+	}
+
+	if !sendAt.After(time.Now()) {
+		return time.Time{}, "", errors.New("send_at must be in the future")
+	}
+
+	return sendAt, loc.String(), nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"fiozap/internal/model"
+)
+
+func TestParseSendAt(t *testing.T) {
+	future := time.Now().Add(48 * time.Hour)
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		timezone string
+		want     time.Time
+		wantZone string
+		wantErr  bool
+	}{
+		{
+			name:     "rfc3339 keeps its offset",
+			value:    future.UTC().Format(time.RFC3339),
+			timezone: "America/Sao_Paulo",
+			want:     future.UTC().Truncate(time.Second),
+			wantZone: "America/Sao_Paulo",
+		},
+		{
+			name:     "local time defaults to utc",
+			value:    future.UTC().Format("2006-01-02 15:04"),
+			want:     future.UTC().Truncate(time.Minute),
+			wantZone: "UTC",
+		},
+		{
+			name:     "local time in time zone",
+			value:    future.In(saoPaulo).Format("2006-01-02T15:04:05"),
+			timezone: "America/Sao_Paulo",
+			want:     future.Truncate(time.Second),
+			wantZone: "America/Sao_Paulo",
+		},
+		{name: "missing value", value: "", wantErr: true},
+		{name: "unknown time zone", value: future.Format(time.RFC3339), timezone: "Mars/Base", wantErr: true},
+		{name: "unparseable value", value: "tomorrow", wantErr: true},
+		{name: "past time", value: time.Now().Add(-time.Hour).Format(time.RFC3339), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, zone, err := parseSendAt(tt.value, tt.timezone)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseSendAt(%q, %q) = %v, want error", tt.value, tt.timezone, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSendAt(%q, %q) error = %v", tt.value, tt.timezone, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseSendAt(%q, %q) = %v, want %v", tt.value, tt.timezone, got, tt.want)
+			}
+			if zone != tt.wantZone {
+				t.Errorf("parseSendAt(%q, %q) zone = %q, want %q", tt.value, tt.timezone, zone, tt.wantZone)
+			}
+		})
+	}
+}
+
+// memoryScheduled is a scheduledStore with the status changes of the
+// repository queries, on a clock the tests move.
+type memoryScheduled struct {
+	mu   sync.Mutex
+	now  time.Time
+	msgs map[string]*model.ScheduledMessage
+}
+
+func newMemoryScheduled(now time.Time, msgs ...model.ScheduledMessage) *memoryScheduled {
+	m := &memoryScheduled{now: now, msgs: make(map[string]*model.ScheduledMessage)}
+	for i := range msgs {
+		msg := msgs[i]
+		if msg.Status == "" {
+			msg.Status = model.ScheduledStatusScheduled
+		}
+		m.msgs[msg.ID] = &msg
+	}
+	return m
+}
+
+func (m *memoryScheduled) advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
+
+func (m *memoryScheduled) get(id string) model.ScheduledMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.msgs[id]
+}
+
+func (m *memoryScheduled) Create(string, string, string, []byte, time.Time, string) (*model.ScheduledMessage, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryScheduled) GetByID(string, string) (*model.ScheduledMessage, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryScheduled) ListBySession(string, string) ([]model.ScheduledMessage, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryScheduled) Reschedule(string, string, time.Time, string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *memoryScheduled) Cancel(string, string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *memoryScheduled) ClaimDue(limit int) ([]model.ScheduledMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*model.ScheduledMessage
+	for _, msg := range m.msgs {
+		if msg.Status == model.ScheduledStatusScheduled && !msg.SendAt.After(m.now) {
+			due = append(due, msg)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].SendAt.Before(due[j].SendAt) })
+
+	claimed := []model.ScheduledMessage{}
+	for _, msg := range due {
+		if len(claimed) == limit {
+			break
+		}
+		msg.Status = model.ScheduledStatusSending
+		msg.UpdatedAt = m.now
+		claimed = append(claimed, *msg)
+	}
+	return claimed, nil
+}
+
+func (m *memoryScheduled) update(id string, change func(msg *model.ScheduledMessage)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg, ok := m.msgs[id]
+	if !ok || msg.Status != model.ScheduledStatusSending {
+		return false
+	}
+	change(msg)
+	msg.UpdatedAt = m.now
+	return true
+}
+
+func (m *memoryScheduled) Touch(id string) (bool, error) {
+	return m.update(id, func(*model.ScheduledMessage) {}), nil
+}
+
+func (m *memoryScheduled) MarkSent(id, messageID string) (bool, error) {
+	return m.update(id, func(msg *model.ScheduledMessage) {
+		msg.Status = model.ScheduledStatusSent
+		msg.MessageID = messageID
+	}), nil
+}
+
+func (m *memoryScheduled) MarkFailed(id, reason string) (bool, error) {
+	return m.update(id, func(msg *model.ScheduledMessage) {
+		msg.Status = model.ScheduledStatusFailed
+		msg.Error = reason
+	}), nil
+}
+
+func (m *memoryScheduled) FailStale(olderThan time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, msg := range m.msgs {
+		if msg.Status == model.ScheduledStatusSending && msg.UpdatedAt.Before(m.now.Add(-olderThan)) {
+			msg.Status = model.ScheduledStatusFailed
+			msg.Error = "interrupted while sending"
+			msg.UpdatedAt = m.now
+		}
+	}
+	return nil
+}
+
+func TestSchedulerProcessDue(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduled := func(id string, sendAt time.Time) model.ScheduledMessage {
+		return model.ScheduledMessage{
+			ID:        id,
+			UserID:    "user",
+			SessionID: "session",
+			Kind:      SendKindText,
+			Payload:   json.RawMessage(`{"phone":"5511999999999","message":"` + id + `"}`),
+			SendAt:    sendAt,
+		}
+	}
+
+	tests := []struct {
+		name string
+		msgs []model.ScheduledMessage
+		// send runs for each message sent, with the store of the test.
+		send       func(store *memoryScheduled, id string) error
+		wantStatus map[string]string
+		wantError  map[string]string
+		wantSent   []string
+		wantEvents []string
+	}{
+		{
+			name:       "sends due messages only",
+			msgs:       []model.ScheduledMessage{scheduled("a", start.Add(-time.Minute)), scheduled("b", start), scheduled("c", start.Add(time.Minute))},
+			wantStatus: map[string]string{"a": model.ScheduledStatusSent, "b": model.ScheduledStatusSent, "c": model.ScheduledStatusScheduled},
+			wantSent:   []string{"a", "b"},
+			wantEvents: []string{"ScheduledMessageSent", "ScheduledMessageSent"},
+		},
+		{
+			name: "records failed sends",
+			msgs: []model.ScheduledMessage{scheduled("a", start)},
+			send: func(*memoryScheduled, string) error {
+				return errors.New("not connected")
+			},
+			wantStatus: map[string]string{"a": model.ScheduledStatusFailed},
+			wantError:  map[string]string{"a": "not connected"},
+			wantSent:   []string{"a"},
+			wantEvents: []string{"ScheduledMessageFailed"},
+		},
+		{
+			name: "long batch is not failed as stale",
+			msgs: []model.ScheduledMessage{scheduled("a", start.Add(-3*time.Second)), scheduled("b", start.Add(-2*time.Second)), scheduled("c", start.Add(-time.Second))},
+			send: func(store *memoryScheduled, _ string) error {
+				store.advance(schedulerStaleAfter / 2)
+				return store.FailStale(schedulerStaleAfter)
+			},
+			wantStatus: map[string]string{"a": model.ScheduledStatusSent, "b": model.ScheduledStatusSent, "c": model.ScheduledStatusSent},
+			wantSent:   []string{"a", "b", "c"},
+			wantEvents: []string{"ScheduledMessageSent", "ScheduledMessageSent", "ScheduledMessageSent"},
+		},
+		{
+			name: "stale messages keep their failure",
+			msgs: []model.ScheduledMessage{scheduled("a", start.Add(-2*time.Second)), scheduled("b", start.Add(-time.Second))},
+			send: func(store *memoryScheduled, _ string) error {
+				store.advance(schedulerStaleAfter + time.Minute)
+				return store.FailStale(schedulerStaleAfter)
+			},
+			wantStatus: map[string]string{"a": model.ScheduledStatusFailed, "b": model.ScheduledStatusFailed},
+			wantError:  map[string]string{"a": "interrupted while sending", "b": "interrupted while sending"},
+			wantSent:   []string{"a"},
+			wantEvents: []string{"ScheduledMessageSent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryScheduled(start, tt.msgs...)
+			var sent, events []string
+			s := &Scheduler{
+				repo: store,
+				sendFn: func(_ context.Context, _, _, _ string, payload json.RawMessage) (map[string]interface{}, error) {
+					var req model.TextMessage
+					_ = json.Unmarshal(payload, &req)
+					sent = append(sent, req.Message)
+					if tt.send != nil {
+						if err := tt.send(store, req.Message); err != nil {
+							return nil, err
+						}
+					}
+					return map[string]interface{}{"id": "WA-" + req.Message}, nil
+				},
+				emit: func(_, _, eventType string, _ interface{}) {
+					events = append(events, eventType)
+				},
+			}
+
+			s.processDue()
+
+			if !slices.Equal(sent, tt.wantSent) {
+				t.Errorf("sent %v, want %v", sent, tt.wantSent)
+			}
+			if !slices.Equal(events, tt.wantEvents) {
+				t.Errorf("events %v, want %v", events, tt.wantEvents)
+			}
+			for id, want := range tt.wantStatus {
+				msg := store.get(id)
+				if msg.Status != want {
+					t.Errorf("%s status = %q, want %q", id, msg.Status, want)
+				}
+				if msg.Error != tt.wantError[id] {
+					t.Errorf("%s error = %q, want %q", id, msg.Error, tt.wantError[id])
+				}
+				if want == model.ScheduledStatusSent && msg.MessageID != "WA-"+id {
+					t.Errorf("%s message id = %q", id, msg.MessageID)
+				}
+			}
+		})
+	}
+}
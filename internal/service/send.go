@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"fiozap/internal/model"
+)
+
+// Send kinds name the send endpoints so requests can be stored and replayed
+// by the send queue and other deferred senders.
+const (
+	SendKindText     = "text"
+	SendKindImage    = "image"
+	SendKindAudio    = "audio"
+	SendKindVideo    = "video"
+	SendKindDocument = "document"
+	SendKindLocation = "location"
+	SendKindContact  = "contact"
+	SendKindSticker  = "sticker"
+	SendKindPoll     = "poll"
+	SendKindList     = "list"
+	SendKindButtons  = "buttons"
+)
+
+var errUploadNotQueueable = errors.New("multipart uploads cannot be queued, use a data or https URL")
+
+// sendRequest is implemented by every request embedding model.SendOptions.
+type sendRequest[T any] interface {
+	*T
+	GetSendOptions() *model.SendOptions
+}
+
+// Send decodes a stored send request of the given kind and sends it
+// synchronously.
+func (s *MessageService) Send(ctx context.Context, userID, sessionID, kind string, payload json.RawMessage) (map[string]interface{}, error) {
+	switch kind {
+	case SendKindText:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendText)
+	case SendKindImage:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendImage)
+	case SendKindAudio:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendAudio)
+	case SendKindVideo:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendVideo)
+	case SendKindDocument:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendDocument)
+	case SendKindLocation:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendLocation)
+	case SendKindContact:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendContact)
+	case SendKindSticker:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendSticker)
+	case SendKindPoll:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendPoll)
+	case SendKindList:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendList)
+	case SendKindButtons:
+		return sendPayload(ctx, userID, sessionID, payload, s.SendButtons)
+	default:
+		return nil, fmt.Errorf("unsupported send kind %q", kind)
+	}
+}
+
+func sendPayload[T any, R sendRequest[T]](ctx context.Context, userID, sessionID string, payload json.RawMessage, send func(context.Context, string, string, R) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	req := R(new(T))
+	if err := json.Unmarshal(payload, req); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	req.GetSendOptions().Queue = false
+
+	return send(ctx, userID, sessionID, req)
+}
+
+// enqueue stores a send request in the send queue and returns its queue id.
+func (s *MessageService) enqueue(userID, sessionID, kind string, req interface{}) (map[string]interface{}, error) {
+	if s.sendQueue == nil {
+		return nil, errors.New("send queue is disabled")
+	}
+	if r, ok := req.(interface{ GetSendOptions() *model.SendOptions }); ok {
+		if err := validateTyping(r.GetSendOptions().Typing); err != nil {
+			return nil, err
+		}
+	}
+
+	item, err := s.sendQueue.Enqueue(userID, sessionID, kind, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"queue_id": item.ID,
+		"status":   item.Status,
+	}, nil
+}
+
+// validateSendPayload checks that a deferred send names a known kind and a
+// recipient, so obvious mistakes are reported when it is stored.
+func validateSendPayload(kind string, payload json.RawMessage) error {
+	switch kind {
+	case SendKindText, SendKindImage, SendKindAudio, SendKindVideo, SendKindDocument, SendKindLocation,
+		SendKindContact, SendKindSticker, SendKindPoll, SendKindList, SendKindButtons:
+	default:
+		return fmt.Errorf("unsupported send kind %q", kind)
+	}
+
+	if !json.Valid(payload) {
+		return errors.New("invalid payload")
+	}
+	if payloadPhone(payload) == "" {
+		return errors.New("payload.phone is required")
+	}
+	return nil
+}
+
+// payloadPhone returns the recipient of a stored send request.
+func payloadPhone(payload json.RawMessage) string {
+	var target struct {
+		Phone string `json:"phone"`
+	}
+	_ = json.Unmarshal(payload, &target)
+	return target.Phone
+}
@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
 
 	"fiozap/internal/config"
 	"fiozap/internal/database/repository"
@@ -17,10 +20,41 @@ import (
 	"fiozap/internal/webhook"
 )
 
+// ErrSessionBanned is returned when connecting a session while a temporary
+// ban is active.
+var ErrSessionBanned = errors.New("session is temporarily banned")
+
+// ErrSessionNotLoggedIn is returned by account operations on a session that
+// is not paired.
+var ErrSessionNotLoggedIn = errors.New("session is not logged in")
+
+// ErrNoSession is returned when a session has no running WhatsApp client,
+// e.g. because it was never connected.
+var ErrNoSession = errors.New("no session")
+
+const (
+	messageStoreBuffer   = 1024
+	messagePruneInterval = time.Hour
+)
+
+// MessageHook receives every incoming message of a session after it is
+// stored. Hooks run in their own goroutine.
+type MessageHook func(userID, sessionID string, evt *events.Message)
+
+// PauseCheck reports whether automated answers are paused in a chat, for
+// example because a human took it over.
+type PauseCheck func(sessionID, chat string) bool
+
 type SessionService struct {
 	userRepo    *repository.UserRepository
 	sessionRepo *repository.SessionRepository
 	webhookRepo *repository.WebhookRepository
+	messageRepo *repository.MessageRepository
+	pollRepo    *repository.PollRepository
+	callRepo    *repository.CallRepository
+	hooks       []MessageHook
+	pauseChecks []PauseCheck
+	storeCh     chan storedEvent
 	clients     map[string]*wameow.Client // key: "userId:sessionId"
 	mu          sync.RWMutex
 	dbConnStr   string
@@ -48,6 +82,64 @@ func (s *SessionService) SetDispatcher(d *webhook.Dispatcher) {
 	s.dispatcher = d
 }
 
+// storedEvent is an incoming message waiting to be written to the message
+// store.
+type storedEvent struct {
+	userID    string
+	sessionID string
+	evt       *events.Message
+}
+
+// SetMessageRepo enables the message store. Messages are written by a single
+// worker off the event goroutine, in the order they arrive, and messages
+// older than retention are pruned every hour; 0 keeps them forever.
+func (s *SessionService) SetMessageRepo(repo *repository.MessageRepository, retention time.Duration) {
+	s.messageRepo = repo
+	s.storeCh = make(chan storedEvent, messageStoreBuffer)
+	go s.storeLoop(retention)
+}
+
+func (s *SessionService) SetPollRepo(repo *repository.PollRepository) {
+	s.pollRepo = repo
+}
+
+func (s *SessionService) SetCallRepo(repo *repository.CallRepository) {
+	s.callRepo = repo
+}
+
+// AddMessageHook registers a hook for incoming messages. Hooks must be added
+// before sessions connect.
+func (s *SessionService) AddMessageHook(hook MessageHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// SequentialHooks returns a hook that runs hooks one after the other, for
+// hooks whose effects depend on their order.
+func SequentialHooks(hooks ...MessageHook) MessageHook {
+	return func(userID, sessionID string, evt *events.Message) {
+		for _, hook := range hooks {
+			hook(userID, sessionID, evt)
+		}
+	}
+}
+
+// AddPauseCheck registers a check consulted by BotPaused. Checks must be
+// added before sessions connect.
+func (s *SessionService) AddPauseCheck(check PauseCheck) {
+	s.pauseChecks = append(s.pauseChecks, check)
+}
+
+// BotPaused reports whether automated answers are paused in a chat. Hooks
+// that answer contacts consult it before replying.
+func (s *SessionService) BotPaused(sessionID, chat string) bool {
+	for _, check := range s.pauseChecks {
+		if check(sessionID, chat) {
+			return true
+		}
+	}
+	return false
+}
+
 // CRUD operations for sessions
 func (s *SessionService) CreateSession(userID string, req *model.SessionCreateRequest) (*model.Session, error) {
 	user, err := s.userRepo.GetByID(userID)
@@ -111,6 +203,10 @@ func (s *SessionService) SessionBelongsToUser(sessionID, userID string) (bool, e
 
 // Connection operations
 func (s *SessionService) Connect(ctx context.Context, userID string, session *model.Session, immediate bool) (*model.Session, error) {
+	if session.Banned() {
+		return nil, fmt.Errorf("%w until %s", ErrSessionBanned, session.BannedUntil.Format(time.RFC3339))
+	}
+
 	s.mu.Lock()
 
 	key := s.clientKey(userID, session.ID)
@@ -132,6 +228,16 @@ func (s *SessionService) Connect(ctx context.Context, userID string, session *mo
 		s.handleEvent(userID, session.ID, eventType, data)
 	})
 
+	client.SetMessageCallback(func(evt *events.Message) {
+		s.handleMessage(userID, session.ID, client, evt)
+	})
+
+	client.SetCallCallback(func(evt *events.CallOffer) {
+		if s.callRepo != nil {
+			go s.handleCallOffer(userID, session.ID, client.GetClient(), evt)
+		}
+	})
+
 	client.SetQRCallback(func(code string) {
 		if err := s.sessionRepo.UpdateQRCode(session.ID, code); err != nil {
 			logger.Warnf("Failed to update QR code: %v", err)
@@ -192,6 +298,13 @@ func (s *SessionService) handleEvent(userID, sessionID, eventType string, data i
 				}
 			}
 		}
+		if err := s.sessionRepo.ClearAlert(sessionID); err != nil {
+			logger.Warnf("Failed to clear session alert: %v", err)
+		}
+	}
+
+	if eventType == "TemporaryBan" || eventType == "StreamReplaced" || eventType == "ConnectFailure" {
+		s.raiseAlert(userID, sessionID, eventType, data)
 	}
 
 	if eventType == "Disconnected" || eventType == "LoggedOut" {
@@ -201,6 +314,146 @@ func (s *SessionService) handleEvent(userID, sessionID, eventType string, data i
 	}
 }
 
+// raiseAlert records a ban, replaced stream or connect failure on the session
+// and sends a SessionAlert to its owner and to the operator alert URL.
+func (s *SessionService) raiseAlert(userID, sessionID, eventType string, data interface{}) {
+	info, _ := data.(map[string]interface{})
+	alert := map[string]interface{}{"event": eventType}
+
+	var (
+		kind        string
+		reason      string
+		bannedUntil *time.Time
+	)
+	switch eventType {
+	case "TemporaryBan":
+		kind = model.SessionAlertTemporaryBan
+		reason, _ = info["reason"].(string)
+		if secs, ok := info["expires"].(int64); ok && secs > 0 {
+			until := time.Now().Add(time.Duration(secs) * time.Second)
+			bannedUntil = &until
+			alert["bannedUntil"] = until.Unix()
+		}
+	case "StreamReplaced":
+		kind = model.SessionAlertStreamReplaced
+		reason = "another client connected with this session"
+	case "ConnectFailure":
+		kind = model.SessionAlertConnectFailure
+		reason, _ = info["reason"].(string)
+		if msg, _ := info["message"].(string); msg != "" {
+			reason += ": " + msg
+		}
+	}
+	alert["alert"] = kind
+	alert["reason"] = reason
+
+	logger.WarnComponent("session").Str("session_id", sessionID).Str("alert", kind).Str("reason", reason).Msg("session alert")
+
+	if err := s.sessionRepo.SetAlert(sessionID, kind, reason, bannedUntil); err != nil {
+		logger.Warnf("Failed to record session alert: %v", err)
+	}
+
+	if s.dispatcher != nil {
+		if err := s.dispatcher.EnqueueAlert(userID, sessionID, alert); err != nil {
+			logger.Warnf("Failed to enqueue session alert: %v", err)
+		}
+	}
+}
+
+func (s *SessionService) handleMessage(userID, sessionID string, client *wameow.Client, evt *events.Message) {
+	if s.pollRepo != nil {
+		s.handlePollMessage(userID, sessionID, client.GetClient(), evt)
+	}
+	if s.messageRepo != nil {
+		// Hooks run once the store worker has written the message.
+		s.storeCh <- storedEvent{userID: userID, sessionID: sessionID, evt: evt}
+		return
+	}
+	s.runHooks(userID, sessionID, evt)
+}
+
+func (s *SessionService) runHooks(userID, sessionID string, evt *events.Message) {
+	for _, hook := range s.hooks {
+		go hook(userID, sessionID, evt)
+	}
+}
+
+// storeLoop writes incoming messages and their edits, revokes and reactions
+// to the message store, one at a time so a change is applied after the
+// message it targets, and prunes expired messages.
+func (s *SessionService) storeLoop(retention time.Duration) {
+	ticker := time.NewTicker(messagePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-s.storeCh:
+			if change := wameow.ParseMessageChange(e.evt); change != nil {
+				s.applyMessageChange(e.sessionID, change)
+			} else {
+				s.storeMessage(e.sessionID, e.evt)
+			}
+			s.runHooks(e.userID, e.sessionID, e.evt)
+		case <-ticker.C:
+			if retention > 0 {
+				s.pruneMessages(retention)
+			}
+		}
+	}
+}
+
+func (s *SessionService) pruneMessages(retention time.Duration) {
+	n, err := s.messageRepo.DeleteOlderThan(time.Now().Add(-retention))
+	if err != nil {
+		logger.Warnf("Failed to prune stored messages: %v", err)
+		return
+	}
+	if n > 0 {
+		logger.Component("session").Int64("deleted", n).Msg("pruned stored messages")
+	}
+}
+
+func (s *SessionService) storeMessage(sessionID string, evt *events.Message) {
+	raw, err := proto.Marshal(evt.Message)
+	if err != nil {
+		logger.Warnf("Failed to marshal message %s: %v", evt.Info.ID, err)
+		return
+	}
+
+	msg := &model.StoredMessage{
+		ID:        evt.Info.ID,
+		SessionID: sessionID,
+		Chat:      evt.Info.Chat.String(),
+		Sender:    evt.Info.Sender.String(),
+		FromMe:    evt.Info.IsFromMe,
+		Type:      wameow.MessageType(evt),
+		Text:      wameow.MessageText(evt),
+		Raw:       raw,
+		Timestamp: evt.Info.Timestamp,
+	}
+
+	if err := s.messageRepo.Save(msg); err != nil {
+		logger.Warnf("Failed to store message %s: %v", evt.Info.ID, err)
+	}
+}
+
+// applyMessageChange updates a stored message after an edit, revoke or
+// reaction.
+func (s *SessionService) applyMessageChange(sessionID string, change *wameow.MessageChange) {
+	var err error
+	switch change.Kind {
+	case wameow.ChangeEdit:
+		err = s.messageRepo.Edit(sessionID, change.TargetID, change.Text, change.Timestamp)
+	case wameow.ChangeRevoke:
+		err = s.messageRepo.Revoke(sessionID, change.TargetID)
+	case wameow.ChangeReaction:
+		err = s.messageRepo.SetReaction(sessionID, change.TargetID, change.Actor, change.Text)
+	}
+	if err != nil {
+		logger.Warnf("Failed to apply %s to message %s: %v", change.Kind, change.TargetID, err)
+	}
+}
+
 func (s *SessionService) Disconnect(userID string, session *model.Session) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -208,7 +461,7 @@ func (s *SessionService) Disconnect(userID string, session *model.Session) error
 	key := s.clientKey(userID, session.ID)
 	client, exists := s.clients[key]
 	if !exists {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	if !client.IsConnected() {
@@ -232,7 +485,7 @@ func (s *SessionService) Logout(ctx context.Context, userID string, session *mod
 	key := s.clientKey(userID, session.ID)
 	client, exists := s.clients[key]
 	if !exists {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	if !client.IsConnected() || !client.IsLoggedIn() {
@@ -278,6 +531,8 @@ func (s *SessionService) GetStatus(userID string, session *model.Session) map[st
 		"jid":       session.JID,
 		"webhook":   session.Webhook,
 		"events":    session.Events,
+		"alert":     session.Alert,
+		"banned":    session.Banned(),
 	}
 }
 
@@ -340,6 +595,10 @@ func (s *SessionService) ReconnectAll(ctx context.Context) {
 	logger.Component("session").Int("count", len(sessions)).Msg("reconnecting")
 
 	for _, session := range sessions {
+		if session.Banned() {
+			logger.WarnComponent("session").Str("session_id", session.ID).Msg("banned, not reconnecting")
+			continue
+		}
 		go func(sess model.Session) {
 			_, err := s.Connect(ctx, sess.UserID, &sess, true)
 			if err != nil {
@@ -372,3 +631,16 @@ func (s *SessionService) GetWhatsmeowClient(userID, sessionID string) *whatsmeow
 	}
 	return nil
 }
+
+// ownClient returns the client of a logged in session with the JID of its
+// account.
+func (s *SessionService) ownClient(userID, sessionID string) (*whatsmeow.Client, types.JID, error) {
+	client := s.GetWhatsmeowClient(userID, sessionID)
+	if client == nil {
+		return nil, types.EmptyJID, ErrNoSession
+	}
+	if client.Store.ID == nil {
+		return nil, types.EmptyJID, ErrSessionNotLoggedIn
+	}
+	return client, client.Store.ID.ToNonAD(), nil
+}
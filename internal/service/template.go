@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"fiozap/internal/database/repository"
+	"fiozap/internal/model"
+)
+
+// templateKind is the kind of automated sends that render a stored template
+// instead of carrying their own payload.
+const templateKind = "template"
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrMissingVariables is returned when a template is sent without a value
+	// for one of its placeholders.
+	ErrMissingVariables = errors.New("missing variables")
+)
+
+// TemplateService stores message templates per user and sends them rendered
+// with the variables of each request.
+type TemplateService struct {
+	repo           *repository.TemplateRepository
+	messageService *MessageService
+}
+
+func NewTemplateService(repo *repository.TemplateRepository, messageService *MessageService) *TemplateService {
+	return &TemplateService{
+		repo:           repo,
+		messageService: messageService,
+	}
+}
+
+func (s *TemplateService) Create(userID string, req *model.TemplateRequest) (*model.Template, error) {
+	if err := validateTemplate(req); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.repo.Create(userID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return withVariables(tmpl), nil
+}
+
+func (s *TemplateService) List(userID string) ([]model.Template, error) {
+	templates, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		withVariables(&templates[i])
+	}
+	return templates, nil
+}
+
+func (s *TemplateService) Get(userID, id string) (*model.Template, error) {
+	tmpl, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	return withVariables(tmpl), nil
+}
+
+func (s *TemplateService) Update(userID, id string, req *model.TemplateRequest) (*model.Template, error) {
+	if err := validateTemplate(req); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.repo.Update(userID, id, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+	if tmpl == nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	return withVariables(tmpl), nil
+}
+
+func (s *TemplateService) Delete(userID, id string) error {
+	deleted, err := s.repo.Delete(userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	if !deleted {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// Send renders a template for req.Phone and sends it through the send method
+// of its kind. Nothing is sent when a variable is missing.
+func (s *TemplateService) Send(ctx context.Context, userID, sessionID string, req *model.TemplateSendRequest) (map[string]interface{}, error) {
+	var (
+		tmpl *model.Template
+		err  error
+	)
+	switch {
+	case req.TemplateID != "":
+		tmpl, err = s.repo.GetByID(userID, req.TemplateID)
+	case req.TemplateName != "":
+		tmpl, err = s.repo.GetByName(userID, req.TemplateName)
+	default:
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	vars := map[string]string{}
+	_ = json.Unmarshal(tmpl.Defaults, &vars)
+	vars["phone"] = req.Phone
+	for name, value := range req.Variables {
+		vars[name] = value
+	}
+
+	var missing []string
+	for _, name := range placeholderNames(tmpl.Payload) {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingVariables, strings.Join(missing, ", "))
+	}
+
+	payload, err := renderPayload(tmpl.Payload, req.Phone, vars)
+	if err != nil {
+		return nil, err
+	}
+	if payload, err = withSendOptions(payload, &req.SendOptions); err != nil {
+		return nil, err
+	}
+
+	if req.Queue {
+		return s.messageService.enqueue(userID, sessionID, tmpl.Kind, payload)
+	}
+	return s.messageService.Send(ctx, userID, sessionID, tmpl.Kind, payload)
+}
+
+func validateTemplate(req *model.TemplateRequest) error {
+	if req.Name == "" {
+		return errors.New("name is required")
+	}
+
+	// The phone is given when sending, so validate the payload as it will be sent.
+	payload, err := renderPayload(req.Payload, "template", req.Defaults)
+	if err != nil {
+		return err
+	}
+	return validateSendPayload(req.Kind, payload)
+}
+
+func withVariables(tmpl *model.Template) *model.Template {
+	tmpl.Variables = placeholderNames(tmpl.Payload)
+	return tmpl
+}
+
+// withSendOptions adds the send options given with a template send to the
+// rendered payload.
+func withSendOptions(payload json.RawMessage, opts *model.SendOptions) (json.RawMessage, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	raw, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(doc)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+
+	"fiozap/internal/model"
+)
+
+const (
+	typingMinDuration = time.Second
+	typingMaxDuration = 30 * time.Second
+	typingMaxWPM      = 1000
+)
+
+// validateTyping checks the typing option of a send request.
+func validateTyping(t *model.Typing) error {
+	if t == nil {
+		return nil
+	}
+	if t.DurationMs < 0 || time.Duration(t.DurationMs)*time.Millisecond > typingMaxDuration {
+		return fmt.Errorf("typing.duration_ms must be between 0 and %d", typingMaxDuration.Milliseconds())
+	}
+	if t.WPM < 0 || t.WPM > typingMaxWPM {
+		return fmt.Errorf("typing.wpm must be between 0 and %d", typingMaxWPM)
+	}
+	if t.DurationMs == 0 && t.WPM == 0 {
+		return errors.New("typing requires duration_ms or wpm")
+	}
+	return nil
+}
+
+// typingDuration is how long to type text. Durations derived from the words
+// per minute are kept between one and thirty seconds.
+func typingDuration(t *model.Typing, text string) time.Duration {
+	if t.DurationMs > 0 {
+		return time.Duration(t.DurationMs) * time.Millisecond
+	}
+
+	words := len(strings.Fields(text))
+	d := time.Duration(words) * time.Minute / time.Duration(t.WPM)
+	return min(max(d, typingMinDuration), typingMaxDuration)
+}
+
+// simulateTyping shows the typing indicator in the chat of a send request
+// with a typing option, waits, and clears it again before the message is
+// delivered. Voice notes show recording instead.
+func simulateTyping(ctx context.Context, client *whatsmeow.Client, recipient types.JID, opts *model.SendOptions, text string, media types.ChatPresenceMedia) error {
+	if opts.Typing == nil {
+		return nil
+	}
+	if err := validateTyping(opts.Typing); err != nil {
+		return err
+	}
+
+	if err := client.SendChatPresence(ctx, recipient, types.ChatPresenceComposing, media); err != nil {
+		return fmt.Errorf("failed to send typing: %w", err)
+	}
+
+	timer := time.NewTimer(typingDuration(opts.Typing, text))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := client.SendChatPresence(ctx, recipient, types.ChatPresencePaused, media); err != nil {
+		return fmt.Errorf("failed to send typing: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fiozap/internal/model"
+)
+
+func TestTypingDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		typing model.Typing
+		text   string
+		want   time.Duration
+	}{
+		{name: "explicit duration", typing: model.Typing{DurationMs: 2500}, text: "hello", want: 2500 * time.Millisecond},
+		{name: "explicit duration wins over wpm", typing: model.Typing{DurationMs: 500, WPM: 1}, text: "hello world", want: 500 * time.Millisecond},
+		{name: "words per minute", typing: model.Typing{WPM: 60}, text: "one two three four five", want: 5 * time.Second},
+		{name: "short text takes the minimum", typing: model.Typing{WPM: 60}, text: "hi", want: typingMinDuration},
+		{name: "empty text takes the minimum", typing: model.Typing{WPM: 40}, want: typingMinDuration},
+		{name: "long text takes the maximum", typing: model.Typing{WPM: 10}, text: strings.Repeat("word ", 100), want: typingMaxDuration},
+		{name: "whitespace does not count", typing: model.Typing{WPM: 30}, text: "  one\n\ttwo   three  ", want: 6 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typingDuration(&tt.typing, tt.text); got != tt.want {
+				t.Errorf("typingDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,8 +8,12 @@ import (
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
 )
 
+// ErrInvalidPhone is returned when a phone number or JID cannot be parsed.
+var ErrInvalidPhone = errors.New("invalid phone")
+
 type UserService struct {
 	sessionService *SessionService
 }
@@ -21,7 +25,7 @@ func NewUserService(sessionService *SessionService) *UserService {
 func (s *UserService) GetInfo(ctx context.Context, userID, sessionID string, phones []string) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	var jids []types.JID
@@ -60,7 +64,7 @@ func (s *UserService) GetInfo(ctx context.Context, userID, sessionID string, pho
 func (s *UserService) CheckUser(ctx context.Context, userID, sessionID string, phones []string) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	resp, err := client.IsOnWhatsApp(ctx, phones)
@@ -84,7 +88,7 @@ func (s *UserService) CheckUser(ctx context.Context, userID, sessionID string, p
 func (s *UserService) GetAvatar(ctx context.Context, userID, sessionID string, phone string, preview bool) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	jid, err := parseUserJID(phone)
@@ -117,7 +121,7 @@ func (s *UserService) GetAvatar(ctx context.Context, userID, sessionID string, p
 func (s *UserService) GetContacts(ctx context.Context, userID, sessionID string) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	contacts, err := client.Store.Contacts.GetAllContacts(ctx)
@@ -142,7 +146,7 @@ func (s *UserService) GetContacts(ctx context.Context, userID, sessionID string)
 func (s *UserService) SendPresence(ctx context.Context, userID, sessionID string, presence string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	var p types.Presence
@@ -161,7 +165,7 @@ func (s *UserService) SendPresence(ctx context.Context, userID, sessionID string
 func (s *UserService) ChatPresence(ctx context.Context, userID, sessionID string, phone, state, media string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := parseUserJID(phone)
@@ -193,7 +197,7 @@ func (s *UserService) ChatPresence(ctx context.Context, userID, sessionID string
 func (s *UserService) RejectCall(ctx context.Context, userID, sessionID string, callFrom, callID string) error {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return errors.New("no session")
+		return ErrNoSession
 	}
 
 	jid, err := parseUserJID(callFrom)
@@ -207,7 +211,7 @@ func (s *UserService) RejectCall(ctx context.Context, userID, sessionID string,
 func (s *UserService) GetNewsletters(ctx context.Context, userID, sessionID string) ([]map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	newsletters, err := client.GetSubscribedNewsletters(ctx)
@@ -237,7 +241,7 @@ func (s *UserService) GetNewsletters(ctx context.Context, userID, sessionID stri
 func (s *UserService) GetUserLID(ctx context.Context, userID, sessionID string, phone string) (map[string]interface{}, error) {
 	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
 	if client == nil {
-		return nil, errors.New("no session")
+		return nil, ErrNoSession
 	}
 
 	jid, err := parseUserJID(phone)
@@ -260,6 +264,66 @@ func (s *UserService) GetUserLID(ctx context.Context, userID, sessionID string,
 	}, nil
 }
 
+// GetBlocklist returns the JIDs blocked by the session account.
+func (s *UserService) GetBlocklist(ctx context.Context, userID, sessionID string) ([]string, error) {
+	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
+	if client == nil {
+		return nil, ErrNoSession
+	}
+
+	blocklist, err := client.GetBlocklist(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocklist: %w", err)
+	}
+
+	return blocklistJIDs(blocklist), nil
+}
+
+// UpdateBlocklist blocks or unblocks contacts and returns the resulting block
+// list. All numbers are parsed before the list is changed.
+func (s *UserService) UpdateBlocklist(ctx context.Context, userID, sessionID string, phones []string, block bool) ([]string, error) {
+	jids := make([]types.JID, 0, len(phones))
+	for _, phone := range phones {
+		jid, err := parseUserJID(phone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPhone, phone, err)
+		}
+		jids = append(jids, jid)
+	}
+
+	client := s.sessionService.GetWhatsmeowClient(userID, sessionID)
+	if client == nil {
+		return nil, ErrNoSession
+	}
+
+	action := events.BlocklistChangeActionUnblock
+	if block {
+		action = events.BlocklistChangeActionBlock
+	}
+
+	var blocklist *types.Blocklist
+	for _, jid := range jids {
+		var err error
+		blocklist, err = client.UpdateBlocklist(ctx, jid, action)
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s %s: %w", action, jid, err)
+		}
+	}
+
+	return blocklistJIDs(blocklist), nil
+}
+
+func blocklistJIDs(blocklist *types.Blocklist) []string {
+	jids := make([]string, 0)
+	if blocklist == nil {
+		return jids
+	}
+	for _, jid := range blocklist.JIDs {
+		jids = append(jids, jid.String())
+	}
+	return jids
+}
+
 func parseUserJID(phone string) (types.JID, error) {
 	if phone == "" {
 		return types.JID{}, errors.New("phone is required")
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fiozap/internal/model"
+)
+
+const clockLayout = "15:04"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// validateWindow checks the clock times, time zone and days of a window.
+func validateWindow(w *model.TimeWindow) error {
+	if w == nil {
+		return nil
+	}
+	if _, err := time.Parse(clockLayout, w.Start); err != nil {
+		return errors.New("window.start must be HH:MM")
+	}
+	if _, err := time.Parse(clockLayout, w.End); err != nil {
+		return errors.New("window.end must be HH:MM")
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("invalid window.timezone %q", w.Timezone)
+	}
+	for _, day := range w.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid window day %q", day)
+		}
+	}
+	return nil
+}
+
+// windowContains reports whether t falls inside the window. A nil window
+// always matches. The window must have been validated.
+func windowContains(w *model.TimeWindow, t time.Time) bool {
+	if w == nil {
+		return true
+	}
+
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+
+	start, _ := time.Parse(clockLayout, w.Start)
+	end, _ := time.Parse(clockLayout, w.End)
+	now := t.Hour()*60 + t.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	// For windows spanning midnight the part after midnight belongs to the
+	// previous day.
+	day := t.Weekday()
+	inside := false
+	switch {
+	case from <= to:
+		inside = now >= from && now < to
+	case now >= from:
+		inside = true
+	case now < to:
+		inside = true
+		day = (day + 6) % 7
+	}
+
+	return inside && windowHasDay(w, day)
+}
+
+func windowHasDay(w *model.TimeWindow, day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
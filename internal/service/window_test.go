@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"fiozap/internal/model"
+)
+
+func TestWindowContains(t *testing.T) {
+	// 2025-01-06 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	business := &model.TimeWindow{Start: "09:00", End: "18:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}
+	night := &model.TimeWindow{Start: "22:00", End: "06:00", Days: []string{"Fri"}}
+	saoPaulo := &model.TimeWindow{Start: "09:00", End: "18:00", Timezone: "America/Sao_Paulo"}
+
+	tests := []struct {
+		name   string
+		window *model.TimeWindow
+		t      time.Time
+		want   bool
+	}{
+		{"nil window always matches", nil, at(5, 3, 0), true},
+		{"inside business hours", business, at(6, 10, 30), true},
+		{"start is inclusive", business, at(6, 9, 0), true},
+		{"end is exclusive", business, at(6, 18, 0), false},
+		{"before start", business, at(6, 8, 59), false},
+		{"weekend", business, at(4, 10, 0), false},
+		{"overnight before midnight", night, at(10, 23, 0), true},
+		{"overnight after midnight belongs to previous day", night, at(11, 5, 0), true},
+		{"overnight after midnight of another day", night, at(10, 5, 0), false},
+		{"overnight outside", night, at(10, 12, 0), false},
+		{"time zone is applied", saoPaulo, at(6, 12, 30), true},
+		{"time zone shifts the window", saoPaulo, at(6, 10, 30), false},
+		{"invalid time zone never matches", &model.TimeWindow{Start: "00:00", End: "23:59", Timezone: "Mars/Base"}, at(6, 12, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowContains(tt.window, tt.t); got != tt.want {
+				t.Errorf("windowContains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
 
 	"fiozap/internal/logger"
@@ -18,25 +19,34 @@ const (
 	qrEventCode    = "code"
 	qrEventTimeout = "timeout"
 
-	msgTypeText     = "text"
-	msgTypeImage    = "image"
-	msgTypeVideo    = "video"
-	msgTypeAudio    = "audio"
-	msgTypeDocument = "document"
-	msgTypeSticker  = "sticker"
-	msgTypeContact  = "contact"
-	msgTypeLocation = "location"
-	msgTypeReaction = "reaction"
-	msgTypeUnknown  = "unknown"
+	msgTypeText          = "text"
+	msgTypeImage         = "image"
+	msgTypeVideo         = "video"
+	msgTypeAudio         = "audio"
+	msgTypeDocument      = "document"
+	msgTypeSticker       = "sticker"
+	msgTypeContact       = "contact"
+	msgTypeLocation      = "location"
+	msgTypeReaction      = "reaction"
+	msgTypeEdit          = "edit"
+	msgTypeRevoke        = "revoke"
+	msgTypePoll          = "poll"
+	msgTypePollVote      = "poll_vote"
+	msgTypeListReply     = "list_reply"
+	msgTypeButtonsReply  = "buttons_reply"
+	msgTypeTemplateReply = "template_reply"
+	msgTypeUnknown       = "unknown"
 )
 
 type EventCallback func(eventType string, data interface{})
 
 type Client struct {
-	wac           *whatsmeow.Client
-	userID        string
-	eventCallback EventCallback
-	qrCallback    func(string)
+	wac             *whatsmeow.Client
+	userID          string
+	eventCallback   EventCallback
+	qrCallback      func(string)
+	messageCallback func(*events.Message)
+	callCallback    func(*events.CallOffer)
 }
 
 func NewClient(ctx context.Context, postgresConnStr string, userID string) (*Client, error) {
@@ -63,6 +73,14 @@ func (c *Client) GetClient() *whatsmeow.Client      { return c.wac }
 func (c *Client) IsConnected() bool                 { return c.wac.IsConnected() }
 func (c *Client) IsLoggedIn() bool                  { return c.wac.IsLoggedIn() }
 
+// SetMessageCallback registers a hook that receives every raw incoming
+// message before it is emitted, e.g. to persist it in the message store.
+func (c *Client) SetMessageCallback(cb func(*events.Message)) { c.messageCallback = cb }
+
+// SetCallCallback registers a hook that receives every incoming call offer
+// after it is emitted, e.g. to reject it automatically.
+func (c *Client) SetCallCallback(cb func(*events.CallOffer)) { c.callCallback = cb }
+
 func (c *Client) GetJID() types.JID {
 	if c.wac.Store.ID != nil {
 		return *c.wac.Store.ID
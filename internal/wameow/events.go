@@ -1,6 +1,10 @@
 package wameow
 
 import (
+	"slices"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types/events"
 
 	"fiozap/internal/logger"
@@ -19,8 +23,59 @@ const (
 	eventCallOffer    = "CallOffer"
 	eventGroupInfo    = "GroupInfo"
 	eventJoinedGroup  = "JoinedGroup"
+
+	eventUndecryptableMessage = "UndecryptableMessage"
+	eventPushName             = "PushName"
+	eventPicture              = "Picture"
+	eventUserAbout            = "UserAbout"
+	eventIdentityChange       = "IdentityChange"
+	eventBlocklist            = "Blocklist"
+	eventOfflineSyncCompleted = "OfflineSyncCompleted"
+	eventKeepAliveTimeout     = "KeepAliveTimeout"
+	eventKeepAliveRestored    = "KeepAliveRestored"
+	eventStreamReplaced       = "StreamReplaced"
+	eventTemporaryBan         = "TemporaryBan"
+	eventConnectFailure       = "ConnectFailure"
+	eventCallAccept           = "CallAccept"
+	eventCallTerminate        = "CallTerminate"
+	eventNewsletterJoin       = "NewsletterJoin"
+	eventNewsletterLeave      = "NewsletterLeave"
+	eventNewsletterLiveUpdate = "NewsletterLiveUpdate"
+	eventMute                 = "Mute"
+	eventPin                  = "Pin"
+	eventArchive              = "Archive"
+	eventStar                 = "Star"
+	eventDeleteChat           = "DeleteChat"
+	eventClearChat            = "ClearChat"
+	eventMarkChatAsRead       = "MarkChatAsRead"
+
+	eventPrivacySettingsChanged = "PrivacySettingsChanged"
+
+	eventInteractiveReply = "InteractiveReply"
+	eventMessageEdited    = "MessageEdited"
+	eventMessageRevoked   = "MessageRevoked"
+	eventMessageReaction  = "MessageReaction"
 )
 
+// Kinds of MessageChange.
+const (
+	ChangeEdit     = "edit"
+	ChangeRevoke   = "revoke"
+	ChangeReaction = "reaction"
+)
+
+// MessageChange is an edit, revoke or reaction that targets an earlier
+// message. Text is the new text of an edit or the emoji of a reaction, empty
+// when a reaction was removed.
+type MessageChange struct {
+	Kind      string
+	TargetID  string
+	Chat      string
+	Actor     string
+	Text      string
+	Timestamp time.Time
+}
+
 func (c *Client) eventHandler(evt interface{}) {
 	switch v := evt.(type) {
 	case *events.Message:
@@ -45,6 +100,56 @@ func (c *Client) eventHandler(evt interface{}) {
 		c.handleGroupInfo(v)
 	case *events.JoinedGroup:
 		c.handleJoinedGroup(v)
+	case *events.UndecryptableMessage:
+		c.handleUndecryptableMessage(v)
+	case *events.PushName:
+		c.handlePushName(v)
+	case *events.Picture:
+		c.handlePicture(v)
+	case *events.UserAbout:
+		c.handleUserAbout(v)
+	case *events.IdentityChange:
+		c.handleIdentityChange(v)
+	case *events.Blocklist:
+		c.handleBlocklist(v)
+	case *events.OfflineSyncCompleted:
+		c.emit(eventOfflineSyncCompleted, map[string]interface{}{"count": v.Count})
+	case *events.KeepAliveTimeout:
+		c.handleKeepAliveTimeout(v)
+	case *events.KeepAliveRestored:
+		c.emit(eventKeepAliveRestored, nil)
+	case *events.StreamReplaced:
+		c.handleStreamReplaced()
+	case *events.TemporaryBan:
+		c.handleTemporaryBan(v)
+	case *events.ConnectFailure:
+		c.handleConnectFailure(v)
+	case *events.CallAccept:
+		c.handleCallAccept(v)
+	case *events.CallTerminate:
+		c.handleCallTerminate(v)
+	case *events.NewsletterJoin:
+		c.handleNewsletterJoin(v)
+	case *events.NewsletterLeave:
+		c.handleNewsletterLeave(v)
+	case *events.NewsletterLiveUpdate:
+		c.handleNewsletterLiveUpdate(v)
+	case *events.Mute:
+		c.handleMute(v)
+	case *events.Pin:
+		c.handlePin(v)
+	case *events.Archive:
+		c.handleArchive(v)
+	case *events.Star:
+		c.handleStar(v)
+	case *events.DeleteChat:
+		c.handleDeleteChat(v)
+	case *events.ClearChat:
+		c.handleClearChat(v)
+	case *events.MarkChatAsRead:
+		c.handleMarkChatAsRead(v)
+	case *events.PrivacySettings:
+		c.handlePrivacySettings(v)
 	}
 }
 
@@ -60,6 +165,10 @@ func (c *Client) handleMessage(v *events.Message) {
 		"message": v.Message,
 	}))
 
+	if c.messageCallback != nil {
+		c.messageCallback(v)
+	}
+
 	c.emit(eventMessage, map[string]interface{}{
 		"from":         v.Info.Sender.String(),
 		"chat":         v.Info.Chat.String(),
@@ -68,10 +177,129 @@ func (c *Client) handleMessage(v *events.Message) {
 		"pushName":     v.Info.PushName,
 		"isGroup":      v.Info.IsGroup,
 		"isFromMe":     v.Info.IsFromMe,
-		"type":         getMessageType(v),
+		"type":         MessageType(v),
 		"text":         v.Message.GetConversation(),
 		"extendedText": getExtendedText(v),
 	})
+
+	if reply := interactiveReply(v); reply != nil {
+		c.emit(eventInteractiveReply, reply)
+	}
+
+	if change := ParseMessageChange(v); change != nil {
+		c.emitChange(v, change)
+	}
+}
+
+func (c *Client) emitChange(v *events.Message, change *MessageChange) {
+	data := map[string]interface{}{
+		"id":         v.Info.ID,
+		"message_id": change.TargetID,
+		"chat":       change.Chat,
+		"from":       change.Actor,
+		"isFromMe":   v.Info.IsFromMe,
+		"timestamp":  change.Timestamp.Unix(),
+	}
+
+	switch change.Kind {
+	case ChangeEdit:
+		data["text"] = change.Text
+		c.emit(eventMessageEdited, data)
+	case ChangeRevoke:
+		c.emit(eventMessageRevoked, data)
+	case ChangeReaction:
+		data["emoji"] = change.Text
+		data["removed"] = change.Text == ""
+		c.emit(eventMessageReaction, data)
+	}
+}
+
+// ParseMessageChange returns the change a message makes to an earlier one,
+// or nil for ordinary messages.
+func ParseMessageChange(v *events.Message) *MessageChange {
+	if v.Message == nil {
+		return nil
+	}
+
+	change := &MessageChange{
+		Chat:      v.Info.Chat.String(),
+		Actor:     v.Info.Sender.String(),
+		Timestamp: v.Info.Timestamp,
+	}
+
+	m := v.Message
+	switch {
+	case m.ProtocolMessage != nil:
+		pm := m.ProtocolMessage
+		switch pm.GetType() {
+		case waE2E.ProtocolMessage_MESSAGE_EDIT:
+			change.Kind = ChangeEdit
+			change.Text = messageText(pm.GetEditedMessage())
+			if ms := pm.GetTimestampMS(); ms > 0 {
+				change.Timestamp = time.UnixMilli(ms)
+			}
+		case waE2E.ProtocolMessage_REVOKE:
+			change.Kind = ChangeRevoke
+		default:
+			return nil
+		}
+		change.TargetID = pm.GetKey().GetID()
+	case m.ReactionMessage != nil:
+		change.Kind = ChangeReaction
+		change.TargetID = m.ReactionMessage.GetKey().GetID()
+		change.Text = m.ReactionMessage.GetText()
+		if ms := m.ReactionMessage.GetSenderTimestampMS(); ms > 0 {
+			change.Timestamp = time.UnixMilli(ms)
+		}
+	default:
+		return nil
+	}
+
+	return change
+}
+
+// interactiveReply describes the option chosen in a reply to a list, buttons
+// or template message, or returns nil for other messages. message_id is the
+// id of the message that offered the options.
+func interactiveReply(v *events.Message) map[string]interface{} {
+	if v.Message == nil {
+		return nil
+	}
+
+	reply := map[string]interface{}{
+		"from":      v.Info.Sender.String(),
+		"chat":      v.Info.Chat.String(),
+		"id":        v.Info.ID,
+		"timestamp": v.Info.Timestamp.Unix(),
+	}
+
+	m := v.Message
+	switch {
+	case m.ListResponseMessage != nil:
+		r := m.ListResponseMessage
+		reply["type"] = msgTypeListReply
+		reply["row_id"] = r.GetSingleSelectReply().GetSelectedRowID()
+		reply["display_text"] = r.GetTitle()
+		reply["description"] = r.GetDescription()
+		reply["message_id"] = r.GetContextInfo().GetStanzaID()
+	case m.ButtonsResponseMessage != nil:
+		r := m.ButtonsResponseMessage
+		reply["type"] = msgTypeButtonsReply
+		reply["button_id"] = r.GetSelectedButtonID()
+		reply["display_text"] = r.GetSelectedDisplayText()
+		reply["message_id"] = r.GetContextInfo().GetStanzaID()
+	case m.TemplateButtonReplyMessage != nil:
+		r := m.TemplateButtonReplyMessage
+		reply["type"] = msgTypeTemplateReply
+		reply["button_id"] = r.GetSelectedID()
+		reply["button_index"] = r.GetSelectedIndex()
+		reply["display_text"] = r.GetSelectedDisplayText()
+		reply["message_id"] = r.GetContextInfo().GetStanzaID()
+	default:
+		return nil
+	}
+
+	return reply
 }
 
 func (c *Client) handleReceipt(v *events.Receipt) {
@@ -128,6 +356,10 @@ func (c *Client) handleCallOffer(v *events.CallOffer) {
 		"timestamp": v.Timestamp.Unix(),
 		"callId":    v.CallID,
 	})
+
+	if c.callCallback != nil {
+		c.callCallback(v)
+	}
 }
 
 func (c *Client) handleGroupInfo(v *events.GroupInfo) {
@@ -146,6 +378,253 @@ func (c *Client) handleJoinedGroup(v *events.JoinedGroup) {
 	})
 }
 
+func (c *Client) handleUndecryptableMessage(v *events.UndecryptableMessage) {
+	logger.Get().Warn().Str("event", "undecryptable_message").Str("id", v.Info.ID).Msg("")
+	c.emit(eventUndecryptableMessage, map[string]interface{}{
+		"from":            v.Info.Sender.String(),
+		"chat":            v.Info.Chat.String(),
+		"id":              v.Info.ID,
+		"timestamp":       v.Info.Timestamp.Unix(),
+		"isGroup":         v.Info.IsGroup,
+		"isFromMe":        v.Info.IsFromMe,
+		"isUnavailable":   v.IsUnavailable,
+		"unavailableType": string(v.UnavailableType),
+		"decryptFailMode": string(v.DecryptFailMode),
+	})
+}
+
+func (c *Client) handlePushName(v *events.PushName) {
+	c.emit(eventPushName, map[string]interface{}{
+		"jid":         v.JID.String(),
+		"oldPushName": v.OldPushName,
+		"newPushName": v.NewPushName,
+	})
+}
+
+func (c *Client) handlePicture(v *events.Picture) {
+	c.emit(eventPicture, map[string]interface{}{
+		"jid":       v.JID.String(),
+		"author":    v.Author.String(),
+		"timestamp": v.Timestamp.Unix(),
+		"remove":    v.Remove,
+		"pictureId": v.PictureID,
+	})
+}
+
+func (c *Client) handleUserAbout(v *events.UserAbout) {
+	c.emit(eventUserAbout, map[string]interface{}{
+		"jid":       v.JID.String(),
+		"status":    v.Status,
+		"timestamp": v.Timestamp.Unix(),
+	})
+}
+
+func (c *Client) handleIdentityChange(v *events.IdentityChange) {
+	c.emit(eventIdentityChange, map[string]interface{}{
+		"jid":       v.JID.String(),
+		"timestamp": v.Timestamp.Unix(),
+		"implicit":  v.Implicit,
+	})
+}
+
+func (c *Client) handleBlocklist(v *events.Blocklist) {
+	changes := make([]map[string]interface{}, 0, len(v.Changes))
+	for _, change := range v.Changes {
+		changes = append(changes, map[string]interface{}{
+			"jid":    change.JID.String(),
+			"action": string(change.Action),
+		})
+	}
+
+	c.emit(eventBlocklist, map[string]interface{}{
+		"action":  string(v.Action),
+		"changes": changes,
+	})
+}
+
+func (c *Client) handleKeepAliveTimeout(v *events.KeepAliveTimeout) {
+	logger.Get().Warn().Str("event", "keepalive_timeout").Int("errors", v.ErrorCount).Msg("")
+	c.emit(eventKeepAliveTimeout, map[string]interface{}{
+		"errorCount":  v.ErrorCount,
+		"lastSuccess": v.LastSuccess.Unix(),
+	})
+}
+
+func (c *Client) handleStreamReplaced() {
+	logger.Get().Warn().Str("event", "stream_replaced").Msg("")
+	c.emit(eventStreamReplaced, nil)
+}
+
+func (c *Client) handleTemporaryBan(v *events.TemporaryBan) {
+	logger.Get().Warn().Str("event", "temporary_ban").Str("reason", v.String()).Msg("")
+	c.emit(eventTemporaryBan, map[string]interface{}{
+		"code":    int(v.Code),
+		"reason":  v.Code.String(),
+		"expires": int64(v.Expire.Seconds()),
+	})
+}
+
+func (c *Client) handleConnectFailure(v *events.ConnectFailure) {
+	logger.Get().Warn().Str("event", "connect_failure").Str("reason", v.Reason.String()).Msg("")
+	c.emit(eventConnectFailure, map[string]interface{}{
+		"code":    int(v.Reason),
+		"reason":  v.Reason.String(),
+		"message": v.Message,
+	})
+}
+
+func (c *Client) handleCallAccept(v *events.CallAccept) {
+	c.emit(eventCallAccept, map[string]interface{}{
+		"from":      v.From.String(),
+		"timestamp": v.Timestamp.Unix(),
+		"callId":    v.CallID,
+		"platform":  v.RemotePlatform,
+		"version":   v.RemoteVersion,
+	})
+}
+
+func (c *Client) handleCallTerminate(v *events.CallTerminate) {
+	c.emit(eventCallTerminate, map[string]interface{}{
+		"from":      v.From.String(),
+		"timestamp": v.Timestamp.Unix(),
+		"callId":    v.CallID,
+		"reason":    v.Reason,
+	})
+}
+
+func (c *Client) handleNewsletterJoin(v *events.NewsletterJoin) {
+	c.emit(eventNewsletterJoin, map[string]interface{}{
+		"jid":  v.ID.String(),
+		"name": v.ThreadMeta.Name.Text,
+	})
+}
+
+func (c *Client) handleNewsletterLeave(v *events.NewsletterLeave) {
+	c.emit(eventNewsletterLeave, map[string]interface{}{
+		"jid":  v.ID.String(),
+		"role": string(v.Role),
+	})
+}
+
+func (c *Client) handleNewsletterLiveUpdate(v *events.NewsletterLiveUpdate) {
+	messages := make([]map[string]interface{}, 0, len(v.Messages))
+	for _, msg := range v.Messages {
+		messages = append(messages, map[string]interface{}{
+			"serverId":  msg.MessageServerID,
+			"id":        msg.MessageID,
+			"type":      msg.Type,
+			"timestamp": msg.Timestamp.Unix(),
+			"views":     msg.ViewsCount,
+			"reactions": msg.ReactionCounts,
+		})
+	}
+
+	c.emit(eventNewsletterLiveUpdate, map[string]interface{}{
+		"jid":       v.JID.String(),
+		"timestamp": v.Time.Unix(),
+		"messages":  messages,
+	})
+}
+
+func (c *Client) handleMute(v *events.Mute) {
+	c.emit(eventMute, map[string]interface{}{
+		"jid":          v.JID.String(),
+		"timestamp":    v.Timestamp.Unix(),
+		"muted":        v.Action.GetMuted(),
+		"muteEnd":      v.Action.GetMuteEndTimestamp(),
+		"fromFullSync": v.FromFullSync,
+	})
+}
+
+func (c *Client) handlePin(v *events.Pin) {
+	c.emit(eventPin, map[string]interface{}{
+		"jid":          v.JID.String(),
+		"timestamp":    v.Timestamp.Unix(),
+		"pinned":       v.Action.GetPinned(),
+		"fromFullSync": v.FromFullSync,
+	})
+}
+
+func (c *Client) handleArchive(v *events.Archive) {
+	c.emit(eventArchive, map[string]interface{}{
+		"jid":          v.JID.String(),
+		"timestamp":    v.Timestamp.Unix(),
+		"archived":     v.Action.GetArchived(),
+		"fromFullSync": v.FromFullSync,
+	})
+}
+
+func (c *Client) handleStar(v *events.Star) {
+	c.emit(eventStar, map[string]interface{}{
+		"chat":         v.ChatJID.String(),
+		"sender":       v.SenderJID.String(),
+		"id":           v.MessageID,
+		"isFromMe":     v.IsFromMe,
+		"timestamp":    v.Timestamp.Unix(),
+		"starred":      v.Action.GetStarred(),
+		"fromFullSync": v.FromFullSync,
+	})
+}
+
+func (c *Client) handleDeleteChat(v *events.DeleteChat) {
+	c.emit(eventDeleteChat, map[string]interface{}{
+		"jid":          v.JID.String(),
+		"timestamp":    v.Timestamp.Unix(),
+		"fromFullSync": v.FromFullSync,
+	})
+}
+
+func (c *Client) handleClearChat(v *events.ClearChat) {
+	c.emit(eventClearChat, map[string]interface{}{
+		"jid":          v.JID.String(),
+		"timestamp":    v.Timestamp.Unix(),
+		"fromFullSync": v.FromFullSync,
+	})
+}
+
+func (c *Client) handleMarkChatAsRead(v *events.MarkChatAsRead) {
+	c.emit(eventMarkChatAsRead, map[string]interface{}{
+		"jid":          v.JID.String(),
+		"timestamp":    v.Timestamp.Unix(),
+		"read":         v.Action.GetRead(),
+		"fromFullSync": v.FromFullSync,
+	})
+}
+
+// handlePrivacySettings reports privacy settings changed on another device,
+// listing the changed ones by their API name.
+func (c *Client) handlePrivacySettings(v *events.PrivacySettings) {
+	changed := make([]string, 0, 7)
+	for name, ok := range map[string]bool{
+		"lastSeen":     v.LastSeenChanged,
+		"online":       v.OnlineChanged,
+		"profile":      v.ProfileChanged,
+		"about":        v.StatusChanged,
+		"groupAdd":     v.GroupAddChanged,
+		"readReceipts": v.ReadReceiptsChanged,
+		"callAdd":      v.CallAddChanged,
+	} {
+		if ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+
+	s := v.NewSettings
+	c.emit(eventPrivacySettingsChanged, map[string]interface{}{
+		"changed": changed,
+		"settings": map[string]interface{}{
+			"lastSeen":     string(s.LastSeen),
+			"online":       string(s.Online),
+			"profile":      string(s.Profile),
+			"about":        string(s.Status),
+			"groupAdd":     string(s.GroupAdd),
+			"readReceipts": string(s.ReadReceipts),
+			"callAdd":      string(s.CallAdd),
+		},
+	})
+}
+
 func getExtendedText(evt *events.Message) string {
 	if evt.Message != nil && evt.Message.ExtendedTextMessage != nil {
 		return evt.Message.ExtendedTextMessage.GetText()
@@ -153,7 +632,54 @@ func getExtendedText(evt *events.Message) string {
 	return ""
 }
 
-func getMessageType(evt *events.Message) string {
+// MessageText returns the text body of a message: the conversation text,
+// extended text or media caption.
+func MessageText(evt *events.Message) string {
+	return messageText(evt.Message)
+}
+
+// ReplyID returns the id of the row or button chosen in a reply to a list,
+// buttons or template message, or "" for other messages.
+func ReplyID(evt *events.Message) string {
+	m := evt.Message
+	switch {
+	case m.GetListResponseMessage() != nil:
+		return m.GetListResponseMessage().GetSingleSelectReply().GetSelectedRowID()
+	case m.GetButtonsResponseMessage() != nil:
+		return m.GetButtonsResponseMessage().GetSelectedButtonID()
+	case m.GetTemplateButtonReplyMessage() != nil:
+		return m.GetTemplateButtonReplyMessage().GetSelectedID()
+	}
+	return ""
+}
+
+func messageText(m *waE2E.Message) string {
+	if m == nil {
+		return ""
+	}
+	switch {
+	case m.Conversation != nil:
+		return m.GetConversation()
+	case m.ExtendedTextMessage != nil:
+		return m.ExtendedTextMessage.GetText()
+	case m.ImageMessage != nil:
+		return m.ImageMessage.GetCaption()
+	case m.VideoMessage != nil:
+		return m.VideoMessage.GetCaption()
+	case m.DocumentMessage != nil:
+		return m.DocumentMessage.GetCaption()
+	case m.ListResponseMessage != nil:
+		return m.ListResponseMessage.GetTitle()
+	case m.ButtonsResponseMessage != nil:
+		return m.ButtonsResponseMessage.GetSelectedDisplayText()
+	case m.TemplateButtonReplyMessage != nil:
+		return m.TemplateButtonReplyMessage.GetSelectedDisplayText()
+	default:
+		return ""
+	}
+}
+
+func MessageType(evt *events.Message) string {
 	if evt.Message == nil {
 		return msgTypeUnknown
 	}
@@ -177,6 +703,20 @@ func getMessageType(evt *events.Message) string {
 		return msgTypeLocation
 	case m.ReactionMessage != nil:
 		return msgTypeReaction
+	case m.ProtocolMessage != nil && m.ProtocolMessage.GetType() == waE2E.ProtocolMessage_MESSAGE_EDIT:
+		return msgTypeEdit
+	case m.ProtocolMessage != nil && m.ProtocolMessage.GetType() == waE2E.ProtocolMessage_REVOKE:
+		return msgTypeRevoke
+	case m.PollCreationMessage != nil || m.PollCreationMessageV2 != nil || m.PollCreationMessageV3 != nil:
+		return msgTypePoll
+	case m.PollUpdateMessage != nil:
+		return msgTypePollVote
+	case m.ListResponseMessage != nil:
+		return msgTypeListReply
+	case m.ButtonsResponseMessage != nil:
+		return msgTypeButtonsReply
+	case m.TemplateButtonReplyMessage != nil:
+		return msgTypeTemplateReply
 	default:
 		return msgTypeUnknown
 	}
@@ -16,6 +16,8 @@ const (
 	sendTimeout    = 10 * time.Second
 	batchSize      = 50
 	eventAll       = "All"
+	eventAlert     = "SessionAlert"
+	alertPriority  = 10
 	componentName  = "webhook"
 )
 
@@ -23,6 +25,7 @@ type Dispatcher struct {
 	webhookRepo *repository.WebhookRepository
 	sessionRepo *repository.SessionRepository
 	sender      *Sender
+	alertURL    string
 	stopCh      chan struct{}
 	wg          sync.WaitGroup
 }
@@ -36,6 +39,12 @@ func NewDispatcher(webhookRepo *repository.WebhookRepository, sessionRepo *repos
 	}
 }
 
+// SetAlertURL sets an operator URL that receives every SessionAlert in
+// addition to the owner of the session.
+func (d *Dispatcher) SetAlertURL(url string) {
+	d.alertURL = url
+}
+
 func (d *Dispatcher) Start() {
 	d.wg.Add(1)
 	go d.processLoop()
@@ -126,6 +135,9 @@ func (d *Dispatcher) sendWebhook(event repository.WebhookEvent, url string) {
 }
 
 func (d *Dispatcher) shouldSendEvent(subscribedEvents, eventType string) bool {
+	if eventType == eventAlert {
+		return true
+	}
 	if subscribedEvents == "" {
 		return false
 	}
@@ -145,3 +157,35 @@ func (d *Dispatcher) Enqueue(userID, eventType string, data interface{}) error {
 func (d *Dispatcher) EnqueueSession(userID, sessionID, eventType string, data interface{}) error {
 	return d.webhookRepo.Create(userID, sessionID, eventType, data)
 }
+
+// EnqueueAlert queues a SessionAlert ahead of other pending events. Alerts are
+// delivered to the session webhook whatever its subscriptions, and are posted
+// to the alert URL right away when one is set.
+func (d *Dispatcher) EnqueueAlert(userID, sessionID string, data map[string]interface{}) error {
+	if d.alertURL != "" {
+		payload := &WebhookPayload{
+			Event:     eventAlert,
+			Timestamp: time.Now().Unix(),
+			Data:      withSession(data, userID, sessionID),
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+			if err := d.sender.Send(ctx, d.alertURL, payload); err != nil {
+				logger.WarnComponent(componentName).Str("session_id", sessionID).Err(err).Msg("alert send failed")
+			}
+		}()
+	}
+
+	return d.webhookRepo.CreateWithPriority(userID, sessionID, eventAlert, data, alertPriority)
+}
+
+func withSession(data map[string]interface{}, userID, sessionID string) map[string]interface{} {
+	out := make(map[string]interface{}, len(data)+2)
+	for k, v := range data {
+		out[k] = v
+	}
+	out["userId"] = userID
+	out["sessionId"] = sessionID
+	return out
+}